@@ -345,5 +345,9 @@ net_cidr=${net_cidr} svc_cidr=${svc_cidr} \
 cp ../templates/ovnkube-monitor.yaml.j2 ../yaml/ovnkube-monitor.yaml
 cp ../templates/k8s.ovn.org_egressfirewalls.yaml.j2 ../yaml/k8s.ovn.org_egressfirewalls.yaml
 cp ../templates/k8s.ovn.org_egressips.yaml.j2 ../yaml/k8s.ovn.org_egressips.yaml
+cp ../templates/k8s.ovn.org_egressqoses.yaml.j2 ../yaml/k8s.ovn.org_egressqoses.yaml
+cp ../templates/k8s.ovn.org_adminpolicybasedexternalroutes.yaml.j2 ../yaml/k8s.ovn.org_adminpolicybasedexternalroutes.yaml
+cp ../templates/k8s.ovn.org_adminnetworkpolicies.yaml.j2 ../yaml/k8s.ovn.org_adminnetworkpolicies.yaml
+cp ../templates/k8s.ovn.org_baselineadminnetworkpolicies.yaml.j2 ../yaml/k8s.ovn.org_baselineadminnetworkpolicies.yaml
 
 exit 0
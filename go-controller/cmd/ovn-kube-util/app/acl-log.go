@@ -0,0 +1,374 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+	"github.com/urfave/cli/v2"
+
+	kapi "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	listers "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	podIPIndex      = "podIP"
+	serviceVIPIndex = "serviceVIP"
+	// aclLogFollowInterval is how often the log file is polled for new lines with --follow
+	aclLogFollowInterval = 500 * time.Millisecond
+)
+
+// ACLLogCommand decodes the ACL log lines of ovn-controller into the Kubernetes objects
+// that the ACLs implement and the pods that the traffic comes from and goes to, and
+// writes them as JSON objects, one per line.
+var ACLLogCommand = cli.Command{
+	Name:  "acl-log",
+	Usage: "Decode the ovn-controller ACL logs into the Kubernetes objects they apply to",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "log-file",
+			Usage: `The ovn-controller log file, "-" for the standard input`,
+			Value: "/var/log/ovn/ovn-controller.log",
+		},
+		&cli.BoolFlag{
+			Name:  "follow",
+			Usage: "Keep decoding the lines appended to the log file, like tail -f",
+		},
+		&cli.StringFlag{
+			Name:  "k8s-kubeconfig",
+			Usage: "absolute path to the Kubernetes kubeconfig file (not required if the --k8s-apiserver, --k8s-cacert, and --k8s-token are given)",
+		},
+		&cli.StringFlag{
+			Name:  "k8s-apiserver",
+			Usage: "URL of the Kubernetes API server (not required if --k8s-kubeconfig is given)",
+		},
+		&cli.StringFlag{
+			Name:  "k8s-cacert",
+			Usage: "the absolute path to the Kubernetes API CA certificate (not required if --k8s-kubeconfig is given)",
+		},
+		&cli.StringFlag{
+			Name:  "k8s-token",
+			Usage: "the Kubernetes API authentication token (not required if --k8s-kubeconfig is given)",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		clientset, err := util.NewKubernetesClientset(&config.KubernetesConfig{
+			Kubeconfig: ctx.String("k8s-kubeconfig"),
+			APIServer:  ctx.String("k8s-apiserver"),
+			CACert:     ctx.String("k8s-cacert"),
+			Token:      ctx.String("k8s-token"),
+		})
+		if err != nil {
+			return err
+		}
+
+		factory := informers.NewSharedInformerFactory(clientset, 0)
+		podInformer := factory.Core().V1().Pods().Informer()
+		if err := podInformer.AddIndexers(cache.Indexers{podIPIndex: podIPIndexFunc}); err != nil {
+			return err
+		}
+		serviceInformer := factory.Core().V1().Services().Informer()
+		if err := serviceInformer.AddIndexers(cache.Indexers{serviceVIPIndex: serviceVIPIndexFunc}); err != nil {
+			return err
+		}
+		decoder := &aclLogDecoder{
+			pods:     podInformer.GetIndexer(),
+			services: serviceInformer.GetIndexer(),
+			policies: factory.Networking().V1().NetworkPolicies().Lister(),
+		}
+
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		factory.Start(stopCh)
+		for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+			if !synced {
+				return fmt.Errorf("failed to sync the %v informer", informerType)
+			}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		return readLogLines(ctx.String("log-file"), ctx.Bool("follow"), func(line string) error {
+			if !strings.Contains(line, "|acl_log(") {
+				return nil
+			}
+			entry, err := ovn.ParseACLLog(line)
+			if err != nil {
+				klog.Warningf("Skipping ACL log line: %v", err)
+				return nil
+			}
+			return encoder.Encode(decoder.decode(entry))
+		})
+	},
+}
+
+// aclLogRecord is a decoded ACL log line
+type aclLogRecord struct {
+	Time      string `json:"time"`
+	ACL       string `json:"acl,omitempty"`
+	Verdict   string `json:"verdict"`
+	Severity  string `json:"severity"`
+	Direction string `json:"direction,omitempty"`
+	Protocol  string `json:"protocol"`
+	// Kind, Namespace, Name, Rule and Audit describe the object that the ACL implements
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+	Audit     bool   `json:"audit,omitempty"`
+	// Policies are the NetworkPolicies of the namespace of the ACL that select
+	// the destination pod for ingress or the source pod for egress
+	Policies    []string      `json:"policies,omitempty"`
+	Source      aclLogAddress `json:"source"`
+	Destination aclLogAddress `json:"destination"`
+}
+
+type aclLogAddress struct {
+	IP        string `json:"ip,omitempty"`
+	Port      string `json:"port,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+}
+
+type aclLogDecoder struct {
+	pods     cache.Indexer
+	services cache.Indexer
+	policies listers.NetworkPolicyLister
+}
+
+func (d *aclLogDecoder) decode(entry *ovn.ACLLogEntry) *aclLogRecord {
+	record := &aclLogRecord{
+		Time:      entry.Time,
+		ACL:       entry.Name,
+		Verdict:   entry.Verdict,
+		Severity:  entry.Severity,
+		Direction: entry.Direction,
+		Protocol:  entry.Protocol,
+	}
+	srcPod := d.getPod(entry.SrcIP)
+	dstPod := d.getPod(entry.DstIP)
+	record.Source = newACLLogAddress(entry.SrcIP, entry.SrcPort, srcPod)
+	record.Destination = newACLLogAddress(entry.DstIP, entry.DstPort, dstPod)
+
+	owner := ovn.DecodeACLName(entry.Name)
+	if owner == nil {
+		return record
+	}
+	record.Kind = owner.Kind
+	record.Namespace = owner.Namespace
+	record.Name = owner.Name
+	record.Rule = owner.Rule
+	record.Audit = owner.Audit
+
+	switch owner.Kind {
+	case "NetworkPolicy":
+		if dstPod != nil && dstPod.Namespace == owner.Namespace {
+			record.Policies = append(record.Policies, d.getPolicies(dstPod, knet.PolicyTypeIngress)...)
+		}
+		if srcPod != nil && srcPod.Namespace == owner.Namespace {
+			record.Policies = append(record.Policies, d.getPolicies(srcPod, knet.PolicyTypeEgress)...)
+		}
+	case "Service":
+		if idx := strings.LastIndex(owner.Rule, ":"); idx >= 0 {
+			if svc := d.getService(owner.Rule[:idx]); svc != nil {
+				record.Namespace = svc.Namespace
+				record.Name = svc.Name
+			}
+		}
+	}
+	return record
+}
+
+func newACLLogAddress(ip, port string, pod *kapi.Pod) aclLogAddress {
+	address := aclLogAddress{IP: ip, Port: port}
+	if pod != nil {
+		address.Namespace = pod.Namespace
+		address.Pod = pod.Name
+	}
+	return address
+}
+
+// getPod returns the pod with the given IP, preferring the running pods as the
+// IPs of the completed pods may have been reused
+func (d *aclLogDecoder) getPod(ip string) *kapi.Pod {
+	if ip == "" {
+		return nil
+	}
+	objs, err := d.pods.ByIndex(podIPIndex, ip)
+	if err != nil {
+		klog.Warningf("Failed to get the pods with IP %s: %v", ip, err)
+		return nil
+	}
+	var found *kapi.Pod
+	for _, obj := range objs {
+		pod := obj.(*kapi.Pod)
+		if found == nil || (pod.Status.Phase != kapi.PodSucceeded && pod.Status.Phase != kapi.PodFailed) {
+			found = pod
+		}
+	}
+	return found
+}
+
+func (d *aclLogDecoder) getService(vip string) *kapi.Service {
+	objs, err := d.services.ByIndex(serviceVIPIndex, vip)
+	if err != nil || len(objs) == 0 {
+		return nil
+	}
+	return objs[0].(*kapi.Service)
+}
+
+// getPolicies returns the names of the NetworkPolicies of the pod's namespace
+// that select the pod for the given policy type
+func (d *aclLogDecoder) getPolicies(pod *kapi.Pod, policyType knet.PolicyType) []string {
+	policies, err := d.policies.NetworkPolicies(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.Warningf("Failed to list the NetworkPolicies of namespace %s: %v", pod.Namespace, err)
+		return nil
+	}
+	var names []string
+	for _, policy := range policies {
+		if !hasPolicyType(policy, policyType) {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		names = append(names, policy.Name)
+	}
+	return names
+}
+
+// hasPolicyType returns whether the policy applies to the given policy type,
+// defaulting as the API server does when the policy has no policyTypes
+func hasPolicyType(policy *knet.NetworkPolicy, policyType knet.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == knet.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+func podIPIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*kapi.Pod)
+	if !ok || pod.Spec.HostNetwork {
+		return nil, nil
+	}
+	var ips []string
+	for _, podIP := range pod.Status.PodIPs {
+		ips = append(ips, podIP.IP)
+	}
+	return ips, nil
+}
+
+func serviceVIPIndexFunc(obj interface{}) ([]string, error) {
+	svc, ok := obj.(*kapi.Service)
+	if !ok {
+		return nil, nil
+	}
+	var vips []string
+	for _, ip := range svc.Spec.ClusterIPs {
+		if ip != kapi.ClusterIPNone {
+			vips = append(vips, ip)
+		}
+	}
+	vips = append(vips, svc.Spec.ExternalIPs...)
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			vips = append(vips, ingress.IP)
+		}
+	}
+	return vips, nil
+}
+
+// readLogLines calls handle with each line of the log file. With follow it waits
+// for the lines appended to the file, and reopens the file when it is rotated.
+func readLogLines(path string, follow bool, handle func(string) error) error {
+	file := os.Stdin
+	if path != "-" {
+		var err error
+		if file, err = os.Open(path); err != nil {
+			return err
+		}
+		defer func() {
+			file.Close()
+		}()
+	}
+	reader := bufio.NewReader(file)
+	var partial string
+	for {
+		line, err := reader.ReadString('\n')
+		if err == nil {
+			if err := handle(partial + strings.TrimSuffix(line, "\n")); err != nil {
+				return err
+			}
+			partial = ""
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		partial += line
+		if !follow {
+			if partial != "" {
+				return handle(partial)
+			}
+			return nil
+		}
+		time.Sleep(aclLogFollowInterval)
+		if path == "-" {
+			continue
+		}
+		reopened, err := reopenRotatedFile(file, path)
+		if err != nil {
+			klog.Warningf("Failed to check if %s was rotated: %v", path, err)
+		} else if reopened != nil {
+			file.Close()
+			file = reopened
+			reader.Reset(file)
+			partial = ""
+		}
+	}
+}
+
+// reopenRotatedFile opens the file at path again when it is no longer the open
+// file or when it was truncated, and returns nil otherwise
+func reopenRotatedFile(file *os.File, path string) (*os.File, error) {
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		// the new file may not be created yet
+		return nil, nil
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !os.SameFile(fileInfo, pathInfo) {
+		return os.Open(path)
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if pathInfo.Size() < offset {
+		// truncated, read it again from the start
+		return os.Open(path)
+	}
+	return nil, nil
+}
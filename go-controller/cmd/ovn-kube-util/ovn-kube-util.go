@@ -29,6 +29,7 @@ func main() {
 		&app.BridgesToNicCommand,
 		&app.ReadinessProbeCommand,
 		&app.OvsExporterCommand,
+		&app.ACLLogCommand,
 	}
 
 	c.Before = func(ctx *cli.Context) error {
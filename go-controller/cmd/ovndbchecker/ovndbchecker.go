@@ -192,6 +192,9 @@ func runOvnKubeDBChecker(ctx *cli.Context) error {
 			KClient:              ovnClientset.KubeClient,
 			EIPClient:            ovnClientset.EgressIPClient,
 			EgressFirewallClient: ovnClientset.EgressFirewallClient,
+			EgressQoSClient:      ovnClientset.EgressQoSClient,
+			APBRouteClient:       ovnClientset.APBRouteClient,
+			ANPClient:            ovnClientset.ANPClient,
 		},
 		stopChan)
 	// run until cancelled
@@ -90,7 +90,12 @@ var (
 	}
 
 	// OVNKubernetesFeatureConfig holds OVN-Kubernetes feature enhancement config file parameters and command-line overrides
-	OVNKubernetesFeature OVNKubernetesFeatureConfig
+	OVNKubernetesFeature = OVNKubernetesFeatureConfig{
+		EgressIPReachabilityCheckInterval:    5,
+		EgressIPReachabilityCheckTimeout:     1,
+		EgressIPReachabilityFailureThreshold: 1,
+		EgressIPRebalanceInterval:            60,
+	}
 
 	// OvnNorth holds northbound OVN database client and server authentication and location details
 	OvnNorth OvnAuthConfig
@@ -218,6 +223,46 @@ type KubernetesConfig struct {
 // OVNKubernetesFeatureConfig holds OVN-Kubernetes feature enhancement config file parameters and command-line overrides
 type OVNKubernetesFeatureConfig struct {
 	EnableEgressIP bool `gcfg:"enable-egress-ip"`
+	// EgressIPNodeHealthCheckPort is the port of the health endpoint served by ovnkube-node
+	// and probed by the master to check that egress nodes are reachable. If 0, the master
+	// instead tries to connect to the TCP discard port of the nodes.
+	EgressIPNodeHealthCheckPort int `gcfg:"egressip-node-healthcheck-port"`
+	// EgressIPNodeHealthCheckCert and EgressIPNodeHealthCheckPrivKey make ovnkube-node serve
+	// the health endpoint over TLS. They must be set along with EgressIPNodeHealthCheckCACert,
+	// otherwise both the nodes and the master use plain HTTP.
+	EgressIPNodeHealthCheckCert    string `gcfg:"egressip-node-healthcheck-cert"`
+	EgressIPNodeHealthCheckPrivKey string `gcfg:"egressip-node-healthcheck-privkey"`
+	// EgressIPNodeHealthCheckCACert makes the master probe the health endpoint over TLS,
+	// verifying the node certificate with this CA
+	EgressIPNodeHealthCheckCACert string `gcfg:"egressip-node-healthcheck-cacert"`
+	// EgressIPReachabilityCheckInterval is the number of seconds between two reachability checks
+	EgressIPReachabilityCheckInterval int `gcfg:"egressip-reachability-check-interval"`
+	// EgressIPReachabilityCheckTimeout is the number of seconds after which a check fails
+	EgressIPReachabilityCheckTimeout int `gcfg:"egressip-reachability-check-timeout"`
+	// EgressIPReachabilityFailureThreshold is the number of consecutive failed checks
+	// after which a node is considered unreachable
+	EgressIPReachabilityFailureThreshold int `gcfg:"egressip-reachability-failure-threshold"`
+	// EnableEgressIPRebalance makes the master move egress IPs from the most loaded egress
+	// nodes to the least loaded ones, e.g. when a node recovers or is newly labeled
+	EnableEgressIPRebalance bool `gcfg:"enable-egress-ip-rebalance"`
+	// EgressIPRebalanceInterval is the minimum number of seconds between two egress IP moves
+	EgressIPRebalanceInterval int `gcfg:"egressip-rebalance-interval"`
+	// EgressIPInterfaces is a comma separated list of secondary host interfaces on which
+	// ovnkube-node lets egress IPs be assigned, in addition to the primary interface
+	EgressIPInterfaces string `gcfg:"egressip-interfaces"`
+	// EgressIPNodeCapacity is the maximum number of egress IPs ovnkube-node publishes its node
+	// can host, e.g. the number of secondary IPs of a cloud VM. 0 publishes no capacity.
+	EgressIPNodeCapacity int `gcfg:"egressip-node-capacity"`
+	// RawEgressIPExcludedDestinations holds the unparsed destination CIDRs which the traffic of
+	// egress pods reaches with the node IP rather than an egress IP. Should only be used inside
+	// config module.
+	RawEgressIPExcludedDestinations string `gcfg:"egressip-excluded-destinations"`
+	// EgressIPExcludedDestinations holds the parsed destination CIDRs excluded from egress IPs
+	// and may be used outside the config module.
+	EgressIPExcludedDestinations []*net.IPNet
+	// EnableEgressService lets the endpoints of LoadBalancer services annotated as egress
+	// services egress the cluster through a host node, with the service ingress IP
+	EnableEgressService bool `gcfg:"enable-egress-service"`
 }
 
 // GatewayMode holds the node gateway mode
@@ -620,6 +665,79 @@ var OVNK8sFeatureFlags = []cli.Flag{
 		Destination: &cliConfig.OVNKubernetesFeature.EnableEgressIP,
 		Value:       OVNKubernetesFeature.EnableEgressIP,
 	},
+	&cli.IntFlag{
+		Name:        "egressip-node-healthcheck-port",
+		Usage:       "Port of the health endpoint served by ovnkube-node for EgressIP reachability checks (default 0, check the TCP discard port instead)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
+		Value:       OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
+	},
+	&cli.StringFlag{
+		Name:        "egressip-node-healthcheck-cert",
+		Usage:       "Certificate ovnkube-node serves the EgressIP health endpoint with",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeHealthCheckCert,
+	},
+	&cli.StringFlag{
+		Name:        "egressip-node-healthcheck-privkey",
+		Usage:       "Private key ovnkube-node serves the EgressIP health endpoint with",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeHealthCheckPrivKey,
+	},
+	&cli.StringFlag{
+		Name:        "egressip-node-healthcheck-cacert",
+		Usage:       "CA certificate the master verifies the EgressIP health endpoint of the nodes with",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeHealthCheckCACert,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-reachability-check-interval",
+		Usage:       "Number of seconds between two reachability checks of the egress nodes (default 5)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPReachabilityCheckInterval,
+		Value:       OVNKubernetesFeature.EgressIPReachabilityCheckInterval,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-reachability-check-timeout",
+		Usage:       "Number of seconds after which a reachability check of an egress node fails (default 1)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPReachabilityCheckTimeout,
+		Value:       OVNKubernetesFeature.EgressIPReachabilityCheckTimeout,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-reachability-failure-threshold",
+		Usage:       "Number of consecutive failed reachability checks after which an egress node is considered unreachable (default 1)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPReachabilityFailureThreshold,
+		Value:       OVNKubernetesFeature.EgressIPReachabilityFailureThreshold,
+	},
+	&cli.BoolFlag{
+		Name:        "enable-egress-ip-rebalance",
+		Usage:       "Move egress IPs between egress nodes to spread them evenly, e.g. when a node recovers or is newly labeled.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableEgressIPRebalance,
+		Value:       OVNKubernetesFeature.EnableEgressIPRebalance,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-rebalance-interval",
+		Usage:       "Minimum number of seconds between two egress IP moves of the rebalancer (default 60)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPRebalanceInterval,
+		Value:       OVNKubernetesFeature.EgressIPRebalanceInterval,
+	},
+	&cli.StringFlag{
+		Name:        "egressip-interfaces",
+		Usage:       "Comma separated list of secondary host interfaces of the node which can host egress IPs, e.g. eth1,eth2.100",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPInterfaces,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-node-capacity",
+		Usage:       "Maximum number of egress IPs the node can host, published for the egress IP assignment (default 0, no limit published)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeCapacity,
+		Value:       OVNKubernetesFeature.EgressIPNodeCapacity,
+	},
+	&cli.StringFlag{
+		Name:        "egressip-excluded-destinations",
+		Usage:       "Comma separated list of destination CIDRs which egress pods reach with the node IP rather than their egress IP, e.g. internal datacenter ranges",
+		Destination: &cliConfig.OVNKubernetesFeature.RawEgressIPExcludedDestinations,
+	},
+	&cli.BoolFlag{
+		Name:        "enable-egress-service",
+		Usage:       "Let the endpoints of LoadBalancer services annotated as egress services egress the cluster with the service ingress IP. Requires --enable-egress-ip.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableEgressService,
+		Value:       OVNKubernetesFeature.EnableEgressService,
+	},
 }
 
 // K8sFlags capture Kubernetes-related options
@@ -1155,6 +1273,53 @@ func buildOVNKubernetesFeatureConfig(ctx *cli.Context, cli, file *config) error
 	if err := overrideFields(&OVNKubernetesFeature, &cli.OVNKubernetesFeature, &savedOVNKubernetesFeature); err != nil {
 		return err
 	}
+
+	if OVNKubernetesFeature.EgressIPNodeHealthCheckPort < 0 || OVNKubernetesFeature.EgressIPNodeHealthCheckPort > 65535 {
+		return fmt.Errorf("invalid egressip-node-healthcheck-port %d", OVNKubernetesFeature.EgressIPNodeHealthCheckPort)
+	}
+	if (OVNKubernetesFeature.EgressIPNodeHealthCheckCert == "") != (OVNKubernetesFeature.EgressIPNodeHealthCheckPrivKey == "") {
+		return fmt.Errorf("egressip-node-healthcheck-cert and egressip-node-healthcheck-privkey must be set together")
+	}
+	// the nodes serve the health endpoint over TLS if and only if the master probes it over TLS
+	if (OVNKubernetesFeature.EgressIPNodeHealthCheckCert == "") != (OVNKubernetesFeature.EgressIPNodeHealthCheckCACert == "") {
+		return fmt.Errorf("egressip-node-healthcheck-cert and egressip-node-healthcheck-cacert must be set together")
+	}
+	if OVNKubernetesFeature.EgressIPReachabilityCheckInterval <= 0 {
+		return fmt.Errorf("invalid egressip-reachability-check-interval %d, must be positive",
+			OVNKubernetesFeature.EgressIPReachabilityCheckInterval)
+	}
+	if OVNKubernetesFeature.EgressIPReachabilityCheckTimeout <= 0 {
+		return fmt.Errorf("invalid egressip-reachability-check-timeout %d, must be positive",
+			OVNKubernetesFeature.EgressIPReachabilityCheckTimeout)
+	}
+	if OVNKubernetesFeature.EgressIPReachabilityFailureThreshold <= 0 {
+		return fmt.Errorf("invalid egressip-reachability-failure-threshold %d, must be positive",
+			OVNKubernetesFeature.EgressIPReachabilityFailureThreshold)
+	}
+	if OVNKubernetesFeature.EgressIPRebalanceInterval <= 0 {
+		return fmt.Errorf("invalid egressip-rebalance-interval %d, must be positive",
+			OVNKubernetesFeature.EgressIPRebalanceInterval)
+	}
+	if OVNKubernetesFeature.EgressIPNodeCapacity < 0 {
+		return fmt.Errorf("invalid egressip-node-capacity %d, must not be negative",
+			OVNKubernetesFeature.EgressIPNodeCapacity)
+	}
+	OVNKubernetesFeature.EgressIPExcludedDestinations = nil
+	for _, destination := range strings.Split(OVNKubernetesFeature.RawEgressIPExcludedDestinations, ",") {
+		destination = strings.TrimSpace(destination)
+		if destination == "" {
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(destination)
+		if err != nil {
+			return fmt.Errorf("invalid egressip-excluded-destinations %q: %v", destination, err)
+		}
+		OVNKubernetesFeature.EgressIPExcludedDestinations = append(OVNKubernetesFeature.EgressIPExcludedDestinations, cidr)
+	}
+	// egress services rely on the no-reroute logical router policies of egress IPs
+	if OVNKubernetesFeature.EnableEgressService && !OVNKubernetesFeature.EnableEgressIP {
+		return fmt.Errorf("enable-egress-service requires enable-egress-ip")
+	}
 	return nil
 }
 
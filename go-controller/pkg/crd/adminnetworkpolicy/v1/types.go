@@ -0,0 +1,220 @@
+package v1
+
+import (
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +resource:path=adminnetworkpolicy
+// +kubebuilder:resource:shortName=anp,scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.status"
+// AdminNetworkPolicy is a CRD allowing the cluster administrator to allow,
+// deny or pass the traffic of the selected pods ahead of the NetworkPolicies
+// of their namespace, which can not override it.
+type AdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of AdminNetworkPolicy.
+	Spec AdminNetworkPolicySpec `json:"spec"`
+	// Observed status of AdminNetworkPolicy. Read-only.
+	// +optional
+	Status AdminNetworkPolicyStatus `json:"status,omitempty"`
+}
+
+// AdminNetworkPolicySpec is a desired state description of AdminNetworkPolicy.
+type AdminNetworkPolicySpec struct {
+	// Priority is the precedence of the policy over the other
+	// AdminNetworkPolicies, the lower the value the higher the precedence.
+	// The precedence of policies of the same priority is undefined.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	Priority int32 `json:"priority"`
+	// Subject selects the pods the policy applies to.
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// Ingress is the list of the rules applied to the traffic received by the
+	// subject pods, in decreasing order of precedence. The first rule matching
+	// the traffic applies.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Ingress []AdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress is the list of the rules applied to the traffic sent by the
+	// subject pods, in decreasing order of precedence. The first rule matching
+	// the traffic applies.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Egress []AdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// AdminNetworkPolicySubject selects the pods an admin policy applies to.
+type AdminNetworkPolicySubject struct {
+	// NamespaceSelector applies the policy only to the pods of the namespace(s)
+	// whose label matches this definition. This field is mandatory.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// PodSelector applies the policy only to the pods whose label matches this
+	// definition. This field is optional, and in case it is not set: results in
+	// the policy being applied to all pods in the namespace(s) matched by the
+	// NamespaceSelector.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// AdminNetworkPolicyRuleAction is the action applied to the traffic matching a rule.
+// +kubebuilder:validation:Enum=Allow;Deny;Pass
+type AdminNetworkPolicyRuleAction string
+
+const (
+	// AdminNetworkPolicyRuleActionAllow allows the traffic, whatever the
+	// NetworkPolicies of the namespace of the pods.
+	AdminNetworkPolicyRuleActionAllow AdminNetworkPolicyRuleAction = "Allow"
+	// AdminNetworkPolicyRuleActionDeny drops the traffic, whatever the
+	// NetworkPolicies of the namespace of the pods.
+	AdminNetworkPolicyRuleActionDeny AdminNetworkPolicyRuleAction = "Deny"
+	// AdminNetworkPolicyRuleActionPass skips the AdminNetworkPolicy rules of
+	// lower precedence, leaving the traffic to the NetworkPolicies of the
+	// namespace of the pods and then to the BaselineAdminNetworkPolicy. It is
+	// not supported by the BaselineAdminNetworkPolicy.
+	AdminNetworkPolicyRuleActionPass AdminNetworkPolicyRuleAction = "Pass"
+)
+
+// AdminNetworkPolicyIngressRule is a rule applied to the traffic received by
+// the subject pods from the pods of its peers.
+type AdminNetworkPolicyIngressRule struct {
+	// Name is an identifier for the rule, shown in the logs of its ACLs.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Action is the action applied to the matching traffic.
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	// From is the list of the peers sending the traffic.
+	// +kubebuilder:validation:MinItems=1
+	From []AdminNetworkPolicyPeer `json:"from"`
+	// Ports restricts the rule to the traffic to these ports of the subject
+	// pods. The rule applies to all the ports when it is not set.
+	// +optional
+	Ports []AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyEgressRule is a rule applied to the traffic sent by the
+// subject pods to the pods of its peers.
+type AdminNetworkPolicyEgressRule struct {
+	// Name is an identifier for the rule, shown in the logs of its ACLs.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Action is the action applied to the matching traffic.
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	// To is the list of the peers receiving the traffic.
+	// +kubebuilder:validation:MinItems=1
+	To []AdminNetworkPolicyPeer `json:"to"`
+	// Ports restricts the rule to the traffic to these ports of the peer
+	// pods. The rule applies to all the ports when it is not set.
+	// +optional
+	Ports []AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyPeer selects the pods at the other end of the traffic of
+// a rule.
+type AdminNetworkPolicyPeer struct {
+	// NamespaceSelector selects the namespace(s) of the peer pods.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// PodSelector selects the peer pods in the selected namespace(s). All the
+	// pods of the namespace(s) are selected when it is not set.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// AdminNetworkPolicyPort is a destination port, or range of ports, of the
+// traffic of a rule.
+type AdminNetworkPolicyPort struct {
+	// Protocol is the protocol of the traffic, TCP if not set.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +optional
+	Protocol kapi.Protocol `json:"protocol,omitempty"`
+	// Port is the destination port of the traffic. All the ports of the
+	// protocol match when it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// EndPort makes the rule match the range of ports from Port to EndPort.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	EndPort int32 `json:"endPort,omitempty"`
+}
+
+// AdminNetworkPolicyStatus is the state of an AdminNetworkPolicy or of the
+// BaselineAdminNetworkPolicy
+type AdminNetworkPolicyStatus struct {
+	// Status is a summary of the state of the policy
+	// +optional
+	Status string `json:"status,omitempty"`
+	// Messages details the errors met applying the policy
+	// +optional
+	Messages []string `json:"messages,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=adminnetworkpolicy
+// AdminNetworkPolicyList is the list of AdminNetworkPolicy.
+type AdminNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of AdminNetworkPolicy.
+	Items []AdminNetworkPolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +resource:path=baselineadminnetworkpolicy
+// +kubebuilder:resource:shortName=banp,scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.status"
+// BaselineAdminNetworkPolicy is a CRD allowing the cluster administrator to
+// allow or deny the traffic of the selected pods which is not isolated by the
+// NetworkPolicies of their namespace, which can override it. It is a
+// singleton, only the BaselineAdminNetworkPolicy named "default" is applied.
+type BaselineAdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of BaselineAdminNetworkPolicy.
+	Spec BaselineAdminNetworkPolicySpec `json:"spec"`
+	// Observed status of BaselineAdminNetworkPolicy. Read-only.
+	// +optional
+	Status AdminNetworkPolicyStatus `json:"status,omitempty"`
+}
+
+// BaselineAdminNetworkPolicySpec is a desired state description of
+// BaselineAdminNetworkPolicy. Its rules do not support the Pass action.
+type BaselineAdminNetworkPolicySpec struct {
+	// Subject selects the pods the policy applies to.
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// Ingress is the list of the rules applied to the traffic received by the
+	// subject pods, in decreasing order of precedence.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Ingress []AdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress is the list of the rules applied to the traffic sent by the
+	// subject pods, in decreasing order of precedence.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Egress []AdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=baselineadminnetworkpolicy
+// BaselineAdminNetworkPolicyList is the list of BaselineAdminNetworkPolicy.
+type BaselineAdminNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of BaselineAdminNetworkPolicy.
+	Items []BaselineAdminNetworkPolicy `json:"items"`
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +resource:path=adminpolicybasedexternalroute
+// +kubebuilder:resource:shortName=apbexternalroute,scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.status"
+// +kubebuilder:printcolumn:name="Active Hops",type=string,JSONPath=".status.activeHops[*]"
+// AdminPolicyBasedExternalRoute is a CRD allowing the cluster administrator to
+// route the egress traffic of the selected pods through a set of external
+// gateways, in place of the k8s.ovn.org/routing-external-gws and
+// k8s.ovn.org/routing-namespaces annotations.
+type AdminPolicyBasedExternalRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of AdminPolicyBasedExternalRoute.
+	Spec AdminPolicyBasedExternalRouteSpec `json:"spec"`
+	// Observed status of AdminPolicyBasedExternalRoute. Read-only.
+	// +optional
+	Status AdminPolicyBasedRouteStatus `json:"status,omitempty"`
+}
+
+// AdminPolicyBasedExternalRouteSpec is a desired state description of AdminPolicyBasedExternalRoute.
+type AdminPolicyBasedExternalRouteSpec struct {
+	// From selects the pods whose egress traffic is routed through the next hops.
+	From ExternalNetworkSource `json:"from"`
+	// NextHops defines the external gateways the selected pods egress through.
+	NextHops ExternalNextHops `json:"nextHops"`
+}
+
+// ExternalNetworkSource selects the pods routed through the external gateways.
+type ExternalNetworkSource struct {
+	// NamespaceSelector applies the policy only to the pods of the namespace(s)
+	// whose label matches this definition. This field is mandatory.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// PodSelector applies the policy only to the pods whose label matches this
+	// definition. This field is optional, and in case it is not set: results in
+	// the policy being applied to all pods in the namespace(s) matched by the
+	// NamespaceSelector.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// ExternalNextHops holds the static and the dynamic next hops of the policy.
+// At least one of them has to be set.
+type ExternalNextHops struct {
+	// StaticHops is the list of the gateways given by their IP.
+	// +optional
+	StaticHops []StaticHop `json:"static,omitempty"`
+	// DynamicHops is the list of the gateways given by the pods serving them.
+	// +optional
+	DynamicHops []DynamicHop `json:"dynamic,omitempty"`
+}
+
+// StaticHop is an external gateway given by its IP.
+type StaticHop struct {
+	// IP is the IP address of the gateway. Can be IPv4 or IPv6.
+	IP string `json:"ip"`
+	// BFDEnabled enables BFD on the routes to the gateway.
+	// +optional
+	BFDEnabled bool `json:"bfdEnabled,omitempty"`
+	// BFDParameters tunes the BFD sessions to the gateway.
+	// +optional
+	BFDParameters BFDParameters `json:"bfdParameters,omitempty"`
+}
+
+// DynamicHop is a set of external gateways served by pods. The IPs of the
+// gateways are the IPs of the pods on the given network attachment, or the
+// host network IPs of the pods when no network attachment is given.
+type DynamicHop struct {
+	// NamespaceSelector selects the namespace(s) of the gateway pods.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// PodSelector selects the gateway pods in the selected namespace(s).
+	PodSelector metav1.LabelSelector `json:"podSelector"`
+	// NetworkAttachmentName is the name of the network attachment, as shown in
+	// the k8s.v1.cni.cncf.io/network-status annotation of the gateway pods,
+	// providing the IPs of the gateways.
+	// +optional
+	NetworkAttachmentName string `json:"networkAttachmentName,omitempty"`
+	// BFDEnabled enables BFD on the routes to the gateways.
+	// +optional
+	BFDEnabled bool `json:"bfdEnabled,omitempty"`
+	// BFDParameters tunes the BFD sessions to the gateways.
+	// +optional
+	BFDParameters BFDParameters `json:"bfdParameters,omitempty"`
+}
+
+// BFDParameters are the parameters of the BFD sessions to a gateway, used
+// when BFD is enabled. Unset parameters keep the OVN defaults.
+type BFDParameters struct {
+	// MinTx is the minimum interval, in milliseconds, between the BFD
+	// packets sent to the gateway.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinTx int32 `json:"minTx,omitempty"`
+	// MinRx is the minimum interval, in milliseconds, between the BFD
+	// packets the gateway may send.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinRx int32 `json:"minRx,omitempty"`
+	// DetectMult is the number of BFD packets that may be missed before the
+	// gateway is considered down.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DetectMult int32 `json:"detectMult,omitempty"`
+}
+
+// AdminPolicyBasedRouteStatus is the state of the AdminPolicyBasedExternalRoute
+type AdminPolicyBasedRouteStatus struct {
+	// Status is a summary of the state of the policy
+	// +optional
+	Status string `json:"status,omitempty"`
+	// ActiveHops is the list of the gateway IPs the selected pods are routed through
+	// +optional
+	ActiveHops []string `json:"activeHops,omitempty"`
+	// Messages details the errors met applying the policy
+	// +optional
+	Messages []string `json:"messages,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=adminpolicybasedexternalroute
+// AdminPolicyBasedExternalRouteList is the list of AdminPolicyBasedExternalRoute.
+type AdminPolicyBasedExternalRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of AdminPolicyBasedExternalRoute.
+	Items []AdminPolicyBasedExternalRoute `json:"items"`
+}
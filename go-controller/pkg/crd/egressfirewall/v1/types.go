@@ -33,8 +33,37 @@ type EgressFirewall struct {
 	Status EgressFirewallStatus `json:"status,omitempty"`
 }
 
+// EgressFirewall condition types
+const (
+	// EgressFirewallReady is true when all the rules of the EgressFirewall are applied
+	EgressFirewallReady = "Ready"
+	// EgressFirewallDegraded is true when the EgressFirewall was rejected or some of its rules are not applied
+	EgressFirewallDegraded = "Degraded"
+)
+
 type EgressFirewallStatus struct {
+	// status is a summary of the state of the EgressFirewall
 	Status string `json:"status,omitempty"`
+	// conditions describe the state of the EgressFirewall
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// rules holds the state of each rule in spec.egress
+	// +optional
+	Rules []EgressFirewallRuleStatus `json:"rules,omitempty"`
+}
+
+// EgressFirewallRuleStatus is the state of a single egressfirewall rule
+type EgressFirewallRuleStatus struct {
+	// index is the position of the rule in spec.egress
+	Index int `json:"index"`
+	// applied is true when the rule is programmed
+	Applied bool `json:"applied"`
+	// message explains why the rule is not applied
+	// +optional
+	Message string `json:"message,omitempty"`
+	// resolvedIPs is the number of IPs the dnsName of the rule currently resolves to
+	// +optional
+	ResolvedIPs *int `json:"resolvedIPs,omitempty"`
 }
 
 // EgressFirewallSpec is a desired state description of EgressFirewall.
@@ -63,15 +92,25 @@ type EgressFirewallPort struct {
 	// +kubebuilder:validation:Minimum:=1
 	// +kubebuilder:validation:Maximum:=65535
 	Port int32 `json:"port"`
+	// endPort, if set, makes the rule match the range of ports from port to endPort, inclusive.
+	// It requires port to be set and must be greater than or equal to port.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +optional
+	EndPort int32 `json:"endPort,omitempty"`
 }
 
 // EgressFirewallDestination is the endpoint that traffic is either allowed or denied to
 type EgressFirewallDestination struct {
-	// cidrSelector is the CIDR range to allow/deny traffic to. If this is set, dnsName must be unset.
+	// cidrSelector is the CIDR range to allow/deny traffic to. If this is set, dnsName and nodeSelector must be unset.
 	CIDRSelector string `json:"cidrSelector,omitempty"`
-	// dnsName is the domain name to allow/deny traffic to. If this is set, cidrSelector must be unset.
+	// dnsName is the domain name to allow/deny traffic to. If this is set, cidrSelector and nodeSelector must be unset.
 	// +kubebuilder:validation:Pattern=^([A-Za-z0-9-]+\.)*[A-Za-z0-9-]+\.?$
 	DNSName string `json:"dnsName,omitempty"`
+	// nodeSelector will allow/deny traffic to the Kubernetes node IP of selected nodes. If this is set,
+	// cidrSelector and dnsName must be unset.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,7 +31,7 @@ func (in *EgressFirewall) DeepCopyInto(out *EgressFirewall) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -55,6 +56,11 @@ func (in *EgressFirewall) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallDestination) DeepCopyInto(out *EgressFirewallDestination) {
 	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -125,7 +131,7 @@ func (in *EgressFirewallRule) DeepCopyInto(out *EgressFirewallRule) {
 		*out = make([]EgressFirewallPort, len(*in))
 		copy(*out, *in)
 	}
-	out.To = in.To
+	in.To.DeepCopyInto(&out.To)
 	return
 }
 
@@ -162,9 +168,44 @@ func (in *EgressFirewallSpec) DeepCopy() *EgressFirewallSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressFirewallRuleStatus) DeepCopyInto(out *EgressFirewallRuleStatus) {
+	*out = *in
+	if in.ResolvedIPs != nil {
+		in, out := &in.ResolvedIPs, &out.ResolvedIPs
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressFirewallRuleStatus.
+func (in *EgressFirewallRuleStatus) DeepCopy() *EgressFirewallRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressFirewallRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallStatus) DeepCopyInto(out *EgressFirewallStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]EgressFirewallRuleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -30,9 +30,32 @@ type EgressIP struct {
 	Status EgressIPStatus `json:"status,omitempty"`
 }
 
+// EgressIP condition types
+const (
+	// EgressIPAssigned is true when all the egress IPs of the EgressIP are assigned to a node
+	EgressIPAssigned = "Assigned"
+)
+
 type EgressIPStatus struct {
 	// The list of assigned egress IPs and their corresponding node assignment.
 	Items []EgressIPStatusItem `json:"items"`
+	// Conditions describe the state of the EgressIP.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The list of egress IPs which could not be assigned and the reason why.
+	// +optional
+	Unassigned []EgressIPStatusUnassignedItem `json:"unassigned,omitempty"`
+}
+
+// The status of an egress IP which could not be assigned to a node.
+type EgressIPStatusUnassignedItem struct {
+	// Unassigned egress IP
+	EgressIP string `json:"egressIP"`
+	// Reason is a CamelCase reason for the egress IP not being assigned
+	Reason string `json:"reason"`
+	// Message is a human readable explanation of the reason
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // The per node status, for those egress IPs who have been assigned.
@@ -41,6 +64,10 @@ type EgressIPStatusItem struct {
 	Node string `json:"node"`
 	// Assigned egress IP
 	EgressIP string `json:"egressIP"`
+	// Secondary host interface of the node the egress IP is assigned to,
+	// empty if the egress IP is assigned to the primary interface of the node
+	// +optional
+	Interface string `json:"interface,omitempty"`
 }
 
 // EgressIPSpec is a desired state description of EgressIP.
@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -116,6 +117,18 @@ func (in *EgressIPStatus) DeepCopyInto(out *EgressIPStatus) {
 		*out = make([]EgressIPStatusItem, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Unassigned != nil {
+		in, out := &in.Unassigned, &out.Unassigned
+		*out = make([]EgressIPStatusUnassignedItem, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -144,3 +157,19 @@ func (in *EgressIPStatusItem) DeepCopy() *EgressIPStatusItem {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressIPStatusUnassignedItem) DeepCopyInto(out *EgressIPStatusUnassignedItem) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressIPStatusUnassignedItem.
+func (in *EgressIPStatusUnassignedItem) DeepCopy() *EgressIPStatusUnassignedItem {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressIPStatusUnassignedItem)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -16,12 +16,12 @@ limitations under the License.
 
 // Code generated by lister-gen. DO NOT EDIT.
 
-package v2alpha1
+package v1
 
-// CronJobListerExpansion allows custom methods to be added to
-// CronJobLister.
-type CronJobListerExpansion interface{}
+// EgressQoSListerExpansion allows custom methods to be added to
+// EgressQoSLister.
+type EgressQoSListerExpansion interface{}
 
-// CronJobNamespaceListerExpansion allows custom methods to be added to
-// CronJobNamespaceLister.
-type CronJobNamespaceListerExpansion interface{}
+// EgressQoSNamespaceListerExpansion allows custom methods to be added to
+// EgressQoSNamespaceLister.
+type EgressQoSNamespaceListerExpansion interface{}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +resource:path=egressqos
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=eq
+// +kubebuilder:printcolumn:name="EgressQoS Status",type=string,JSONPath=".status.status"
+// EgressQoS is a CRD that allows the user to define a DSCP value
+// for pods egress traffic on its namespace to specified CIDRs.
+// Traffic from these pods will be checked against each EgressQoSRule in
+// the namespace's EgressQoS, and if there is a match the traffic is marked
+// with the relevant DSCP value.
+type EgressQoS struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of EgressQoS.
+	Spec EgressQoSSpec `json:"spec"`
+	// Observed status of EgressQoS
+	// +optional
+	Status EgressQoSStatus `json:"status,omitempty"`
+}
+
+// EgressQoSSpec is a desired state description of EgressQoS.
+type EgressQoSSpec struct {
+	// a collection of Egress QoS rule objects, the first matching rule of a
+	// packet sets its DSCP value
+	Egress []EgressQoSRule `json:"egress"`
+}
+
+// EgressQoSRule is a single Egress QoS rule object
+type EgressQoSRule struct {
+	// dscp marking value for matching pods' traffic.
+	// +kubebuilder:validation:Maximum:=63
+	// +kubebuilder:validation:Minimum:=0
+	DSCP int `json:"dscp"`
+
+	// dstCIDR specifies the destination's CIDR. Only traffic heading
+	// to this CIDR will be marked with the DSCP value.
+	// This field is optional, and in case it is not set the rule is applied
+	// to all egress traffic regardless of the destination.
+	// +optional
+	DstCIDR string `json:"dstCIDR,omitempty"`
+
+	// podSelector applies the QoS rule only to the pods in the namespace whose label
+	// matches this definition. This field is optional, and in case it is not set
+	// results in the rule being applied to all pods in the namespace.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// EgressQoSStatus is the state of the EgressQoS
+type EgressQoSStatus struct {
+	// status is a summary of the state of the EgressQoS
+	Status string `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=egressqos
+// EgressQoSList is the list of EgressQoS.
+type EgressQoSList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of EgressQoS.
+	Items []EgressQoS `json:"items"`
+}
@@ -13,10 +13,27 @@ import (
 	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
 	egressfirewallscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/scheme"
 	egressfirewallinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/informers/externalversions"
+	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/listers/egressfirewall/v1"
 
 	egressipapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned/scheme"
 	egressipinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/informers/externalversions"
+
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
+	egressqosscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/scheme"
+	egressqosinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/informers/externalversions"
+
+	adminnetworkpolicyapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	adminnetworkpolicyclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned"
+	adminnetworkpolicyscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned/scheme"
+	adminnetworkpolicyinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/informers/externalversions"
+	adminnetworkpolicylister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/listers/adminnetworkpolicy/v1"
+	adminpolicybasedrouteapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1"
+	adminpolicybasedrouteclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/clientset/versioned"
+	adminpolicybasedroutescheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/clientset/versioned/scheme"
+	adminpolicybasedrouteinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/informers/externalversions"
+	adminpolicybasedroutelister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/listers/adminpolicybasedroute/v1"
 	apiextensionsapi "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
 	apiextensionsinformerfactory "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
@@ -42,15 +59,26 @@ type WatchFactory struct {
 	// requirements with atomic accesses
 	handlerCounter uint64
 
-	iFactory    informerfactory.SharedInformerFactory
-	eipFactory  egressipinformerfactory.SharedInformerFactory
-	efFactory   egressfirewallinformerfactory.SharedInformerFactory
-	efClientset egressfirewallclientset.Interface
-	crdFactory  apiextensionsinformerfactory.SharedInformerFactory
-	informers   map[reflect.Type]*informer
+	iFactory     informerfactory.SharedInformerFactory
+	eipFactory   egressipinformerfactory.SharedInformerFactory
+	efFactory    egressfirewallinformerfactory.SharedInformerFactory
+	efClientset  egressfirewallclientset.Interface
+	eqFactory    egressqosinformerfactory.SharedInformerFactory
+	eqClientset  egressqosclientset.Interface
+	apbFactory   adminpolicybasedrouteinformerfactory.SharedInformerFactory
+	apbClientset adminpolicybasedrouteclientset.Interface
+	anpFactory   adminnetworkpolicyinformerfactory.SharedInformerFactory
+	banpFactory  adminnetworkpolicyinformerfactory.SharedInformerFactory
+	anpClientset adminnetworkpolicyclientset.Interface
+	crdFactory   apiextensionsinformerfactory.SharedInformerFactory
+	informers    map[reflect.Type]*informer
 
 	stopChan               chan struct{}
 	egressFirewallStopChan chan struct{}
+	egressQoSStopChan      chan struct{}
+	apbRouteStopChan       chan struct{}
+	anpStopChan            chan struct{}
+	banpStopChan           chan struct{}
 }
 
 // WatchFactory implements the ObjectCacheInterface interface.
@@ -79,6 +107,10 @@ var (
 	egressFirewallType reflect.Type = reflect.TypeOf(&egressfirewallapi.EgressFirewall{})
 	crdType            reflect.Type = reflect.TypeOf(&apiextensionsapi.CustomResourceDefinition{})
 	egressIPType       reflect.Type = reflect.TypeOf(&egressipapi.EgressIP{})
+	egressQoSType      reflect.Type = reflect.TypeOf(&egressqosapi.EgressQoS{})
+	apbRouteType       reflect.Type = reflect.TypeOf(&adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute{})
+	anpType            reflect.Type = reflect.TypeOf(&adminnetworkpolicyapi.AdminNetworkPolicy{})
+	banpType           reflect.Type = reflect.TypeOf(&adminnetworkpolicyapi.BaselineAdminNetworkPolicy{})
 )
 
 // NewMasterWatchFactory initializes a new watch factory for the master or master+node processes.
@@ -90,12 +122,15 @@ func NewMasterWatchFactory(ovnClientset *util.OVNClientset) (*WatchFactory, erro
 	// the downside of making it tight (like 10 minutes) is needless spinning on all resources
 	// However, AddEventHandlerWithResyncPeriod can specify a per handler resync period
 	wf := &WatchFactory{
-		iFactory:    informerfactory.NewSharedInformerFactory(ovnClientset.KubeClient, resyncInterval),
-		eipFactory:  egressipinformerfactory.NewSharedInformerFactory(ovnClientset.EgressIPClient, resyncInterval),
-		efClientset: ovnClientset.EgressFirewallClient,
-		crdFactory:  apiextensionsinformerfactory.NewSharedInformerFactory(ovnClientset.APIExtensionsClient, resyncInterval),
-		informers:   make(map[reflect.Type]*informer),
-		stopChan:    make(chan struct{}),
+		iFactory:     informerfactory.NewSharedInformerFactory(ovnClientset.KubeClient, resyncInterval),
+		eipFactory:   egressipinformerfactory.NewSharedInformerFactory(ovnClientset.EgressIPClient, resyncInterval),
+		efClientset:  ovnClientset.EgressFirewallClient,
+		eqClientset:  ovnClientset.EgressQoSClient,
+		apbClientset: ovnClientset.APBRouteClient,
+		anpClientset: ovnClientset.ANPClient,
+		crdFactory:   apiextensionsinformerfactory.NewSharedInformerFactory(ovnClientset.APIExtensionsClient, resyncInterval),
+		informers:    make(map[reflect.Type]*informer),
+		stopChan:     make(chan struct{}),
 	}
 	var err error
 
@@ -256,6 +291,20 @@ func NewNodeWatchFactory(ovnClientset *util.OVNClientset, nodeName string) (*Wat
 		}
 	}
 
+	// Egress IPs assigned to secondary host interfaces are set up by the node
+	if config.OVNKubernetesFeature.EnableEgressIP && config.OVNKubernetesFeature.EgressIPInterfaces != "" {
+		wf.informers[egressIPType], err = newInformer(egressIPType, wf.eipFactory.K8s().V1().EgressIPs().Informer())
+		if err != nil {
+			return nil, err
+		}
+		wf.eipFactory.Start(wf.stopChan)
+		for oType, synced := range wf.eipFactory.WaitForCacheSync(wf.stopChan) {
+			if !synced {
+				return nil, fmt.Errorf("error in syncing cache for %v informer", oType)
+			}
+		}
+	}
+
 	return wf, nil
 }
 
@@ -284,6 +333,106 @@ func (wf *WatchFactory) ShutdownEgressFirewallWatchFactory() {
 	wf.informers[egressFirewallType].shutdown()
 }
 
+func (wf *WatchFactory) InitializeEgressQoSWatchFactory() error {
+	err := egressqosapi.AddToScheme(egressqosscheme.Scheme)
+	if err != nil {
+		return err
+	}
+	wf.eqFactory = egressqosinformerfactory.NewSharedInformerFactory(wf.eqClientset, resyncInterval)
+	wf.informers[egressQoSType], err = newInformer(egressQoSType, wf.eqFactory.K8s().V1().EgressQoSes().Informer())
+	if err != nil {
+		return err
+	}
+	wf.egressQoSStopChan = make(chan struct{})
+	wf.eqFactory.Start(wf.egressQoSStopChan)
+	for oType, synced := range wf.eqFactory.WaitForCacheSync(wf.egressQoSStopChan) {
+		if !synced {
+			return fmt.Errorf("error in syncing cache for %v informer", oType)
+		}
+	}
+	return nil
+}
+
+func (wf *WatchFactory) ShutdownEgressQoSWatchFactory() {
+	close(wf.egressQoSStopChan)
+	wf.informers[egressQoSType].shutdown()
+}
+
+func (wf *WatchFactory) InitializeAPBRouteWatchFactory() error {
+	err := adminpolicybasedrouteapi.AddToScheme(adminpolicybasedroutescheme.Scheme)
+	if err != nil {
+		return err
+	}
+	wf.apbFactory = adminpolicybasedrouteinformerfactory.NewSharedInformerFactory(wf.apbClientset, resyncInterval)
+	wf.informers[apbRouteType], err = newInformer(apbRouteType, wf.apbFactory.K8s().V1().AdminPolicyBasedExternalRoutes().Informer())
+	if err != nil {
+		return err
+	}
+	wf.apbRouteStopChan = make(chan struct{})
+	wf.apbFactory.Start(wf.apbRouteStopChan)
+	for oType, synced := range wf.apbFactory.WaitForCacheSync(wf.apbRouteStopChan) {
+		if !synced {
+			return fmt.Errorf("error in syncing cache for %v informer", oType)
+		}
+	}
+	return nil
+}
+
+func (wf *WatchFactory) ShutdownAPBRouteWatchFactory() {
+	close(wf.apbRouteStopChan)
+	wf.informers[apbRouteType].shutdown()
+}
+
+func (wf *WatchFactory) InitializeANPWatchFactory() error {
+	err := adminnetworkpolicyapi.AddToScheme(adminnetworkpolicyscheme.Scheme)
+	if err != nil {
+		return err
+	}
+	wf.anpFactory = adminnetworkpolicyinformerfactory.NewSharedInformerFactory(wf.anpClientset, resyncInterval)
+	wf.informers[anpType], err = newInformer(anpType, wf.anpFactory.K8s().V1().AdminNetworkPolicies().Informer())
+	if err != nil {
+		return err
+	}
+	wf.anpStopChan = make(chan struct{})
+	wf.anpFactory.Start(wf.anpStopChan)
+	for oType, synced := range wf.anpFactory.WaitForCacheSync(wf.anpStopChan) {
+		if !synced {
+			return fmt.Errorf("error in syncing cache for %v informer", oType)
+		}
+	}
+	return nil
+}
+
+func (wf *WatchFactory) ShutdownANPWatchFactory() {
+	close(wf.anpStopChan)
+	wf.informers[anpType].shutdown()
+}
+
+func (wf *WatchFactory) InitializeBANPWatchFactory() error {
+	err := adminnetworkpolicyapi.AddToScheme(adminnetworkpolicyscheme.Scheme)
+	if err != nil {
+		return err
+	}
+	wf.banpFactory = adminnetworkpolicyinformerfactory.NewSharedInformerFactory(wf.anpClientset, resyncInterval)
+	wf.informers[banpType], err = newInformer(banpType, wf.banpFactory.K8s().V1().BaselineAdminNetworkPolicies().Informer())
+	if err != nil {
+		return err
+	}
+	wf.banpStopChan = make(chan struct{})
+	wf.banpFactory.Start(wf.banpStopChan)
+	for oType, synced := range wf.banpFactory.WaitForCacheSync(wf.banpStopChan) {
+		if !synced {
+			return fmt.Errorf("error in syncing cache for %v informer", oType)
+		}
+	}
+	return nil
+}
+
+func (wf *WatchFactory) ShutdownBANPWatchFactory() {
+	close(wf.banpStopChan)
+	wf.informers[banpType].shutdown()
+}
+
 func (wf *WatchFactory) Shutdown() {
 	close(wf.stopChan)
 
@@ -327,6 +476,22 @@ func getObjectMeta(objType reflect.Type, obj interface{}) (*metav1.ObjectMeta, e
 		if egressIP, ok := obj.(*egressipapi.EgressIP); ok {
 			return &egressIP.ObjectMeta, nil
 		}
+	case egressQoSType:
+		if egressQoS, ok := obj.(*egressqosapi.EgressQoS); ok {
+			return &egressQoS.ObjectMeta, nil
+		}
+	case apbRouteType:
+		if apbRoute, ok := obj.(*adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute); ok {
+			return &apbRoute.ObjectMeta, nil
+		}
+	case anpType:
+		if anp, ok := obj.(*adminnetworkpolicyapi.AdminNetworkPolicy); ok {
+			return &anp.ObjectMeta, nil
+		}
+	case banpType:
+		if banp, ok := obj.(*adminnetworkpolicyapi.BaselineAdminNetworkPolicy); ok {
+			return &banp.ObjectMeta, nil
+		}
 	}
 	return nil, fmt.Errorf("cannot get ObjectMeta from type %v", objType)
 }
@@ -446,6 +611,46 @@ func (wf *WatchFactory) RemoveEgressFirewallHandler(handler *Handler) {
 	wf.removeHandler(egressFirewallType, handler)
 }
 
+// AddEgressQoSHandler adds a handler function that will be executed on EgressQoS object changes
+func (wf *WatchFactory) AddEgressQoSHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(egressQoSType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveEgressQoSHandler removes an EgressQoS object event handler function
+func (wf *WatchFactory) RemoveEgressQoSHandler(handler *Handler) {
+	wf.removeHandler(egressQoSType, handler)
+}
+
+// AddAPBRouteHandler adds a handler function that will be executed on AdminPolicyBasedExternalRoute object changes
+func (wf *WatchFactory) AddAPBRouteHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(apbRouteType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveAPBRouteHandler removes an AdminPolicyBasedExternalRoute object event handler function
+func (wf *WatchFactory) RemoveAPBRouteHandler(handler *Handler) {
+	wf.removeHandler(apbRouteType, handler)
+}
+
+// AddANPHandler adds a handler function that will be executed on AdminNetworkPolicy object changes
+func (wf *WatchFactory) AddANPHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(anpType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveANPHandler removes an AdminNetworkPolicy object event handler function
+func (wf *WatchFactory) RemoveANPHandler(handler *Handler) {
+	wf.removeHandler(anpType, handler)
+}
+
+// AddBANPHandler adds a handler function that will be executed on BaselineAdminNetworkPolicy object changes
+func (wf *WatchFactory) AddBANPHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(banpType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveBANPHandler removes a BaselineAdminNetworkPolicy object event handler function
+func (wf *WatchFactory) RemoveBANPHandler(handler *Handler) {
+	wf.removeHandler(banpType, handler)
+}
+
 // AddCRDHandler adds a handler function that will be executed on CRD obje changes
 func (wf *WatchFactory) AddCRDHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
 	return wf.addHandler(crdType, "", nil, handlerFuncs, processExisting)
@@ -508,6 +713,36 @@ func (wf *WatchFactory) GetPods(namespace string) ([]*kapi.Pod, error) {
 	return podLister.Pods(namespace).List(labels.Everything())
 }
 
+// GetEgressFirewall returns the EgressFirewall given the namespace and name
+func (wf *WatchFactory) GetEgressFirewall(namespace, name string) (*egressfirewallapi.EgressFirewall, error) {
+	egressFirewallLister := wf.informers[egressFirewallType].lister.(egressfirewalllister.EgressFirewallLister)
+	return egressFirewallLister.EgressFirewalls(namespace).Get(name)
+}
+
+// GetAPBRoute returns the AdminPolicyBasedExternalRoute given its name
+func (wf *WatchFactory) GetAPBRoute(name string) (*adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute, error) {
+	apbRouteLister := wf.informers[apbRouteType].lister.(adminpolicybasedroutelister.AdminPolicyBasedExternalRouteLister)
+	return apbRouteLister.Get(name)
+}
+
+// GetANP returns the AdminNetworkPolicy given its name
+func (wf *WatchFactory) GetANP(name string) (*adminnetworkpolicyapi.AdminNetworkPolicy, error) {
+	anpLister := wf.informers[anpType].lister.(adminnetworkpolicylister.AdminNetworkPolicyLister)
+	return anpLister.Get(name)
+}
+
+// GetANPs returns all the AdminNetworkPolicies
+func (wf *WatchFactory) GetANPs() ([]*adminnetworkpolicyapi.AdminNetworkPolicy, error) {
+	anpLister := wf.informers[anpType].lister.(adminnetworkpolicylister.AdminNetworkPolicyLister)
+	return anpLister.List(labels.Everything())
+}
+
+// GetBANP returns the BaselineAdminNetworkPolicy given its name
+func (wf *WatchFactory) GetBANP(name string) (*adminnetworkpolicyapi.BaselineAdminNetworkPolicy, error) {
+	banpLister := wf.informers[banpType].lister.(adminnetworkpolicylister.BaselineAdminNetworkPolicyLister)
+	return banpLister.Get(name)
+}
+
 // GetNodes returns the node specs of all the nodes
 func (wf *WatchFactory) GetNodes() ([]*kapi.Node, error) {
 	nodeLister := wf.informers[nodeType].lister.(listers.NodeLister)
@@ -526,6 +761,12 @@ func (wf *WatchFactory) GetService(namespace, name string) (*kapi.Service, error
 	return serviceLister.Services(namespace).Get(name)
 }
 
+// GetServices returns all the services
+func (wf *WatchFactory) GetServices() ([]*kapi.Service, error) {
+	serviceLister := wf.informers[serviceType].lister.(listers.ServiceLister)
+	return serviceLister.List(labels.Everything())
+}
+
 // GetEndpoints returns the endpoints list in a given namespace
 func (wf *WatchFactory) GetEndpoints(namespace string) ([]*kapi.Endpoints, error) {
 	endpointsLister := wf.informers[endpointsType].lister.(listers.EndpointsLister)
@@ -13,6 +13,10 @@ import (
 	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/listers/egressfirewall/v1"
 
 	egressiplister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/listers/egressip/v1"
+	egressqoslister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/listers/egressqos/v1"
+
+	adminnetworkpolicylister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/listers/adminnetworkpolicy/v1"
+	adminpolicybasedroutelister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/listers/adminpolicybasedroute/v1"
 	apiextensionslister "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1beta1"
 
 	listers "k8s.io/client-go/listers/core/v1"
@@ -301,6 +305,14 @@ func newInformerLister(oType reflect.Type, sharedInformer cache.SharedIndexInfor
 		return apiextensionslister.NewCustomResourceDefinitionLister(sharedInformer.GetIndexer()), nil
 	case egressIPType:
 		return egressiplister.NewEgressIPLister(sharedInformer.GetIndexer()), nil
+	case egressQoSType:
+		return egressqoslister.NewEgressQoSLister(sharedInformer.GetIndexer()), nil
+	case apbRouteType:
+		return adminpolicybasedroutelister.NewAdminPolicyBasedExternalRouteLister(sharedInformer.GetIndexer()), nil
+	case anpType:
+		return adminnetworkpolicylister.NewAdminNetworkPolicyLister(sharedInformer.GetIndexer()), nil
+	case banpType:
+		return adminnetworkpolicylister.NewBaselineAdminNetworkPolicyLister(sharedInformer.GetIndexer()), nil
 	}
 
 	return nil, fmt.Errorf("cannot create lister from type %v", oType)
@@ -5,10 +5,16 @@ import (
 	"encoding/json"
 	"k8s.io/klog/v2"
 
+	adminnetworkpolicyv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	adminnetworkpolicyclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned"
+	adminpolicybasedroutev1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1"
+	adminpolicybasedrouteclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/clientset/versioned"
 	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
 	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
 	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned"
+	egressqos "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -23,8 +29,13 @@ type Interface interface {
 	SetAnnotationsOnPod(pod *kapi.Pod, annotations map[string]string) error
 	SetAnnotationsOnNode(node *kapi.Node, annotations map[string]interface{}) error
 	SetAnnotationsOnNamespace(namespace *kapi.Namespace, annotations map[string]string) error
+	SetAnnotationsOnService(namespace, serviceName string, annotations map[string]interface{}) error
 	UpdateEgressFirewall(egressfirewall *egressfirewall.EgressFirewall) error
 	UpdateEgressIP(eIP *egressipv1.EgressIP) error
+	UpdateEgressQoS(egressqos *egressqos.EgressQoS) error
+	UpdateAdminPolicyBasedExternalRoute(apbRoute *adminpolicybasedroutev1.AdminPolicyBasedExternalRoute) error
+	UpdateAdminNetworkPolicy(anp *adminnetworkpolicyv1.AdminNetworkPolicy) error
+	UpdateBaselineAdminNetworkPolicy(banp *adminnetworkpolicyv1.BaselineAdminNetworkPolicy) error
 	UpdateNodeStatus(node *kapi.Node) error
 	GetAnnotationsOnPod(namespace, name string) (map[string]string, error)
 	GetNodes() (*kapi.NodeList, error)
@@ -43,6 +54,9 @@ type Kube struct {
 	KClient              kubernetes.Interface
 	EIPClient            egressipclientset.Interface
 	EgressFirewallClient egressfirewallclientset.Interface
+	EgressQoSClient      egressqosclientset.Interface
+	APBRouteClient       adminpolicybasedrouteclientset.Interface
+	ANPClient            adminnetworkpolicyclientset.Interface
 }
 
 // SetAnnotationsOnPod takes the pod object and map of key/value string pairs to set as annotations
@@ -124,6 +138,33 @@ func (k *Kube) SetAnnotationsOnNamespace(namespace *kapi.Namespace, annotations
 	return err
 }
 
+// SetAnnotationsOnService takes a service name and a map of key/value string pairs to set as annotations,
+// a nil value removes the annotation
+func (k *Kube) SetAnnotationsOnService(namespace, serviceName string, annotations map[string]interface{}) error {
+	var err error
+	var patchData []byte
+	patch := struct {
+		Metadata map[string]interface{} `json:"metadata"`
+	}{
+		Metadata: map[string]interface{}{
+			"annotations": annotations,
+		},
+	}
+
+	klog.Infof("Setting annotations %v on service %s/%s", annotations, namespace, serviceName)
+	patchData, err = json.Marshal(&patch)
+	if err != nil {
+		klog.Errorf("Error in setting annotations on service %s/%s: %v", namespace, serviceName, err)
+		return err
+	}
+
+	_, err = k.KClient.CoreV1().Services(namespace).Patch(context.TODO(), serviceName, types.MergePatchType, patchData, metav1.PatchOptions{})
+	if err != nil {
+		klog.Errorf("Error in setting annotation on service %s/%s: %v", namespace, serviceName, err)
+	}
+	return err
+}
+
 // UpdateEgressFirewall updates the EgressFirewall with the provided EgressFirewall data
 func (k *Kube) UpdateEgressFirewall(egressfirewall *egressfirewall.EgressFirewall) error {
 	klog.Infof("Updating status on EgressFirewall %s in namespace %s", egressfirewall.Name, egressfirewall.Namespace)
@@ -131,6 +172,13 @@ func (k *Kube) UpdateEgressFirewall(egressfirewall *egressfirewall.EgressFirewal
 	return err
 }
 
+// UpdateEgressQoS updates the EgressQoS with the provided EgressQoS data
+func (k *Kube) UpdateEgressQoS(egressqos *egressqos.EgressQoS) error {
+	klog.Infof("Updating status on EgressQoS %s in namespace %s", egressqos.Name, egressqos.Namespace)
+	_, err := k.EgressQoSClient.K8sV1().EgressQoSes(egressqos.Namespace).Update(context.TODO(), egressqos, metav1.UpdateOptions{})
+	return err
+}
+
 // UpdateEgressIP updates the EgressIP with the provided EgressIP data
 func (k *Kube) UpdateEgressIP(eIP *egressipv1.EgressIP) error {
 	klog.Infof("Updating status on EgressIP %s", eIP.Name)
@@ -138,6 +186,27 @@ func (k *Kube) UpdateEgressIP(eIP *egressipv1.EgressIP) error {
 	return err
 }
 
+// UpdateAdminPolicyBasedExternalRoute updates the AdminPolicyBasedExternalRoute with the provided AdminPolicyBasedExternalRoute data
+func (k *Kube) UpdateAdminPolicyBasedExternalRoute(apbRoute *adminpolicybasedroutev1.AdminPolicyBasedExternalRoute) error {
+	klog.Infof("Updating status on AdminPolicyBasedExternalRoute %s", apbRoute.Name)
+	_, err := k.APBRouteClient.K8sV1().AdminPolicyBasedExternalRoutes().Update(context.TODO(), apbRoute, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateAdminNetworkPolicy updates the AdminNetworkPolicy with the provided AdminNetworkPolicy data
+func (k *Kube) UpdateAdminNetworkPolicy(anp *adminnetworkpolicyv1.AdminNetworkPolicy) error {
+	klog.Infof("Updating status on AdminNetworkPolicy %s", anp.Name)
+	_, err := k.ANPClient.K8sV1().AdminNetworkPolicies().Update(context.TODO(), anp, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateBaselineAdminNetworkPolicy updates the BaselineAdminNetworkPolicy with the provided BaselineAdminNetworkPolicy data
+func (k *Kube) UpdateBaselineAdminNetworkPolicy(banp *adminnetworkpolicyv1.BaselineAdminNetworkPolicy) error {
+	klog.Infof("Updating status on BaselineAdminNetworkPolicy %s", banp.Name)
+	_, err := k.ANPClient.K8sV1().BaselineAdminNetworkPolicies().Update(context.TODO(), banp, metav1.UpdateOptions{})
+	return err
+}
+
 // UpdateNodeStatus takes the node object and sets the provided update status
 func (k *Kube) UpdateNodeStatus(node *kapi.Node) error {
 	klog.Infof("Updating status on node %s", node.Name)
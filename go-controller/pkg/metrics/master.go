@@ -143,6 +143,45 @@ var metricV6AllocatedHostSubnetCount = prometheus.NewGauge(prometheus.GaugeOpts{
 	Help:      "The total number of v6 host subnets currently allocated",
 })
 
+var metricEgressIPAssignedCount = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "num_egress_ips_assigned",
+	Help:      "The number of egress IPs currently assigned to a node",
+})
+
+var metricEgressIPUnassignedCount = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "num_egress_ips_unassigned",
+	Help:      "The number of requested egress IPs which could not be assigned to a node",
+})
+
+var metricExternalGatewayBFDUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "external_gateway_bfd_up",
+	Help:      "Whether the BFD session from a gateway router to an external gateway is up (1) or not (0)"},
+	// labels
+	[]string{"gateway_router", "gateway_ip"},
+)
+
+var metricNetworkPolicyACLCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "network_policy_acls",
+	Help:      "The number of ACLs implementing the rules of a network policy"},
+	// labels
+	[]string{"namespace", "name"},
+)
+
+var metricNetworkPolicyFailedCount = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "num_network_policies_failed",
+	Help:      "The number of network policies which could not be enforced as specified",
+})
+
 var registerMasterMetricsOnce sync.Once
 var startE2ETimeStampUpdaterOnce sync.Once
 
@@ -215,6 +254,11 @@ func RegisterMasterMetrics(nbClient, sbClient goovn.Client) {
 		prometheus.MustRegister(metricV6HostSubnetCount)
 		prometheus.MustRegister(metricV4AllocatedHostSubnetCount)
 		prometheus.MustRegister(metricV6AllocatedHostSubnetCount)
+		prometheus.MustRegister(metricEgressIPAssignedCount)
+		prometheus.MustRegister(metricEgressIPUnassignedCount)
+		prometheus.MustRegister(metricExternalGatewayBFDUp)
+		prometheus.MustRegister(metricNetworkPolicyACLCount)
+		prometheus.MustRegister(metricNetworkPolicyFailedCount)
 		registerWorkqueueMetrics(MetricOvnkubeNamespace, MetricOvnkubeSubsystemMaster)
 	})
 }
@@ -285,3 +329,43 @@ func RecordSubnetCount(v4SubnetCount, v6SubnetCount float64) {
 	metricV4HostSubnetCount.Set(v4SubnetCount)
 	metricV6HostSubnetCount.Set(v6SubnetCount)
 }
+
+// RecordEgressIPCount records the number of assigned and unassigned egress IPs
+func RecordEgressIPCount(assigned, unassigned float64) {
+	metricEgressIPAssignedCount.Set(assigned)
+	metricEgressIPUnassignedCount.Set(unassigned)
+}
+
+// RecordExternalGatewayBFDStatus records whether the BFD session from the
+// gateway router to the external gateway is up
+func RecordExternalGatewayBFDStatus(gatewayRouter, gatewayIP string, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	metricExternalGatewayBFDUp.WithLabelValues(gatewayRouter, gatewayIP).Set(value)
+}
+
+// DeleteExternalGatewayBFDStatus removes the status of a BFD session which
+// does not exist anymore
+func DeleteExternalGatewayBFDStatus(gatewayRouter, gatewayIP string) {
+	metricExternalGatewayBFDUp.DeleteLabelValues(gatewayRouter, gatewayIP)
+}
+
+// RecordNetworkPolicyACLCount records the number of ACLs implementing the
+// rules of a network policy
+func RecordNetworkPolicyACLCount(namespace, name string, count int) {
+	metricNetworkPolicyACLCount.WithLabelValues(namespace, name).Set(float64(count))
+}
+
+// DeleteNetworkPolicyACLCount deletes the number of ACLs of a deleted network
+// policy
+func DeleteNetworkPolicyACLCount(namespace, name string) {
+	metricNetworkPolicyACLCount.DeleteLabelValues(namespace, name)
+}
+
+// RecordNetworkPolicyFailedCount records the number of network policies which
+// could not be enforced as specified
+func RecordNetworkPolicyFailedCount(count int) {
+	metricNetworkPolicyFailedCount.Set(float64(count))
+}
@@ -0,0 +1,118 @@
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	"k8s.io/klog/v2"
+)
+
+// egressIPHealthServer serves the health endpoint the master probes to decide whether
+// the node can host egress IPs. The node is healthy once its data path is set up and
+// as long as ovn-controller is connected to the southbound database.
+type egressIPHealthServer struct {
+	nodeName string
+	// ready is set to 1 once the management port and gateway are ready
+	ready int32
+	// isOVNControllerConnected is overridden by tests
+	isOVNControllerConnected func() bool
+
+	// the endpoint is not authenticated, so the connection status of ovn-controller
+	// is cached to run ovs-appctl at most once per egressIPHealthCacheTTL whatever
+	// the rate of the requests
+	sync.Mutex
+	connected bool
+	checkedAt time.Time
+}
+
+// egressIPHealthCacheTTL is how long the connection status of ovn-controller is cached
+const egressIPHealthCacheTTL = time.Second
+
+func newEgressIPHealthServer(nodeName string) *egressIPHealthServer {
+	return &egressIPHealthServer{
+		nodeName:                 nodeName,
+		isOVNControllerConnected: isOVNControllerConnected,
+	}
+}
+
+func (s *egressIPHealthServer) setReady() {
+	atomic.StoreInt32(&s.ready, 1)
+}
+
+func (s *egressIPHealthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&s.ready) == 0 {
+		http.Error(w, "data path is not ready", http.StatusServiceUnavailable)
+		return
+	}
+	if !s.isConnected() {
+		http.Error(w, "ovn-controller is not connected", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
+
+// isConnected returns the cached connection status of ovn-controller, refreshing it
+// when it is older than egressIPHealthCacheTTL
+func (s *egressIPHealthServer) isConnected() bool {
+	s.Lock()
+	defer s.Unlock()
+	if time.Since(s.checkedAt) >= egressIPHealthCacheTTL {
+		s.connected = s.isOVNControllerConnected()
+		s.checkedAt = time.Now()
+	}
+	return s.connected
+}
+
+// Run serves the health endpoint on the configured port until stopChan is closed
+func (s *egressIPHealthServer) Run(stopChan <-chan struct{}) {
+	mux := http.NewServeMux()
+	mux.Handle(types.EgressIPNodeHealthCheckPath, s)
+	server := &http.Server{
+		Addr:              net.JoinHostPort("", strconv.Itoa(config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort)),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-stopChan
+		server.Close()
+	}()
+
+	var err error
+	if config.OVNKubernetesFeature.EgressIPNodeHealthCheckCert != "" {
+		err = server.ListenAndServeTLS(config.OVNKubernetesFeature.EgressIPNodeHealthCheckCert,
+			config.OVNKubernetesFeature.EgressIPNodeHealthCheckPrivKey)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		klog.Errorf("Node %s: EgressIP health endpoint stopped: %v", s.nodeName, err)
+	}
+}
+
+// isOVNControllerConnected returns true if ovn-controller is connected to the southbound database
+func isOVNControllerConnected() bool {
+	runDir := util.GetOvnRunDir()
+	pid, err := ioutil.ReadFile(runDir + "ovn-controller.pid")
+	if err != nil {
+		klog.V(5).Infof("Unknown pid for ovn-controller process: %v", err)
+		return false
+	}
+	ctlFile := runDir + fmt.Sprintf("ovn-controller.%s.ctl", strings.TrimSuffix(string(pid), "\n"))
+	ret, _, err := util.RunOVSAppctl("-t", ctlFile, "connection-status")
+	if err != nil {
+		klog.V(5).Infof("Failed to get the connection status of ovn-controller: %v", err)
+		return false
+	}
+	return ret == "connected"
+}
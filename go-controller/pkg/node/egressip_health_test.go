@@ -0,0 +1,65 @@
+package node
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EgressIP health endpoint", func() {
+	var (
+		server    *egressIPHealthServer
+		connected bool
+		checks    int
+	)
+
+	BeforeEach(func() {
+		connected = true
+		checks = 0
+		server = newEgressIPHealthServer("node1")
+		server.isOVNControllerConnected = func() bool {
+			checks++
+			return connected
+		}
+	})
+
+	probe := func() int {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, types.EgressIPNodeHealthCheckPath, nil))
+		return recorder.Code
+	}
+
+	It("reports the node as not ready until its data path is ready", func() {
+		Expect(probe()).To(Equal(http.StatusServiceUnavailable))
+		server.setReady()
+		Expect(probe()).To(Equal(http.StatusOK))
+	})
+
+	It("reports the node as not ready when ovn-controller is disconnected", func() {
+		server.setReady()
+		connected = false
+		server.checkedAt = time.Time{}
+		Expect(probe()).To(Equal(http.StatusServiceUnavailable))
+		connected = true
+		server.checkedAt = time.Time{}
+		Expect(probe()).To(Equal(http.StatusOK))
+	})
+
+	It("checks the connection of ovn-controller at most once per cache period", func() {
+		server.setReady()
+		for i := 0; i < 10; i++ {
+			Expect(probe()).To(Equal(http.StatusOK))
+		}
+		Expect(checks).To(Equal(1))
+		connected = false
+		Expect(probe()).To(Equal(http.StatusOK))
+		server.checkedAt = time.Now().Add(-egressIPHealthCacheTTL)
+		Expect(probe()).To(Equal(http.StatusServiceUnavailable))
+		Expect(checks).To(Equal(2))
+	})
+})
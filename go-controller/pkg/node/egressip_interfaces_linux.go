@@ -0,0 +1,543 @@
+// +build linux
+
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	iptableEgressIPChain = "OVN-KUBE-EGRESSIP"
+	// egressIPRulePriority is the priority of the ip rules routing the traffic of egress IPs
+	// through their secondary host interface
+	egressIPRulePriority = 6000
+	// egressIPRouteTableBase plus the index of a secondary host interface is its routing table
+	egressIPRouteTableBase = 7000
+	// egressIPAddressesFile records the egress IP addresses this node added to its secondary
+	// host interfaces, so that only these are removed when they turn out to be stale
+	egressIPAddressesFile = "/var/run/ovn-kubernetes/egressip-addresses"
+)
+
+// getEgressInterfaces returns the secondary host interfaces which can host egress IPs,
+// with their IP addresses
+func getEgressInterfaces() ([]util.EgressInterface, error) {
+	egressInterfaces := []util.EgressInterface{}
+	for _, name := range strings.Split(config.OVNKubernetesFeature.EgressIPInterfaces, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		ifAddrs, err := getNetworkInterfaceIPAddresses(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the IP addresses of egress interface %s: %v", name, err)
+		}
+		egressInterface := util.EgressInterface{Name: name}
+		if v4IfAddr, _ := util.MatchIPNetFamily(false, ifAddrs); v4IfAddr != nil {
+			egressInterface.IPv4 = v4IfAddr.String()
+		}
+		if v6IfAddr, _ := util.MatchIPNetFamily(true, ifAddrs); v6IfAddr != nil {
+			egressInterface.IPv6 = v6IfAddr.String()
+		}
+		egressInterfaces = append(egressInterfaces, egressInterface)
+	}
+	return egressInterfaces, nil
+}
+
+// egressIPAssignment is an egress IP the master assigned to a secondary host interface of this node
+type egressIPAssignment struct {
+	// the egress IP, with the prefix length of the subnet of the interface
+	cidr  string
+	iface string
+	// the packet mark of the traffic of the pods of the EgressIP
+	mark int
+}
+
+// addressKey identifies the address of the egress IP on its interface in the file of the
+// addresses the node added
+func (a egressIPAssignment) addressKey() string {
+	return a.iface + " " + a.cidr
+}
+
+// egressIPInterfaceController sets up the egress IPs which the master assigns to the secondary host
+// interfaces of this node: it adds them to the interfaces, routes the traffic the master reroutes to
+// the management port with the packet mark of their EgressIP through them, and SNATs it.
+type egressIPInterfaceController struct {
+	sync.Mutex
+	nodeName   string
+	interfaces []util.EgressInterface
+	// EgressIPs by name
+	egressIPs map[string]*egressipv1.EgressIP
+	// egress IPs which are set up, by egress IP
+	assignments map[string]egressIPAssignment
+	// the addresses the node added to its interfaces, see egressIPAssignment.addressKey,
+	// persisted to addressesFile
+	addresses     sets.String
+	addressesFile string
+}
+
+func newEgressIPInterfaceController(nodeName string, interfaces []util.EgressInterface) *egressIPInterfaceController {
+	return &egressIPInterfaceController{
+		nodeName:      nodeName,
+		interfaces:    interfaces,
+		egressIPs:     make(map[string]*egressipv1.EgressIP),
+		assignments:   make(map[string]egressIPAssignment),
+		addresses:     sets.NewString(),
+		addressesFile: egressIPAddressesFile,
+	}
+}
+
+// loadAddresses reads the addresses the node added to its interfaces in a previous run
+func (c *egressIPInterfaceController) loadAddresses() error {
+	data, err := ioutil.ReadFile(c.addressesFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			c.addresses.Insert(line)
+		}
+	}
+	return nil
+}
+
+// saveAddresses persists the addresses the node added to its interfaces
+func (c *egressIPInterfaceController) saveAddresses() error {
+	if err := os.MkdirAll(filepath.Dir(c.addressesFile), 0755); err != nil {
+		return err
+	}
+	data := ""
+	for _, address := range c.addresses.List() {
+		data += address + "\n"
+	}
+	tmpFile := c.addressesFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, []byte(data), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, c.addressesFile)
+}
+
+// Run watches the EgressIPs, after removing the set up of the egress IPs which are no longer
+// assigned to this node
+func (c *egressIPInterfaceController) Run(wf factory.NodeWatchFactory) {
+	wf.AddEgressIPHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			eIP := obj.(*egressipv1.EgressIP)
+			c.Lock()
+			defer c.Unlock()
+			c.egressIPs[eIP.Name] = eIP
+			c.sync()
+		},
+		UpdateFunc: func(old, new interface{}) {
+			eIP := new.(*egressipv1.EgressIP)
+			c.Lock()
+			defer c.Unlock()
+			c.egressIPs[eIP.Name] = eIP
+			c.sync()
+		},
+		DeleteFunc: func(obj interface{}) {
+			eIP := obj.(*egressipv1.EgressIP)
+			c.Lock()
+			defer c.Unlock()
+			delete(c.egressIPs, eIP.Name)
+			c.sync()
+		},
+	}, c.syncEgressIPs)
+}
+
+// syncEgressIPs removes the egress IP set up of a previous run which is stale
+func (c *egressIPInterfaceController) syncEgressIPs(eIPs []interface{}) {
+	egressIPs := make(map[string]*egressipv1.EgressIP)
+	for _, obj := range eIPs {
+		if eIP, ok := obj.(*egressipv1.EgressIP); ok {
+			egressIPs[eIP.Name] = eIP
+		}
+	}
+	assignments := getEgressIPAssignments(c.nodeName, c.interfaces, egressIPs)
+
+	// the SNAT rules of the current assignments are re-added right away
+	for _, proto := range clusterIPTablesProtocols() {
+		ipt, err := util.GetIPTablesHelper(proto)
+		if err != nil {
+			klog.Errorf("Unable to clean up the egress IP SNAT rules: %v", err)
+			continue
+		}
+		if err := ipt.ClearChain("nat", iptableEgressIPChain); err != nil {
+			klog.Errorf("Unable to clean up the egress IP SNAT rules: %v", err)
+		}
+		jumpRule := iptRule{
+			table:    "nat",
+			chain:    "POSTROUTING",
+			args:     []string{"-j", iptableEgressIPChain},
+			protocol: proto,
+		}
+		if err := addIptRules([]iptRule{jumpRule}); err != nil {
+			klog.Errorf("Unable to set up the egress IP SNAT rules: %v", err)
+		}
+	}
+
+	rules, err := getEgressIPRules(assignments)
+	if err != nil {
+		klog.Errorf("Unable to clean up the egress IP ip rules: %v", err)
+	}
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		if rules == nil {
+			break
+		}
+		existingRules, err := netlink.RuleList(family)
+		if err != nil {
+			klog.Errorf("Unable to list the ip rules to clean up egress IPs: %v", err)
+			continue
+		}
+		for _, rule := range existingRules {
+			if isStaleEgressIPRule(rule, rules) {
+				rule := rule
+				if err := netlink.RuleDel(&rule); err != nil {
+					klog.Errorf("Unable to delete stale egress IP ip rule: %v, err: %v", rule, err)
+				}
+			}
+		}
+	}
+
+	if err := c.loadAddresses(); err != nil {
+		klog.Errorf("Unable to read the egress IP addresses of a previous run from %s, they will not be cleaned up: %v",
+			c.addressesFile, err)
+	}
+	for _, address := range c.addresses.List() {
+		if !isStaleEgressIPAddress(address, assignments) {
+			continue
+		}
+		klog.Infof("Deleting stale egress IP address: %s", address)
+		if err := deleteEgressIPAddress(address); err != nil {
+			klog.Errorf("Unable to delete stale egress IP address: %s, err: %v", address, err)
+			continue
+		}
+		c.addresses.Delete(address)
+	}
+	if err := c.saveAddresses(); err != nil {
+		klog.Errorf("Unable to save the egress IP addresses to %s: %v", c.addressesFile, err)
+	}
+
+	for _, egressInterface := range c.interfaces {
+		for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+			if !hasEgressIPAssignment(assignments, egressInterface.Name, family) {
+				if err := flushEgressIPRouteTable(egressInterface.Name, family); err != nil {
+					klog.Errorf("Unable to flush the egress IP routing table of interface: %s, err: %v", egressInterface.Name, err)
+				}
+			}
+		}
+	}
+}
+
+// isStaleEgressIPAddress returns true if the address, which the node added to one of its
+// interfaces, is no longer an egress IP assigned to that interface
+func isStaleEgressIPAddress(address string, assignments map[string]egressIPAssignment) bool {
+	for _, assignment := range assignments {
+		if assignment.addressKey() == address {
+			return false
+		}
+	}
+	return true
+}
+
+// egressIPRuleKey identifies an ip rule of the egress IPs
+type egressIPRuleKey struct {
+	family int
+	mark   int
+	table  int
+}
+
+// getEgressIPRules returns the ip rules of the egress IP assignments
+func getEgressIPRules(assignments map[string]egressIPAssignment) (map[egressIPRuleKey]bool, error) {
+	rules := make(map[egressIPRuleKey]bool)
+	for egressIP, assignment := range assignments {
+		link, err := util.GetNetLinkOps().LinkByName(assignment.iface)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get link %s: %v", assignment.iface, err)
+		}
+		rule := getEgressIPRule(assignment, link, net.ParseIP(egressIP))
+		rules[egressIPRuleKey{family: rule.Family, mark: rule.Mark, table: rule.Table}] = true
+	}
+	return rules, nil
+}
+
+// isStaleEgressIPRule returns true if the ip rule routes the traffic of an egress IP which
+// is no longer assigned, matching the rule on both its packet mark and its routing table
+func isStaleEgressIPRule(rule netlink.Rule, rules map[egressIPRuleKey]bool) bool {
+	if rule.Priority != egressIPRulePriority || rule.Table < egressIPRouteTableBase {
+		return false
+	}
+	return !rules[egressIPRuleKey{family: rule.Family, mark: rule.Mark, table: rule.Table}]
+}
+
+// hasEgressIPAssignment returns true if an egress IP of the family is assigned to the interface
+func hasEgressIPAssignment(assignments map[string]egressIPAssignment, iface string, family int) bool {
+	for egressIP, assignment := range assignments {
+		if assignment.iface == iface && utilnet.IsIPv6String(egressIP) == (family == netlink.FAMILY_V6) {
+			return true
+		}
+	}
+	return false
+}
+
+// sync sets up the egress IPs assigned to this node and removes the set up of the egress IPs
+// which are no longer, it must be called with the lock held
+func (c *egressIPInterfaceController) sync() {
+	assignments := getEgressIPAssignments(c.nodeName, c.interfaces, c.egressIPs)
+	for egressIP, assignment := range c.assignments {
+		if assignments[egressIP] == assignment {
+			continue
+		}
+		klog.Infof("Removing egress IP: %s from interface: %s", assignment.cidr, assignment.iface)
+		if err := deleteEgressIPAssignment(assignment); err != nil {
+			klog.Errorf("Unable to remove egress IP: %s from interface: %s, err: %v", assignment.cidr, assignment.iface, err)
+			continue
+		}
+		delete(c.assignments, egressIP)
+		c.addresses.Delete(assignment.addressKey())
+		if err := c.saveAddresses(); err != nil {
+			klog.Errorf("Unable to save the egress IP addresses to %s: %v", c.addressesFile, err)
+		}
+		// the routing table of the interface is flushed once it hosts no more egress IPs
+		family := netlink.FAMILY_V4
+		if utilnet.IsIPv6String(egressIP) {
+			family = netlink.FAMILY_V6
+		}
+		if !hasEgressIPAssignment(assignments, assignment.iface, family) && !hasEgressIPAssignment(c.assignments, assignment.iface, family) {
+			if err := flushEgressIPRouteTable(assignment.iface, family); err != nil {
+				klog.Errorf("Unable to flush the egress IP routing table of interface: %s, err: %v", assignment.iface, err)
+			}
+		}
+	}
+	for egressIP, assignment := range assignments {
+		if _, exists := c.assignments[egressIP]; exists {
+			continue
+		}
+		klog.Infof("Adding egress IP: %s to interface: %s", assignment.cidr, assignment.iface)
+		// the address is recorded before it is added, so that it is cleaned up even if
+		// ovnkube-node stops in between
+		c.addresses.Insert(assignment.addressKey())
+		if err := c.saveAddresses(); err != nil {
+			klog.Errorf("Unable to add egress IP: %s to interface: %s, failed to save it to %s: %v",
+				assignment.cidr, assignment.iface, c.addressesFile, err)
+			continue
+		}
+		if err := addEgressIPAssignment(assignment); err != nil {
+			klog.Errorf("Unable to add egress IP: %s to interface: %s, err: %v", assignment.cidr, assignment.iface, err)
+			continue
+		}
+		c.assignments[egressIP] = assignment
+	}
+}
+
+// getEgressIPAssignments returns the egress IPs the master assigned to the secondary host
+// interfaces of the node, by egress IP
+func getEgressIPAssignments(nodeName string, interfaces []util.EgressInterface, eIPs map[string]*egressipv1.EgressIP) map[string]egressIPAssignment {
+	assignments := make(map[string]egressIPAssignment)
+	for _, eIP := range eIPs {
+		for _, status := range eIP.Status.Items {
+			if status.Node != nodeName || status.Interface == "" {
+				continue
+			}
+			mark, err := strconv.Atoi(eIP.Annotations[types.EgressIPMarkAnnotation])
+			if err != nil {
+				klog.Errorf("EgressIP: %s has no valid packet mark, err: %v", eIP.Name, err)
+				break
+			}
+			ip := net.ParseIP(status.EgressIP)
+			if ip == nil {
+				klog.Errorf("EgressIP: %s has an invalid egress IP: %s", eIP.Name, status.EgressIP)
+				continue
+			}
+			subnet := getEgressInterfaceSubnet(interfaces, status.Interface, utilnet.IsIPv6(ip))
+			if subnet == nil || !subnet.Contains(ip) {
+				klog.Errorf("Egress IP: %s of EgressIP: %s is assigned to interface: %s, which cannot host it", status.EgressIP, eIP.Name, status.Interface)
+				continue
+			}
+			assignments[ip.String()] = egressIPAssignment{
+				cidr:  (&net.IPNet{IP: ip, Mask: subnet.Mask}).String(),
+				iface: status.Interface,
+				mark:  mark,
+			}
+		}
+	}
+	return assignments
+}
+
+func getEgressInterfaceSubnet(interfaces []util.EgressInterface, name string, wantsIPv6 bool) *net.IPNet {
+	for _, egressInterface := range interfaces {
+		if egressInterface.Name != name {
+			continue
+		}
+		ifAddr := egressInterface.IPv4
+		if wantsIPv6 {
+			ifAddr = egressInterface.IPv6
+		}
+		if _, subnet, err := net.ParseCIDR(ifAddr); err == nil {
+			return subnet
+		}
+	}
+	return nil
+}
+
+func getEgressIPSNATRule(assignment egressIPAssignment, ip net.IP) iptRule {
+	proto := iptables.ProtocolIPv4
+	if utilnet.IsIPv6(ip) {
+		proto = iptables.ProtocolIPv6
+	}
+	return iptRule{
+		table: "nat",
+		chain: iptableEgressIPChain,
+		args: []string{
+			"-m", "mark", "--mark", strconv.Itoa(assignment.mark),
+			"-o", assignment.iface,
+			"-j", "SNAT", "--to-source", ip.String(),
+		},
+		protocol: proto,
+	}
+}
+
+func getEgressIPRule(assignment egressIPAssignment, link netlink.Link, ip net.IP) *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Priority = egressIPRulePriority
+	rule.Mark = assignment.mark
+	rule.Table = egressIPRouteTableBase + link.Attrs().Index
+	rule.Family = netlink.FAMILY_V4
+	if utilnet.IsIPv6(ip) {
+		rule.Family = netlink.FAMILY_V6
+	}
+	return rule
+}
+
+func addEgressIPAssignment(assignment egressIPAssignment) error {
+	ip, subnet, err := net.ParseCIDR(assignment.cidr)
+	if err != nil {
+		return err
+	}
+	link, err := util.GetNetLinkOps().LinkByName(assignment.iface)
+	if err != nil {
+		return fmt.Errorf("failed to get link %s: %v", assignment.iface, err)
+	}
+	addr := &net.IPNet{IP: ip, Mask: subnet.Mask}
+	if exists, err := util.LinkAddrExist(link, addr); err != nil {
+		return err
+	} else if !exists {
+		if err := util.LinkAddrAdd(link, addr); err != nil {
+			return err
+		}
+	}
+
+	// the routing table of the interface holds the routes of the interface of the main table
+	rule := getEgressIPRule(assignment, link, ip)
+	routes, err := util.GetNetLinkOps().RouteListFiltered(rule.Family,
+		&netlink.Route{LinkIndex: link.Attrs().Index, Table: unix.RT_TABLE_MAIN},
+		netlink.RT_FILTER_OIF|netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return fmt.Errorf("failed to list the routes of link %s: %v", assignment.iface, err)
+	}
+	for _, route := range routes {
+		route.Table = rule.Table
+		if err := netlink.RouteReplace(&route); err != nil {
+			return fmt.Errorf("failed to add route %s to table %d: %v", route, rule.Table, err)
+		}
+	}
+	if err := netlink.RuleAdd(rule); err != nil && !strings.Contains(err.Error(), "file exists") {
+		return fmt.Errorf("failed to add ip rule %v: %v", rule, err)
+	}
+	return addIptRules([]iptRule{getEgressIPSNATRule(assignment, ip)})
+}
+
+func deleteEgressIPAssignment(assignment egressIPAssignment) error {
+	ip, subnet, err := net.ParseCIDR(assignment.cidr)
+	if err != nil {
+		return err
+	}
+	link, err := util.GetNetLinkOps().LinkByName(assignment.iface)
+	if err != nil {
+		return fmt.Errorf("failed to get link %s: %v", assignment.iface, err)
+	}
+	if err := delIptRules([]iptRule{getEgressIPSNATRule(assignment, ip)}); err != nil {
+		return err
+	}
+	rule := getEgressIPRule(assignment, link, ip)
+	if err := netlink.RuleDel(rule); err != nil && !strings.Contains(err.Error(), "no such file or directory") {
+		return fmt.Errorf("failed to delete ip rule %v: %v", rule, err)
+	}
+	addr := &net.IPNet{IP: ip, Mask: subnet.Mask}
+	if exists, err := util.LinkAddrExist(link, addr); err != nil {
+		return err
+	} else if exists {
+		if err := util.GetNetLinkOps().AddrDel(link, &netlink.Addr{IPNet: addr}); err != nil {
+			return fmt.Errorf("failed to delete address %s from link %s: %v", addr, assignment.iface, err)
+		}
+	}
+	return nil
+}
+
+// deleteEgressIPAddress deletes the address, see egressIPAssignment.addressKey, from its interface
+func deleteEgressIPAddress(address string) error {
+	fields := strings.Fields(address)
+	if len(fields) != 2 {
+		return fmt.Errorf("invalid egress IP address %q", address)
+	}
+	ip, subnet, err := net.ParseCIDR(fields[1])
+	if err != nil {
+		return err
+	}
+	link, err := util.GetNetLinkOps().LinkByName(fields[0])
+	if err != nil {
+		return fmt.Errorf("failed to get link %s: %v", fields[0], err)
+	}
+	addr := &net.IPNet{IP: ip, Mask: subnet.Mask}
+	if exists, err := util.LinkAddrExist(link, addr); err != nil {
+		return err
+	} else if exists {
+		if err := util.GetNetLinkOps().AddrDel(link, &netlink.Addr{IPNet: addr}); err != nil {
+			return fmt.Errorf("failed to delete address %s from link %s: %v", addr, fields[0], err)
+		}
+	}
+	return nil
+}
+
+// flushEgressIPRouteTable deletes the routes of the family which addEgressIPAssignment
+// copied to the routing table of the interface
+func flushEgressIPRouteTable(iface string, family int) error {
+	link, err := util.GetNetLinkOps().LinkByName(iface)
+	if err != nil {
+		return fmt.Errorf("failed to get link %s: %v", iface, err)
+	}
+	table := egressIPRouteTableBase + link.Attrs().Index
+	routes, err := util.GetNetLinkOps().RouteListFiltered(family, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return fmt.Errorf("failed to list the routes of table %d: %v", table, err)
+	}
+	for _, route := range routes {
+		route := route
+		if err := util.GetNetLinkOps().RouteDel(&route); err != nil {
+			return fmt.Errorf("failed to delete route %s from table %d: %v", route, table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// +build linux
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EgressIP secondary host interfaces", func() {
+	interfaces := []util.EgressInterface{
+		{Name: "eth1", IPv4: "10.10.0.10/24"},
+		{Name: "eth2", IPv4: "10.20.0.10/24", IPv6: "fd00:20::10/64"},
+	}
+
+	newEgressIP := func(name, mark string, items ...egressipv1.EgressIPStatusItem) *egressipv1.EgressIP {
+		eIP := &egressipv1.EgressIP{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     egressipv1.EgressIPStatus{Items: items},
+		}
+		if mark != "" {
+			eIP.Annotations = map[string]string{types.EgressIPMarkAnnotation: mark}
+		}
+		return eIP
+	}
+
+	It("returns the egress IPs assigned to the interfaces of the node", func() {
+		eIPs := map[string]*egressipv1.EgressIP{
+			"egressip1": newEgressIP("egressip1", "50000",
+				egressipv1.EgressIPStatusItem{EgressIP: "10.10.0.100", Node: "node1", Interface: "eth1"},
+				egressipv1.EgressIPStatusItem{EgressIP: "10.10.0.101", Node: "node2", Interface: "eth1"},
+			),
+			"egressip2": newEgressIP("egressip2", "50001",
+				egressipv1.EgressIPStatusItem{EgressIP: "fd00:20::100", Node: "node1", Interface: "eth2"},
+			),
+			// assigned to the primary interface, it is set up by OVN
+			"egressip3": newEgressIP("egressip3", "",
+				egressipv1.EgressIPStatusItem{EgressIP: "192.168.126.100", Node: "node1"},
+			),
+		}
+		Expect(getEgressIPAssignments("node1", interfaces, eIPs)).To(Equal(map[string]egressIPAssignment{
+			"10.10.0.100":  {cidr: "10.10.0.100/24", iface: "eth1", mark: 50000},
+			"fd00:20::100": {cidr: "fd00:20::100/64", iface: "eth2", mark: 50001},
+		}))
+	})
+
+	It("ignores egress IPs without a mark or outside of the subnet of their interface", func() {
+		eIPs := map[string]*egressipv1.EgressIP{
+			"egressip1": newEgressIP("egressip1", "",
+				egressipv1.EgressIPStatusItem{EgressIP: "10.10.0.100", Node: "node1", Interface: "eth1"},
+			),
+			"egressip2": newEgressIP("egressip2", "50001",
+				egressipv1.EgressIPStatusItem{EgressIP: "10.20.0.100", Node: "node1", Interface: "eth1"},
+				egressipv1.EgressIPStatusItem{EgressIP: "fd00:10::100", Node: "node1", Interface: "eth1"},
+			),
+		}
+		Expect(getEgressIPAssignments("node1", interfaces, eIPs)).To(BeEmpty())
+	})
+
+	It("only reports the addresses the node added for unassigned egress IPs as stale", func() {
+		assignments := map[string]egressIPAssignment{
+			"10.10.0.100": {cidr: "10.10.0.100/24", iface: "eth1", mark: 50000},
+		}
+		Expect(isStaleEgressIPAddress("eth1 10.10.0.101/24", assignments)).To(BeTrue())
+		Expect(isStaleEgressIPAddress("eth2 10.10.0.100/24", assignments)).To(BeTrue())
+		Expect(isStaleEgressIPAddress("eth1 10.10.0.100/24", assignments)).To(BeFalse())
+	})
+
+	It("only reports the ip rules of unassigned egress IPs as stale", func() {
+		rules := map[egressIPRuleKey]bool{
+			{family: netlink.FAMILY_V4, mark: 50000, table: egressIPRouteTableBase + 3}: true,
+		}
+		newRule := func(priority, mark, table int) netlink.Rule {
+			rule := netlink.NewRule()
+			rule.Family = netlink.FAMILY_V4
+			rule.Priority = priority
+			rule.Mark = mark
+			rule.Table = table
+			return *rule
+		}
+		Expect(isStaleEgressIPRule(newRule(egressIPRulePriority, 50000, egressIPRouteTableBase+3), rules)).To(BeFalse())
+		// same mark, but the routing table of another interface
+		Expect(isStaleEgressIPRule(newRule(egressIPRulePriority, 50000, egressIPRouteTableBase+4), rules)).To(BeTrue())
+		Expect(isStaleEgressIPRule(newRule(egressIPRulePriority, 50001, egressIPRouteTableBase+3), rules)).To(BeTrue())
+		// rules of other components are left alone
+		Expect(isStaleEgressIPRule(newRule(egressIPRulePriority, 50001, 254), rules)).To(BeFalse())
+		Expect(isStaleEgressIPRule(newRule(egressIPRulePriority+1, 50001, egressIPRouteTableBase+3), rules)).To(BeFalse())
+	})
+
+	It("persists the addresses the node added to its interfaces", func() {
+		dir, err := ioutil.TempDir("", "egressip")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		c := newEgressIPInterfaceController("node1", interfaces)
+		c.addressesFile = filepath.Join(dir, "run", "egressip-addresses")
+		Expect(c.loadAddresses()).To(Succeed())
+		Expect(c.addresses.List()).To(BeEmpty())
+		c.addresses.Insert("eth1 10.10.0.100/24", "eth2 fd00:20::100/64")
+		Expect(c.saveAddresses()).To(Succeed())
+
+		restarted := newEgressIPInterfaceController("node1", interfaces)
+		restarted.addressesFile = c.addressesFile
+		Expect(restarted.loadAddresses()).To(Succeed())
+		Expect(restarted.addresses.List()).To(Equal([]string{"eth1 10.10.0.100/24", "eth2 fd00:20::100/64"}))
+	})
+})
@@ -206,6 +206,13 @@ func (n *OvnNode) Start(wg *sync.WaitGroup) error {
 		return err
 	}
 
+	var egressIPHealthServer *egressIPHealthServer
+	if config.OVNKubernetesFeature.EnableEgressIP && config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort != 0 {
+		// the endpoint reports the node as not ready until its data path is
+		egressIPHealthServer = newEgressIPHealthServer(n.name)
+		go egressIPHealthServer.Run(n.stopChan)
+	}
+
 	nodeAnnotator := kube.NewNodeAnnotator(n.Kube, node)
 	waiter := newStartupWaiter()
 
@@ -220,6 +227,21 @@ func (n *OvnNode) Start(wg *sync.WaitGroup) error {
 		return err
 	}
 
+	var egressInterfaces []util.EgressInterface
+	if config.OVNKubernetesFeature.EnableEgressIP && config.OVNKubernetesFeature.EgressIPInterfaces != "" {
+		if egressInterfaces, err = getEgressInterfaces(); err != nil {
+			return err
+		}
+		if err := util.SetNodeEgressInterfaces(nodeAnnotator, egressInterfaces); err != nil {
+			return err
+		}
+	}
+	if config.OVNKubernetesFeature.EnableEgressIP && config.OVNKubernetesFeature.EgressIPNodeCapacity > 0 {
+		if err := util.SetNodeEgressIPCapacity(nodeAnnotator, config.OVNKubernetesFeature.EgressIPNodeCapacity); err != nil {
+			return err
+		}
+	}
+
 	if err := nodeAnnotator.Run(); err != nil {
 		return fmt.Errorf("failed to set node %s annotations: %v", n.name, err)
 	}
@@ -232,6 +254,12 @@ func (n *OvnNode) Start(wg *sync.WaitGroup) error {
 	}
 	go n.gateway.Run(n.stopChan, wg)
 	klog.Infof("Gateway and management port readiness took %v", time.Since(start))
+	if egressIPHealthServer != nil {
+		egressIPHealthServer.setReady()
+	}
+	if egressInterfaces != nil {
+		newEgressIPInterfaceController(n.name, egressInterfaces).Run(n.watchFactory)
+	}
 
 	if config.HybridOverlay.Enabled {
 		nodeController, err := honode.NewNode(
@@ -0,0 +1,121 @@
+package ovn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ACLLogEntry is an ACL log line of ovn-controller, e.g.
+// 2021-06-29T17:06:21.823Z|00004|acl_log(ovn_pinctrl0)|INFO|name="ns1", verdict=drop, severity=alert, direction=to-lport: icmp,...,nw_src=10.244.1.6,nw_dst=10.244.1.5,...
+type ACLLogEntry struct {
+	Time      string
+	Name      string
+	Verdict   string
+	Severity  string
+	Direction string
+	Protocol  string
+	SrcIP     string
+	DstIP     string
+	SrcPort   string
+	DstPort   string
+}
+
+// ACLOwner is the Kubernetes object that an ACL implements, decoded from the ACL name
+type ACLOwner struct {
+	// Kind is NetworkPolicy, EgressFirewall, AdminNetworkPolicy, BaselineAdminNetworkPolicy or Service
+	Kind      string
+	Namespace string
+	// Name is the name of the AdminNetworkPolicy or BaselineAdminNetworkPolicy.
+	// The ACLs of the NetworkPolicies are named after their namespace, and the
+	// load balancer reject ACLs after their VIP, so Name is empty for them.
+	Name string
+	// Rule is the rule name of an AdminNetworkPolicy, the rule index of an
+	// EgressFirewall or the <vip>:<port> of a Service
+	Rule string
+	// Audit is set for the default deny and EgressFirewall ACLs in audit mode
+	Audit bool
+}
+
+var aclLogRegex = regexp.MustCompile(`^([^|]+)\|\d+\|acl_log\([^)]*\)\|\w+\|name=("[^"]*"|[^,]*), verdict=([\w-]+), severity=(\w+)(?:, direction=([\w-]+))?: (.*)$`)
+
+// ParseACLLog parses an ACL log line of ovn-controller
+func ParseACLLog(line string) (*ACLLogEntry, error) {
+	match := aclLogRegex.FindStringSubmatch(line)
+	if match == nil {
+		return nil, fmt.Errorf("not an ACL log line: %q", line)
+	}
+	entry := &ACLLogEntry{
+		Time:      match[1],
+		Name:      strings.Trim(match[2], `"`),
+		Verdict:   match[3],
+		Severity:  match[4],
+		Direction: match[5],
+	}
+	if entry.Name == "<unnamed>" {
+		entry.Name = ""
+	}
+	fields := strings.Split(match[6], ",")
+	entry.Protocol = fields[0]
+	for _, field := range fields[1:] {
+		keyValue := strings.SplitN(field, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		switch keyValue[0] {
+		case "nw_src", "ipv6_src":
+			entry.SrcIP = keyValue[1]
+		case "nw_dst", "ipv6_dst":
+			entry.DstIP = keyValue[1]
+		case "tp_src":
+			entry.SrcPort = keyValue[1]
+		case "tp_dst":
+			entry.DstPort = keyValue[1]
+		}
+	}
+	return entry, nil
+}
+
+// DecodeACLName returns the Kubernetes object that the ACL with the given name
+// implements, nil for the unnamed ACLs. The names are truncated to 63
+// characters, so the decoded names may be truncated as well.
+func DecodeACLName(name string) *ACLOwner {
+	if name == "" {
+		return nil
+	}
+	for _, p := range []struct{ prefix, kind string }{
+		{anpKeyPrefix, "AdminNetworkPolicy"},
+		{banpKeyPrefix, "BaselineAdminNetworkPolicy"},
+	} {
+		if strings.HasPrefix(name, p.prefix) {
+			// <prefix><policy name>:<rule name>, policy names have no ':'
+			parts := strings.SplitN(strings.TrimPrefix(name, p.prefix), ":", 2)
+			owner := &ACLOwner{Kind: p.kind, Name: parts[0]}
+			if len(parts) == 2 {
+				owner.Rule = parts[1]
+			}
+			return owner
+		}
+	}
+	// namespaces have no ':', the load balancer reject ACLs are named
+	// <load balancer>-<vip>:<port>, see generateACLName
+	if idx := strings.LastIndex(name, "-"); idx >= 0 && strings.Contains(name[idx:], ":") {
+		return &ACLOwner{Kind: "Service", Rule: name[idx+1:]}
+	}
+	owner := &ACLOwner{}
+	if strings.HasPrefix(name, aclAuditNamePrefix) {
+		owner.Audit = true
+		name = strings.TrimPrefix(name, aclAuditNamePrefix)
+	}
+	// namespaces have no '_', the EgressFirewall ACLs are named
+	// <namespace>_<rule index>, see getEgressFirewallACLName
+	if idx := strings.LastIndex(name, "_"); idx >= 0 {
+		owner.Kind = "EgressFirewall"
+		owner.Namespace = name[:idx]
+		owner.Rule = name[idx+1:]
+		return owner
+	}
+	owner.Kind = "NetworkPolicy"
+	owner.Namespace = name
+	return owner
+}
@@ -0,0 +1,116 @@
+package ovn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseACLLog(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		line     string
+		expected *ACLLogEntry
+	}{
+		{
+			desc: "IPv4 TCP with direction",
+			line: `2021-06-29T17:06:21.823Z|00004|acl_log(ovn_pinctrl0)|INFO|name="namespace1", verdict=drop, severity=alert, direction=to-lport: tcp,vlan_tci=0x0000,dl_src=0a:58:0a:f4:01:06,dl_dst=0a:58:0a:f4:01:01,nw_src=10.244.1.6,nw_dst=10.244.1.5,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=45678,tp_dst=8080,tcp_flags=syn`,
+			expected: &ACLLogEntry{
+				Time:      "2021-06-29T17:06:21.823Z",
+				Name:      "namespace1",
+				Verdict:   "drop",
+				Severity:  "alert",
+				Direction: "to-lport",
+				Protocol:  "tcp",
+				SrcIP:     "10.244.1.6",
+				DstIP:     "10.244.1.5",
+				SrcPort:   "45678",
+				DstPort:   "8080",
+			},
+		},
+		{
+			desc: "IPv6 ICMP without direction",
+			line: `2021-06-29T17:06:21.823Z|00005|acl_log(ovn_pinctrl0)|INFO|name="audit_namespace1", verdict=allow, severity=info: icmp6,vlan_tci=0x0000,dl_src=0a:58:0a:f4:01:06,dl_dst=0a:58:0a:f4:01:01,ipv6_src=fd00:10:244:1::6,ipv6_dst=fd00:10:244:1::5,ipv6_label=0x00000,nw_tos=0,nw_ecn=0,nw_ttl=64,icmp_type=128,icmp_code=0`,
+			expected: &ACLLogEntry{
+				Time:     "2021-06-29T17:06:21.823Z",
+				Name:     "audit_namespace1",
+				Verdict:  "allow",
+				Severity: "info",
+				Protocol: "icmp6",
+				SrcIP:    "fd00:10:244:1::6",
+				DstIP:    "fd00:10:244:1::5",
+			},
+		},
+		{
+			desc: "unnamed ACL",
+			line: `2021-06-29T17:06:21.823Z|00006|acl_log(ovn_pinctrl0)|INFO|name="<unnamed>", verdict=drop, severity=warning, direction=to-lport: arp,vlan_tci=0x0000`,
+			expected: &ACLLogEntry{
+				Time:      "2021-06-29T17:06:21.823Z",
+				Verdict:   "drop",
+				Severity:  "warning",
+				Direction: "to-lport",
+				Protocol:  "arp",
+			},
+		},
+		{
+			desc: "not an ACL log",
+			line: `2021-06-29T17:06:21.823Z|00007|binding|INFO|Claiming lport namespace1_pod1 for this chassis.`,
+		},
+	}
+	for _, tc := range testcases {
+		entry, err := ParseACLLog(tc.line)
+		if tc.expected == nil {
+			assert.Error(t, err, tc.desc)
+			continue
+		}
+		assert.NoError(t, err, tc.desc)
+		assert.Equal(t, tc.expected, entry, tc.desc)
+	}
+}
+
+func TestDecodeACLName(t *testing.T) {
+	testcases := []struct {
+		name     string
+		expected *ACLOwner
+	}{
+		{
+			name:     "",
+			expected: nil,
+		},
+		{
+			name:     "namespace-1",
+			expected: &ACLOwner{Kind: "NetworkPolicy", Namespace: "namespace-1"},
+		},
+		{
+			name:     "audit_namespace-1",
+			expected: &ACLOwner{Kind: "NetworkPolicy", Namespace: "namespace-1", Audit: true},
+		},
+		{
+			name:     "namespace-1_3",
+			expected: &ACLOwner{Kind: "EgressFirewall", Namespace: "namespace-1", Rule: "3"},
+		},
+		{
+			name:     "audit_namespace-1_3",
+			expected: &ACLOwner{Kind: "EgressFirewall", Namespace: "namespace-1", Rule: "3", Audit: true},
+		},
+		{
+			name:     "ANP:policy.1:ingress:1",
+			expected: &ACLOwner{Kind: "AdminNetworkPolicy", Name: "policy.1", Rule: "ingress:1"},
+		},
+		{
+			name:     "BANP:default:deny-all",
+			expected: &ACLOwner{Kind: "BaselineAdminNetworkPolicy", Name: "default", Rule: "deny-all"},
+		},
+		{
+			name:     "8a86f6d8-7972-4253-b0bd-ddbef66e9303-172.30.0.10:80",
+			expected: &ACLOwner{Kind: "Service", Rule: "172.30.0.10:80"},
+		},
+		{
+			name:     "8a86f6d8-7972-4253-b0bd-ddbef66e9303-fd00:10:96::a:80",
+			expected: &ACLOwner{Kind: "Service", Rule: "fd00:10:96::a:80"},
+		},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, DecodeACLName(tc.name), tc.name)
+	}
+}
@@ -0,0 +1,209 @@
+package ovn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// aclLoggingMeterPrefix prefixes the name of the ACL logging meter of a namespace
+// with the k8s.ovn.org/acl-logging-rate-limit annotation
+const aclLoggingMeterPrefix = types.OvnACLLoggingMeter + "-"
+
+func getNamespaceACLLoggingMeter(ns string) string {
+	return aclLoggingMeterPrefix + ns
+}
+
+// getACLLoggingMeter returns the meter rate limiting the ACL logs of the namespace:
+// its own meter when it has a rate limit, the meter shared by all namespaces otherwise
+func (nsInfo *namespaceInfo) getACLLoggingMeter() string {
+	if nsInfo.aclLoggingMeter != "" {
+		return nsInfo.aclLoggingMeter
+	}
+	return types.OvnACLLoggingMeter
+}
+
+// parseACLLoggingRateLimit parses the k8s.ovn.org/acl-logging-rate-limit annotation,
+// returning 0 when it is not set
+func parseACLLoggingRateLimit(annotations map[string]string) (int, error) {
+	annotation, ok := annotations[aclLoggingRateLimitAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	rate, err := strconv.Atoi(annotation)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("invalid %s annotation value %q, must be a positive integer",
+			aclLoggingRateLimitAnnotation, annotation)
+	}
+	return rate, nil
+}
+
+// setNamespaceACLLoggingRateLimit creates, updates or deletes the ACL logging meter of
+// the namespace to rate limit its ACL logs to rate packets per second, 0 meaning the
+// namespace shares the default meter, and moves the ACLs of the namespace to the meter.
+// Caller must hold the namespace's namespaceInfo object lock.
+func (oc *Controller) setNamespaceACLLoggingRateLimit(ns string, nsInfo *namespaceInfo, rate int) error {
+	var meter string
+	if rate > 0 && oc.aclLoggingEnabled {
+		meter = getNamespaceACLLoggingMeter(ns)
+		if err := ensureACLLoggingMeter(meter, rate); err != nil {
+			return err
+		}
+	}
+	oldMeter := nsInfo.getACLLoggingMeter()
+	nsInfo.aclLoggingMeter = meter
+	newMeter := nsInfo.getACLLoggingMeter()
+	if newMeter == oldMeter {
+		return nil
+	}
+	for _, np := range nsInfo.networkPolicies {
+		np.Lock()
+		for _, gp := range np.ingressPolicies {
+			gp.aclLoggingMeter = newMeter
+		}
+		for _, gp := range np.egressPolicies {
+			gp.aclLoggingMeter = newMeter
+		}
+		np.Unlock()
+	}
+	if err := setNamespaceACLsMeter(ns, oldMeter, newMeter); err != nil {
+		return err
+	}
+	if oldMeter != types.OvnACLLoggingMeter {
+		return deleteACLLoggingMeter(oldMeter)
+	}
+	return nil
+}
+
+// ensureACLLoggingMeter creates the meter dropping the ACL logs above rate packets
+// per second, or updates its rate if it already exists
+func ensureACLLoggingMeter(meter string, rate int) error {
+	bands, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=bands", "find", "meter", "name="+meter)
+	if err != nil {
+		return fmt.Errorf("failed to find meter %s, stderr: %q (%v)", meter, stderr, err)
+	}
+	if bands == "" {
+		if _, stderr, err := util.RunOVNNbctl("meter-add", meter, "drop", strconv.Itoa(rate), "pktps"); err != nil {
+			return fmt.Errorf("failed to create meter %s, stderr: %q (%v)", meter, stderr, err)
+		}
+		return nil
+	}
+	for _, band := range strings.Fields(bands) {
+		if _, stderr, err := util.RunOVNNbctl("set", "meter_band", band, fmt.Sprintf("rate=%d", rate)); err != nil {
+			return fmt.Errorf("failed to set the rate of meter %s, stderr: %q (%v)", meter, stderr, err)
+		}
+	}
+	return nil
+}
+
+// deleteACLLoggingMeter moves the ACLs still using the meter to the default meter
+// and deletes the meter
+func deleteACLLoggingMeter(meter string) error {
+	uuids, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find", "acl", "meter="+meter)
+	if err != nil {
+		return fmt.Errorf("failed to find the ACLs of meter %s, stderr: %q (%v)", meter, stderr, err)
+	}
+	if err := setACLsMeter(strings.Fields(uuids), types.OvnACLLoggingMeter); err != nil {
+		return err
+	}
+	if _, stderr, err := util.RunOVNNbctl("meter-del", meter); err != nil {
+		return fmt.Errorf("failed to delete meter %s, stderr: %q (%v)", meter, stderr, err)
+	}
+	return nil
+}
+
+// setNamespaceACLsMeter moves the NetworkPolicy and EgressFirewall ACLs of the
+// namespace from one meter to another
+func setNamespaceACLsMeter(ns, oldMeter, newMeter string) error {
+	var uuids []string
+	for _, selector := range []string{
+		fmt.Sprintf("name=%.63s", ns),
+		fmt.Sprintf("name=%.63s", getDenyACLName(ns, true)),
+		fmt.Sprintf("external-ids:egressFirewall=%s", ns),
+		fmt.Sprintf("external-ids:egressFirewall=%s-blockAll", ns),
+	} {
+		stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find", "acl",
+			selector, "meter="+oldMeter)
+		if err != nil {
+			return fmt.Errorf("failed to find the ACLs of namespace %s, stderr: %q (%v)", ns, stderr, err)
+		}
+		uuids = append(uuids, strings.Fields(stdout)...)
+	}
+	return setACLsMeter(uuids, newMeter)
+}
+
+// setACLsMeter sets the meter of the ACLs in a single transaction
+func setACLsMeter(uuids []string, meter string) error {
+	if len(uuids) == 0 {
+		return nil
+	}
+	var args []string
+	for _, uuid := range uuids {
+		args = append(args, "--", "set", "acl", uuid, "meter="+meter)
+	}
+	if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+		return fmt.Errorf("failed to set meter %s on ACLs %v, stderr: %q (%v)", meter, uuids, stderr, err)
+	}
+	return nil
+}
+
+// syncACLLoggingMeters deletes the ACL logging meters of the namespaces that were
+// deleted, or lost their k8s.ovn.org/acl-logging-rate-limit annotation, while
+// ovnkube-master was down
+func (oc *Controller) syncACLLoggingMeters() {
+	namespaces, err := oc.watchFactory.GetNamespaces()
+	if err != nil {
+		klog.Errorf("Failed to get the namespaces to sync the ACL logging meters: %v", err)
+		return
+	}
+	expectedMeters := make(map[string]bool)
+	for _, ns := range namespaces {
+		if rate, err := parseACLLoggingRateLimit(ns.Annotations); err == nil && rate > 0 {
+			expectedMeters[getNamespaceACLLoggingMeter(ns.Name)] = true
+		}
+	}
+	meters, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=name", "find", "meter")
+	if err != nil {
+		klog.Errorf("Failed to list the meters, stderr: %q (%v)", stderr, err)
+		return
+	}
+	for _, meter := range strings.Fields(meters) {
+		if strings.HasPrefix(meter, aclLoggingMeterPrefix) && !expectedMeters[meter] {
+			if err := deleteACLLoggingMeter(meter); err != nil {
+				klog.Errorf(err.Error())
+			}
+		}
+	}
+}
+
+// updateNamespaceACLLoggingRateLimit applies a change of the
+// k8s.ovn.org/acl-logging-rate-limit annotation of the namespace.
+// Caller must hold the namespace's namespaceInfo object lock.
+func (oc *Controller) updateNamespaceACLLoggingRateLimit(ns *kapi.Namespace, nsInfo *namespaceInfo) {
+	rate, err := parseACLLoggingRateLimit(ns.Annotations)
+	if err != nil {
+		klog.Warningf("Namespace %s: %v, keeping ACL logging meter %s", ns.Name, err, nsInfo.getACLLoggingMeter())
+		nsRef := kapi.ObjectReference{
+			Kind:      "Namespace",
+			Name:      ns.Name,
+			Namespace: ns.Name,
+		}
+		oc.recorder.Eventf(&nsRef, kapi.EventTypeWarning, "InvalidACLLoggingRateLimit",
+			"%v, the ACL logs of namespace %s are still rate limited by meter %s", err, ns.Name, nsInfo.getACLLoggingMeter())
+		return
+	}
+	if err := oc.setNamespaceACLLoggingRateLimit(ns.Name, nsInfo, rate); err != nil {
+		klog.Warningf("Failed to set the ACL logging rate limit of namespace %s: %v", ns.Name, err)
+		return
+	}
+	if rate > 0 && nsInfo.aclLoggingMeter != "" {
+		klog.Infof("Namespace %s: ACL logging is rate limited to %d packets per second by meter %s",
+			ns.Name, rate, nsInfo.aclLoggingMeter)
+	}
+}
@@ -133,6 +133,25 @@ func (f *FakeAddressSetFactory) ExpectAddressSetWithIPs(name string, ips []strin
 	gomega.Expect(lenAddressSet).To(gomega.Equal(len(ips)))
 }
 
+// EventuallyExpectAddressSetWithIPs ensures the named address set eventually exists with the given set of IPs
+func (f *FakeAddressSetFactory) EventuallyExpectAddressSetWithIPs(name string, ips []string) {
+	name4, name6 := MakeAddressSetName(name)
+	gomega.Eventually(func() []string {
+		addrs := sets.NewString()
+		for _, asName := range []string{name4, name6} {
+			as := f.getAddressSet(asName)
+			if as == nil {
+				continue
+			}
+			for ip := range as.ips {
+				addrs.Insert(ip)
+			}
+			as.Unlock()
+		}
+		return addrs.List()
+	}).Should(gomega.Equal(sets.NewString(ips...).List()))
+}
+
 // ExpectEmptyAddressSet ensures the named address set exists with no IPs
 func (f *FakeAddressSetFactory) ExpectEmptyAddressSet(name string) {
 	f.ExpectAddressSetWithIPs(name, nil)
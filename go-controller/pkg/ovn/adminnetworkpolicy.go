@@ -0,0 +1,787 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	adminnetworkpolicyapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+
+	kapi "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	anpStatusSuccess = "Success"
+	anpStatusFail    = "Fail"
+	// The ACLs of the AdminNetworkPolicies are in a band of priorities above
+	// the ones of the NetworkPolicies and of the multicast policies, which
+	// they take precedence over. Each policy priority gets anpMaxRules ACL
+	// priorities, one for each rule of a direction, in decreasing order from
+	// anpMaxACLPriority for the policies of priority 0.
+	anpMaxACLPriority = 30000
+	// The ACLs of the BaselineAdminNetworkPolicy are in a band of priorities
+	// below the default deny ACLs of the NetworkPolicies, only the traffic of
+	// the pods not isolated by a NetworkPolicy reaches them.
+	banpMaxACLPriority = 999
+	// maximum number of rules in each direction of an admin policy
+	anpMaxRules = 100
+	// maximum priority of an AdminNetworkPolicy
+	anpMaxPriority = 99
+	// name of the only BaselineAdminNetworkPolicy applied
+	banpName = "default"
+	// prefixes of the keys of the admin policies, which can not collide with
+	// the namespace prefixed names of the NetworkPolicy port groups
+	anpKeyPrefix  = "ANP:"
+	banpKeyPrefix = "BANP:"
+)
+
+// adminNetworkPolicy is the state applied for an AdminNetworkPolicy or for
+// the BaselineAdminNetworkPolicy: the ports of the subject pods are in a port
+// group holding the ACLs of the rules, whose peer pods IPs are in per rule
+// address sets
+type adminNetworkPolicy struct {
+	// key is the name of the policy prefixed with the kind of policy, it is
+	// the readable name of the port group
+	key      string
+	name     string
+	baseline bool
+	// aclPriority is the priority of the ACL of the first rule of each direction
+	aclPriority   int
+	subject       podSelector
+	rules         []*anpRule
+	portGroupName string
+	portGroupUUID string
+	// logical switch port UUIDs of the subject pods in the port group, by
+	// namespace/name key
+	subjectPorts map[string]string
+	// ACLs last set on the port group
+	acls []anpACL
+}
+
+// anpRule is an ingress or egress rule of an admin policy
+type anpRule struct {
+	policyType knet.PolicyType
+	idx        int
+	name       string
+	action     adminnetworkpolicyapi.AdminNetworkPolicyRuleAction
+	peers      []podSelector
+	l4Match    string
+	addrSet    addressset.AddressSet
+	// IPs of the peer pods in the address set, by namespace/name key
+	peerPods map[string][]net.IP
+}
+
+// anpACL is an ACL of an admin policy port group
+type anpACL struct {
+	priority  int
+	direction string
+	match     string
+	action    string
+	name      string
+}
+
+func getANPKey(name string) string {
+	return anpKeyPrefix + name
+}
+
+func getBANPKey(name string) string {
+	return banpKeyPrefix + name
+}
+
+// getANPAddrSetName returns the name of the address set of the peers of a
+// rule. The dots of the policy names are replaced as address set names are
+// parsed as namespace.policy[.suffix] by the NetworkPolicy sync.
+func getANPAddrSetName(key string, policyType knet.PolicyType, idx int) string {
+	return fmt.Sprintf("%s:%s:%d", strings.ReplaceAll(key, ".", "_"), strings.ToLower(string(policyType)), idx)
+}
+
+// getANPRuleL4Match returns the match of the destination ports of a rule,
+// "" when it applies to all the ports
+func getANPRuleL4Match(ports []adminnetworkpolicyapi.AdminNetworkPolicyPort) (string, error) {
+	var matches []string
+	for _, port := range ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = kapi.ProtocolTCP
+		}
+		switch protocol {
+		case kapi.ProtocolTCP, kapi.ProtocolUDP, kapi.ProtocolSCTP:
+		default:
+			return "", fmt.Errorf("unknown port protocol %v", protocol)
+		}
+		if port.EndPort != 0 && port.EndPort < port.Port {
+			return "", fmt.Errorf("invalid port range %d-%d", port.Port, port.EndPort)
+		}
+		matches = append(matches, "("+getL4PortMatch(strings.ToLower(string(protocol)), port.Port, port.EndPort)+")")
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(matches, " || ") + ")", nil
+}
+
+func newANPRule(policyType knet.PolicyType, idx int, name string, action adminnetworkpolicyapi.AdminNetworkPolicyRuleAction,
+	peers []adminnetworkpolicyapi.AdminNetworkPolicyPeer, ports []adminnetworkpolicyapi.AdminNetworkPolicyPort) (*anpRule, error) {
+	if name == "" {
+		name = fmt.Sprintf("%s:%d", strings.ToLower(string(policyType)), idx)
+	}
+	rule := &anpRule{
+		policyType: policyType,
+		idx:        idx,
+		name:       name,
+		action:     action,
+		peerPods:   make(map[string][]net.IP),
+	}
+	switch action {
+	case adminnetworkpolicyapi.AdminNetworkPolicyRuleActionAllow,
+		adminnetworkpolicyapi.AdminNetworkPolicyRuleActionDeny,
+		adminnetworkpolicyapi.AdminNetworkPolicyRuleActionPass:
+	default:
+		return nil, fmt.Errorf("invalid action %q of rule %s", action, name)
+	}
+	for _, peer := range peers {
+		selector, err := newPodSelector(&peer.NamespaceSelector, &peer.PodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer of rule %s: %v", name, err)
+		}
+		rule.peers = append(rule.peers, selector)
+	}
+	var err error
+	if rule.l4Match, err = getANPRuleL4Match(ports); err != nil {
+		return nil, fmt.Errorf("invalid ports of rule %s: %v", name, err)
+	}
+	return rule, nil
+}
+
+// isPeer returns true if the pod is a peer of the rule
+func (r *anpRule) isPeer(namespace *kapi.Namespace, pod *kapi.Pod) bool {
+	// host networked pods have no logical port to match
+	if pod.Spec.HostNetwork {
+		return false
+	}
+	for _, peer := range r.peers {
+		if peer.matches(namespace, pod) {
+			return true
+		}
+	}
+	return false
+}
+
+// getMatch returns the match of the ACL of the rule, without its action
+func (r *anpRule) getMatch(portGroupName string) string {
+	ipv4ASHashName, ipv6ASHashName := r.addrSet.GetASHashNames()
+	direction, portMatch := "dst", "inport == @"+portGroupName
+	if r.policyType == knet.PolicyTypeIngress {
+		direction, portMatch = "src", "outport == @"+portGroupName
+	}
+	var l3Matches []string
+	if config.IPv4Mode {
+		l3Matches = append(l3Matches, fmt.Sprintf("ip4.%s == $%s", direction, ipv4ASHashName))
+	}
+	if config.IPv6Mode {
+		l3Matches = append(l3Matches, fmt.Sprintf("ip6.%s == $%s", direction, ipv6ASHashName))
+	}
+	l3Match := l3Matches[0]
+	if len(l3Matches) > 1 {
+		l3Match = "(" + strings.Join(l3Matches, " || ") + ")"
+	}
+	match := portMatch + " && " + l3Match
+	if r.l4Match != "" {
+		match += " && " + r.l4Match
+	}
+	return match
+}
+
+// getACLAction returns the action of the ACL of the rule
+func (r *anpRule) getACLAction() string {
+	if r.action == adminnetworkpolicyapi.AdminNetworkPolicyRuleActionAllow {
+		return "allow-related"
+	}
+	return "drop"
+}
+
+// isSubject returns true if the pod is a subject of the policy
+func (p *adminNetworkPolicy) isSubject(namespace *kapi.Namespace, pod *kapi.Pod) bool {
+	// host networked pods have no logical port to add to the port group
+	return !pod.Spec.HostNetwork && p.subject.matches(namespace, pod)
+}
+
+// matchesNamespace returns true if the policy selects pods of the namespace,
+// as subjects or as peers
+func (p *adminNetworkPolicy) matchesNamespace(namespace *kapi.Namespace) bool {
+	if p.subject.matchesNamespace(namespace) {
+		return true
+	}
+	for _, rule := range p.rules {
+		for _, peer := range rule.peers {
+			if peer.matchesNamespace(namespace) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getRuleACLPriority returns the priority of the ACL of a rule
+func (p *adminNetworkPolicy) getRuleACLPriority(rule *anpRule) int {
+	return p.aclPriority - rule.idx
+}
+
+// newAdminNetworkPolicy builds the state of an admin policy from its spec.
+// Invalid rules are skipped and reported in the returned errors, a nil
+// adminNetworkPolicy is only returned if the subject pods can not be selected.
+func newAdminNetworkPolicy(key, name string, baseline bool, aclPriority int, subject adminnetworkpolicyapi.AdminNetworkPolicySubject,
+	ingress []adminnetworkpolicyapi.AdminNetworkPolicyIngressRule, egress []adminnetworkpolicyapi.AdminNetworkPolicyEgressRule) (*adminNetworkPolicy, []error) {
+	subjectSelector, err := newPodSelector(&subject.NamespaceSelector, &subject.PodSelector)
+	if err != nil {
+		return nil, []error{fmt.Errorf("invalid subject: %v", err)}
+	}
+	if len(ingress) > anpMaxRules || len(egress) > anpMaxRules {
+		return nil, []error{fmt.Errorf("too many rules, at most %d rules are supported in each direction", anpMaxRules)}
+	}
+	p := &adminNetworkPolicy{
+		key:           key,
+		name:          name,
+		baseline:      baseline,
+		aclPriority:   aclPriority,
+		subject:       subjectSelector,
+		portGroupName: hashedPortGroup(key),
+		subjectPorts:  make(map[string]string),
+	}
+	var errs []error
+	addRule := func(rule *anpRule, err error) {
+		if err == nil && baseline && rule.action == adminnetworkpolicyapi.AdminNetworkPolicyRuleActionPass {
+			err = fmt.Errorf("invalid action %q of rule %s, not supported by the BaselineAdminNetworkPolicy", rule.action, rule.name)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		p.rules = append(p.rules, rule)
+	}
+	for i, rule := range ingress {
+		addRule(newANPRule(knet.PolicyTypeIngress, i, rule.Name, rule.Action, rule.From, rule.Ports))
+	}
+	for i, rule := range egress {
+		addRule(newANPRule(knet.PolicyTypeEgress, i, rule.Name, rule.Action, rule.To, rule.Ports))
+	}
+	return p, errs
+}
+
+// applyAdminNetworkPolicy programs the port group and the address sets of the
+// policy with the pods it selects, reusing the address sets of the previous
+// state of the policy, if any
+func (oc *Controller) applyAdminNetworkPolicy(p, old *adminNetworkPolicy) []error {
+	var errs []error
+	var err error
+	p.portGroupUUID, err = createPortGroup(p.key, p.portGroupName)
+	if err != nil {
+		return []error{fmt.Errorf("failed to create port group: %v", err)}
+	}
+
+	oldAddrSets := make(map[string]addressset.AddressSet)
+	if old != nil {
+		for _, rule := range old.rules {
+			oldAddrSets[rule.addrSet.GetName()] = rule.addrSet
+		}
+	}
+	rules := p.rules
+	p.rules = nil
+	for _, rule := range rules {
+		var pods []*kapi.Pod
+		for _, peer := range rule.peers {
+			peerPods, err := oc.listSelectedPods(peer)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get the peers of rule %s: %v", rule.name, err))
+				continue
+			}
+			pods = append(pods, peerPods...)
+		}
+		var ips []net.IP
+		for _, pod := range pods {
+			key, _ := cache.MetaNamespaceKeyFunc(pod)
+			if pod.Spec.HostNetwork || rule.peerPods[key] != nil {
+				continue
+			}
+			// pods without IPs yet get them added along with their logical port
+			podIPs, err := util.GetAllPodIPs(pod)
+			if err != nil {
+				continue
+			}
+			rule.peerPods[key] = podIPs
+			ips = append(ips, podIPs...)
+		}
+		addrSetName := getANPAddrSetName(p.key, rule.policyType, rule.idx)
+		if addrSet, ok := oldAddrSets[addrSetName]; ok {
+			rule.addrSet = addrSet
+			delete(oldAddrSets, addrSetName)
+			err = addrSet.SetIPs(ips)
+		} else {
+			rule.addrSet, err = oc.addressSetFactory.NewAddressSet(addrSetName, ips)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to set the address set of rule %s: %v", rule.name, err))
+			if rule.addrSet == nil {
+				continue
+			}
+		}
+		p.rules = append(p.rules, rule)
+	}
+	for _, addrSet := range oldAddrSets {
+		if err := addrSet.Destroy(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	pods, err := oc.listSelectedPods(p.subject)
+	if err != nil {
+		return append(errs, fmt.Errorf("failed to get the subject pods: %v", err))
+	}
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork {
+			continue
+		}
+		// pods without a logical port yet get added to the port group along
+		// with it
+		portInfo, err := oc.logicalPortCache.get(podLogicalPortName(pod))
+		if err != nil {
+			continue
+		}
+		key, _ := cache.MetaNamespaceKeyFunc(pod)
+		p.subjectPorts[key] = portInfo.uuid
+	}
+	if old != nil && reflect.DeepEqual(old.subjectPorts, p.subjectPorts) {
+		return errs
+	}
+	args := []string{"clear", "port_group", p.portGroupUUID, "ports"}
+	if len(p.subjectPorts) > 0 {
+		uuids := make([]string, 0, len(p.subjectPorts))
+		for _, uuid := range p.subjectPorts {
+			uuids = append(uuids, uuid)
+		}
+		sort.Strings(uuids)
+		args = append(args, "--", "add", "port_group", p.portGroupUUID, "ports")
+		args = append(args, uuids...)
+	}
+	_, stderr, err := util.RunOVNNbctl(args...)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to set the ports of port group %s, stderr: %q (%v)", p.key, stderr, err))
+	}
+	return errs
+}
+
+// destroyAdminNetworkPolicy deletes the port group, along with its ACLs, and
+// the address sets of the policy
+func destroyAdminNetworkPolicy(p *adminNetworkPolicy) []error {
+	var errs []error
+	deletePortGroup(p.portGroupName)
+	for _, rule := range p.rules {
+		if err := rule.addrSet.Destroy(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// getAdminNetworkPolicyACLs returns the ACLs of the policies, by policy key.
+// A Pass rule has no ACL: its match is excluded from the matches of the ACLs
+// of lower priority of the AdminNetworkPolicies, so that the traffic it
+// matches is left to the NetworkPolicies and then to the
+// BaselineAdminNetworkPolicy.
+func getAdminNetworkPolicyACLs(policies map[string]*adminNetworkPolicy) map[string][]anpACL {
+	type passMatch struct {
+		priority int
+		match    string
+	}
+	passMatches := make(map[knet.PolicyType][]passMatch)
+	for _, p := range policies {
+		for _, rule := range p.rules {
+			if rule.action == adminnetworkpolicyapi.AdminNetworkPolicyRuleActionPass {
+				passMatches[rule.policyType] = append(passMatches[rule.policyType],
+					passMatch{priority: p.getRuleACLPriority(rule), match: rule.getMatch(p.portGroupName)})
+			}
+		}
+	}
+	for _, matches := range passMatches {
+		sort.Slice(matches, func(i, j int) bool {
+			if matches[i].priority != matches[j].priority {
+				return matches[i].priority > matches[j].priority
+			}
+			return matches[i].match < matches[j].match
+		})
+	}
+
+	acls := make(map[string][]anpACL)
+	for key, p := range policies {
+		for _, rule := range p.rules {
+			if rule.action == adminnetworkpolicyapi.AdminNetworkPolicyRuleActionPass {
+				continue
+			}
+			priority := p.getRuleACLPriority(rule)
+			match := rule.getMatch(p.portGroupName)
+			if !p.baseline {
+				for _, pass := range passMatches[rule.policyType] {
+					if pass.priority > priority {
+						match += " && !(" + pass.match + ")"
+					}
+				}
+			}
+			acls[key] = append(acls[key], anpACL{
+				priority:  priority,
+				direction: toLport,
+				match:     match,
+				action:    rule.getACLAction(),
+				name:      fmt.Sprintf("%s:%s", p.key, rule.name),
+			})
+		}
+	}
+	return acls
+}
+
+// setAdminNetworkPolicyACLs sets the ACLs of the port groups of the admin
+// policies whose ACLs changed
+// This should only be called with anpMutex held
+func (oc *Controller) setAdminNetworkPolicyACLs() []error {
+	var errs []error
+	acls := getAdminNetworkPolicyACLs(oc.adminNetworkPolicies)
+	for key, p := range oc.adminNetworkPolicies {
+		if p.portGroupUUID == "" || reflect.DeepEqual(p.acls, acls[key]) {
+			continue
+		}
+		args := []string{"clear", "port_group", p.portGroupUUID, "acls"}
+		for i, acl := range acls[key] {
+			id := fmt.Sprintf("@acl%d", i)
+			args = append(args, "--", "--id="+id, "create", "acl",
+				fmt.Sprintf("priority=%d", acl.priority),
+				fmt.Sprintf("direction=%s", acl.direction),
+				fmt.Sprintf("match=\"%s\"", acl.match),
+				fmt.Sprintf("action=%s", acl.action),
+				fmt.Sprintf("name=%.63s", acl.name),
+				"--", "add", "port_group", p.portGroupUUID, "acls", id)
+		}
+		_, stderr, err := util.RunOVNNbctl(args...)
+		if err != nil {
+			// set them on the next sync
+			p.acls = nil
+			errs = append(errs, fmt.Errorf("failed to set the ACLs of port group %s, stderr: %q (%v)", p.key, stderr, err))
+			continue
+		}
+		p.acls = acls[key]
+	}
+	return errs
+}
+
+// syncAdminNetworkPolicy applies the AdminNetworkPolicy of the given name as
+// found in the informer cache, or removes it if it is not found, and reports
+// the outcome in its status
+func (oc *Controller) syncAdminNetworkPolicy(name string) error {
+	oc.anpMutex.Lock()
+	defer oc.anpMutex.Unlock()
+
+	policy, err := oc.watchFactory.GetANP(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get AdminNetworkPolicy %s: %v", name, err)
+	}
+	if apierrors.IsNotFound(err) {
+		return kerrors.NewAggregate(oc.setAdminNetworkPolicy(getANPKey(name), nil))
+	}
+	var p *adminNetworkPolicy
+	var errs []error
+	if policy.Spec.Priority < 0 || policy.Spec.Priority > anpMaxPriority {
+		errs = []error{fmt.Errorf("invalid priority %d, it must be between 0 and %d", policy.Spec.Priority, anpMaxPriority)}
+	} else {
+		aclPriority := anpMaxACLPriority - int(policy.Spec.Priority)*anpMaxRules
+		p, errs = newAdminNetworkPolicy(getANPKey(name), name, false, aclPriority,
+			policy.Spec.Subject, policy.Spec.Ingress, policy.Spec.Egress)
+	}
+	errs = append(errs, oc.setAdminNetworkPolicy(getANPKey(name), p)...)
+	status := getAdminNetworkPolicyStatus(errs)
+	if err := oc.updateANPStatusWithRetry(name, status); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// syncBaselineAdminNetworkPolicy applies the BaselineAdminNetworkPolicy of
+// the given name as found in the informer cache, or removes it if it is not
+// found, and reports the outcome in its status
+func (oc *Controller) syncBaselineAdminNetworkPolicy(name string) error {
+	oc.anpMutex.Lock()
+	defer oc.anpMutex.Unlock()
+
+	policy, err := oc.watchFactory.GetBANP(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get BaselineAdminNetworkPolicy %s: %v", name, err)
+	}
+	if apierrors.IsNotFound(err) {
+		return kerrors.NewAggregate(oc.setAdminNetworkPolicy(getBANPKey(name), nil))
+	}
+	var p *adminNetworkPolicy
+	var errs []error
+	if name != banpName {
+		errs = []error{fmt.Errorf("only the BaselineAdminNetworkPolicy named %s is applied", banpName)}
+	} else {
+		p, errs = newAdminNetworkPolicy(getBANPKey(name), name, true, banpMaxACLPriority,
+			policy.Spec.Subject, policy.Spec.Ingress, policy.Spec.Egress)
+	}
+	errs = append(errs, oc.setAdminNetworkPolicy(getBANPKey(name), p)...)
+	status := getAdminNetworkPolicyStatus(errs)
+	if err := oc.updateBANPStatusWithRetry(name, status); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// setAdminNetworkPolicy replaces the state of the admin policy of the given
+// key, removing it if p is nil, and updates the ACLs of all the admin policies
+// This should only be called with anpMutex held
+func (oc *Controller) setAdminNetworkPolicy(key string, p *adminNetworkPolicy) []error {
+	var errs []error
+	old := oc.adminNetworkPolicies[key]
+	if p == nil {
+		if old == nil {
+			return nil
+		}
+		delete(oc.adminNetworkPolicies, key)
+		errs = append(errs, destroyAdminNetworkPolicy(old)...)
+	} else {
+		errs = append(errs, oc.applyAdminNetworkPolicy(p, old)...)
+		if old != nil {
+			p.acls = old.acls
+		}
+		oc.adminNetworkPolicies[key] = p
+	}
+	return append(errs, oc.setAdminNetworkPolicyACLs()...)
+}
+
+// syncAdminNetworkPoliciesForPod updates the port groups and the address sets
+// of the admin policies the pod is a subject or a peer of, on the pod add
+// (oldPod is nil), update or delete (newPod is nil)
+func (oc *Controller) syncAdminNetworkPoliciesForPod(oldPod, newPod *kapi.Pod, baseline bool) {
+	pod := newPod
+	if pod == nil {
+		pod = oldPod
+	}
+	key, _ := cache.MetaNamespaceKeyFunc(pod)
+	// the namespace is gone on deletion of the pods of deleted namespaces
+	namespace, _ := oc.watchFactory.GetNamespace(pod.Namespace)
+	var newIPs []net.IP
+	if newPod != nil {
+		newIPs, _ = util.GetAllPodIPs(newPod)
+	}
+	var portInfo *lpInfo
+	if newPod != nil {
+		portInfo, _ = oc.logicalPortCache.get(podLogicalPortName(newPod))
+	}
+
+	oc.anpMutex.Lock()
+	defer oc.anpMutex.Unlock()
+	for _, p := range oc.adminNetworkPolicies {
+		if p.baseline != baseline {
+			continue
+		}
+		isSubject := newPod != nil && namespace != nil && portInfo != nil && p.isSubject(namespace, newPod)
+		if _, ok := p.subjectPorts[key]; ok && !isSubject {
+			// deleted pods leave the port group along with their logical port
+			if newPod != nil {
+				if err := deleteFromPortGroup(p.portGroupUUID, &lpInfo{name: podLogicalPortName(newPod), uuid: p.subjectPorts[key]}); err != nil {
+					klog.Errorf("Unable to remove pod %s from the port group of %s: %v", key, p.key, err)
+				}
+			}
+			delete(p.subjectPorts, key)
+		} else if !ok && isSubject {
+			if err := addToPortGroup(p.portGroupUUID, portInfo); err != nil {
+				klog.Errorf("Unable to add pod %s to the port group of %s: %v", key, p.key, err)
+			} else {
+				p.subjectPorts[key] = portInfo.uuid
+			}
+		}
+		for _, rule := range p.rules {
+			isPeer := newPod != nil && namespace != nil && len(newIPs) > 0 && rule.isPeer(namespace, newPod)
+			oldIPs, wasPeer := rule.peerPods[key]
+			if wasPeer && (!isPeer || !reflect.DeepEqual(oldIPs, newIPs)) {
+				if err := rule.addrSet.DeleteIPs(oldIPs); err != nil {
+					klog.Errorf("Unable to remove pod %s from the peers of %s rule %s: %v", key, p.key, rule.name, err)
+				}
+				delete(rule.peerPods, key)
+				wasPeer = false
+			}
+			if isPeer && !wasPeer {
+				if err := rule.addrSet.AddIPs(newIPs); err != nil {
+					klog.Errorf("Unable to add pod %s to the peers of %s rule %s: %v", key, p.key, rule.name, err)
+					continue
+				}
+				rule.peerPods[key] = newIPs
+			}
+		}
+	}
+}
+
+// addPodToAdminNetworkPolicies adds the logical port of the pod to the port
+// groups of the admin policies it is a subject of, and its IPs to the address
+// sets of the rules it is a peer of
+func (oc *Controller) addPodToAdminNetworkPolicies(pod *kapi.Pod, portInfo *lpInfo) error {
+	oc.anpMutex.Lock()
+	defer oc.anpMutex.Unlock()
+	if len(oc.adminNetworkPolicies) == 0 {
+		return nil
+	}
+	namespace, err := oc.watchFactory.GetNamespace(pod.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to get namespace %s of pod %s: %v", pod.Namespace, pod.Name, err)
+	}
+	key, _ := cache.MetaNamespaceKeyFunc(pod)
+	ips := make([]net.IP, 0, len(portInfo.ips))
+	for _, ip := range portInfo.ips {
+		ips = append(ips, ip.IP)
+	}
+	var errs []error
+	for _, p := range oc.adminNetworkPolicies {
+		if p.isSubject(namespace, pod) && p.subjectPorts[key] != portInfo.uuid {
+			if err := addToPortGroup(p.portGroupUUID, portInfo); err != nil {
+				errs = append(errs, err)
+			} else {
+				p.subjectPorts[key] = portInfo.uuid
+			}
+		}
+		for _, rule := range p.rules {
+			if _, ok := rule.peerPods[key]; ok || !rule.isPeer(namespace, pod) {
+				continue
+			}
+			if err := rule.addrSet.AddIPs(ips); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rule.peerPods[key] = ips
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// getAdminNetworkPoliciesForNamespace returns the names of the admin policies
+// of the given kind selecting pods of the namespace, as it was (oldNamespace)
+// or as it is (newNamespace)
+func (oc *Controller) getAdminNetworkPoliciesForNamespace(oldNamespace, newNamespace *kapi.Namespace, baseline bool) []string {
+	oc.anpMutex.Lock()
+	defer oc.anpMutex.Unlock()
+	names := sets.NewString()
+	for _, p := range oc.adminNetworkPolicies {
+		if p.baseline != baseline {
+			continue
+		}
+		if (oldNamespace != nil && p.matchesNamespace(oldNamespace)) || p.matchesNamespace(newNamespace) {
+			names.Insert(p.name)
+		}
+	}
+	return names.List()
+}
+
+// deleteStaleAdminNetworkPolicies deletes the port groups and the address sets
+// of the admin policies of the given kind that no longer exist
+func (oc *Controller) deleteStaleAdminNetworkPolicies(keyPrefix string, names []string) {
+	expectedKeys := sets.NewString()
+	expectedAddrSetKeys := sets.NewString()
+	for _, name := range names {
+		expectedKeys.Insert(keyPrefix + name)
+		expectedAddrSetKeys.Insert(strings.ReplaceAll(keyPrefix+name, ".", "_"))
+	}
+	output, stderr, err := util.RunOVNNbctl("--format=csv", "--data=bare", "--no-heading",
+		"--columns=name,external_ids", "find", "port_group")
+	if err != nil {
+		klog.Errorf("Unable to list port groups, stderr: %q (%v)", stderr, err)
+		return
+	}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, ",")
+		if len(parts) != 2 {
+			continue
+		}
+		for _, externalID := range strings.Fields(parts[1]) {
+			key := strings.TrimPrefix(externalID, "name=")
+			if key != externalID && strings.HasPrefix(key, keyPrefix) && !expectedKeys.Has(key) {
+				deletePortGroup(parts[0])
+			}
+		}
+	}
+	err = oc.addressSetFactory.ForEachAddressSet(func(addrSetName, _, _ string) {
+		if !strings.HasPrefix(addrSetName, keyPrefix) {
+			return
+		}
+		parts := strings.Split(addrSetName, ":")
+		if len(parts) == 4 && !expectedAddrSetKeys.Has(keyPrefix+parts[1]) {
+			if err := oc.addressSetFactory.DestroyAddressSetInBackingStore(addrSetName); err != nil {
+				klog.Errorf(err.Error())
+			}
+		}
+	})
+	if err != nil {
+		klog.Errorf("Error in syncing admin network policies: %v", err)
+	}
+}
+
+func getAdminNetworkPolicyStatus(errs []error) adminnetworkpolicyapi.AdminNetworkPolicyStatus {
+	status := adminnetworkpolicyapi.AdminNetworkPolicyStatus{
+		Status: anpStatusSuccess,
+	}
+	if len(errs) > 0 {
+		status.Status = anpStatusFail
+		for _, err := range errs {
+			status.Messages = append(status.Messages, err.Error())
+		}
+		sort.Strings(status.Messages)
+	}
+	return status
+}
+
+// updateANPStatusWithRetry sets the status of the AdminNetworkPolicy of the given name, unless it is already set
+func (oc *Controller) updateANPStatusWithRetry(name string, status adminnetworkpolicyapi.AdminNetworkPolicyStatus) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		policy, err := oc.watchFactory.GetANP(name)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(policy.Status, status) {
+			return nil
+		}
+		policy = policy.DeepCopy()
+		policy.Status = status
+		return oc.kube.UpdateAdminNetworkPolicy(policy)
+	})
+	if retryErr != nil {
+		return fmt.Errorf("error in updating status on AdminNetworkPolicy %s: %v", name, retryErr)
+	}
+	return nil
+}
+
+// updateBANPStatusWithRetry sets the status of the BaselineAdminNetworkPolicy of the given name, unless it is already set
+func (oc *Controller) updateBANPStatusWithRetry(name string, status adminnetworkpolicyapi.AdminNetworkPolicyStatus) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		policy, err := oc.watchFactory.GetBANP(name)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(policy.Status, status) {
+			return nil
+		}
+		policy = policy.DeepCopy()
+		policy.Status = status
+		return oc.kube.UpdateBaselineAdminNetworkPolicy(policy)
+	})
+	if retryErr != nil {
+		return fmt.Errorf("error in updating status on BaselineAdminNetworkPolicy %s: %v", name, retryErr)
+	}
+	return nil
+}
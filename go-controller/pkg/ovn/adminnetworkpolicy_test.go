@@ -0,0 +1,277 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	adminnetworkpolicyapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/urfave/cli/v2"
+
+	v1 "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("OVN AdminNetworkPolicy Operations", func() {
+	const (
+		policyName    string = "policy"
+		namespaceName string = "namespace1"
+		portGroupUUID string = "anp-port-group-uuid"
+	)
+	var (
+		app     *cli.App
+		fakeOVN *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	namespaceSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{"name": namespaceName},
+	}
+	peers := []adminnetworkpolicyapi.AdminNetworkPolicyPeer{
+		{NamespaceSelector: namespaceSelector},
+	}
+
+	newPolicy := func(ingress []adminnetworkpolicyapi.AdminNetworkPolicyIngressRule, egress []adminnetworkpolicyapi.AdminNetworkPolicyEgressRule) adminnetworkpolicyapi.AdminNetworkPolicy {
+		return adminnetworkpolicyapi.AdminNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: policyName,
+			},
+			Spec: adminnetworkpolicyapi.AdminNetworkPolicySpec{
+				Priority: 10,
+				Subject: adminnetworkpolicyapi.AdminNetworkPolicySubject{
+					NamespaceSelector: namespaceSelector,
+				},
+				Ingress: ingress,
+				Egress:  egress,
+			},
+		}
+	}
+
+	getPolicyStatus := func() adminnetworkpolicyapi.AdminNetworkPolicyStatus {
+		policy, err := fakeOVN.fakeClient.ANPClient.K8sV1().AdminNetworkPolicies().Get(context.TODO(), policyName, metav1.GetOptions{})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return policy.Status
+	}
+
+	getBaselinePolicyStatus := func(name string) func() adminnetworkpolicyapi.AdminNetworkPolicyStatus {
+		return func() adminnetworkpolicyapi.AdminNetworkPolicyStatus {
+			policy, err := fakeOVN.fakeClient.ANPClient.K8sV1().BaselineAdminNetworkPolicies().Get(context.TODO(), name, metav1.GetOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			return policy.Status
+		}
+	}
+
+	// addPortGroupCmds adds the commands syncing the stale policies and
+	// creating the port group of the policy of the given key
+	addPortGroupCmds := func(key string) {
+		fExec.AddFakeCmdsNoOutputNoError([]string{
+			"ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=name,external_ids find port_group",
+			"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find port_group name=" + hashedPortGroup(key),
+		})
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 create port_group name=" + hashedPortGroup(key) + " external-ids:name=" + key,
+			Output: portGroupUUID,
+		})
+	}
+
+	getPeerMatch := func(key string, policyType knet.PolicyType, idx int) string {
+		ipv4ASHashName, _ := addressset.MakeAddressSetHashNames(getANPAddrSetName(key, policyType, idx))
+		if policyType == knet.PolicyTypeIngress {
+			return "outport == @" + hashedPortGroup(key) + " && ip4.src == $" + ipv4ASHashName
+		}
+		return "inport == @" + hashedPortGroup(key) + " && ip4.dst == $" + ipv4ASHashName
+	}
+
+	getACLArgs := func(idx, priority int, match, action, name string) string {
+		return fmt.Sprintf(" -- --id=@acl%d create acl priority=%d direction=to-lport match=\"%s\" action=%s name=%s -- add port_group %s acls @acl%d",
+			idx, priority, match, action, name, portGroupUUID, idx)
+	}
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOVN = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	ginkgo.It("applies the rules to the subject pods ahead of the NetworkPolicies until the policy is deleted", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespaceT := *newNamespace(namespaceName)
+			t := newTPod(
+				"node1",
+				"10.128.1.0/24",
+				"10.128.1.2",
+				"10.128.1.1",
+				"myPod",
+				"10.128.1.3",
+				"0a:58:0a:80:01:03",
+				namespaceT.Name,
+			)
+			policy := newPolicy(
+				[]adminnetworkpolicyapi.AdminNetworkPolicyIngressRule{
+					{Action: adminnetworkpolicyapi.AdminNetworkPolicyRuleActionDeny, From: peers},
+				},
+				[]adminnetworkpolicyapi.AdminNetworkPolicyEgressRule{
+					{
+						Name:   "web",
+						Action: adminnetworkpolicyapi.AdminNetworkPolicyRuleActionAllow,
+						To:     peers,
+						Ports:  []adminnetworkpolicyapi.AdminNetworkPolicyPort{{Port: 80}},
+					},
+				},
+			)
+			key := getANPKey(policyName)
+
+			t.baseCmds(fExec)
+			addPortGroupCmds(key)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 clear port_group " + portGroupUUID + " ports -- add port_group " + portGroupUUID + " ports " + fakeUUID,
+				"ovn-nbctl --timeout=15 clear port_group " + portGroupUUID + " acls" +
+					getACLArgs(0, 29000, getPeerMatch(key, knet.PolicyTypeIngress, 0), "drop", key+":ingress:0") +
+					getACLArgs(1, 29000, getPeerMatch(key, knet.PolicyTypeEgress, 0)+" && ((tcp && tcp.dst==80))", "allow-related", key+":web"),
+			})
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespaceT}},
+				&v1.PodList{Items: []v1.Pod{*newPod(t.namespace, t.podName, t.nodeName, t.podIP)}},
+				&adminnetworkpolicyapi.AdminNetworkPolicyList{
+					Items: []adminnetworkpolicyapi.AdminNetworkPolicy{policy},
+				},
+			)
+			t.populateLogicalSwitchCache(fakeOVN)
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchPods()
+			fakeOVN.controller.WatchAdminNetworkPolicies()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminnetworkpolicyapi.AdminNetworkPolicyStatus{
+				Status: anpStatusSuccess,
+			}))
+			fakeOVN.asf.ExpectAddressSetWithIPs(getANPAddrSetName(key, knet.PolicyTypeIngress, 0), []string{t.podIP})
+			fakeOVN.asf.ExpectAddressSetWithIPs(getANPAddrSetName(key, knet.PolicyTypeEgress, 0), []string{t.podIP})
+
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find port_group name=" + hashedPortGroup(key),
+				Output: portGroupUUID,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --if-exists destroy port_group " + portGroupUUID,
+			})
+			err := fakeOVN.fakeClient.ANPClient.K8sV1().AdminNetworkPolicies().Delete(context.TODO(), policyName, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.EventuallyExpectNoAddressSet(getANPAddrSetName(key, knet.PolicyTypeIngress, 0))
+			fakeOVN.asf.EventuallyExpectNoAddressSet(getANPAddrSetName(key, knet.PolicyTypeEgress, 0))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("excludes the traffic passed by a rule from the rules of lower priority", func() {
+		app.Action = func(ctx *cli.Context) error {
+			policy := newPolicy(
+				[]adminnetworkpolicyapi.AdminNetworkPolicyIngressRule{
+					{
+						Action: adminnetworkpolicyapi.AdminNetworkPolicyRuleActionPass,
+						From:   peers,
+						Ports:  []adminnetworkpolicyapi.AdminNetworkPolicyPort{{Protocol: v1.ProtocolUDP, Port: 53}},
+					},
+					{Action: adminnetworkpolicyapi.AdminNetworkPolicyRuleActionDeny, From: peers},
+				},
+				nil,
+			)
+			key := getANPKey(policyName)
+
+			addPortGroupCmds(key)
+			passMatch := getPeerMatch(key, knet.PolicyTypeIngress, 0) + " && ((udp && udp.dst==53))"
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 clear port_group " + portGroupUUID + " ports",
+				"ovn-nbctl --timeout=15 clear port_group " + portGroupUUID + " acls" +
+					getACLArgs(0, 28999, getPeerMatch(key, knet.PolicyTypeIngress, 1)+" && !("+passMatch+")", "drop", key+":ingress:1"),
+			})
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{*newNamespace(namespaceName)}},
+				&adminnetworkpolicyapi.AdminNetworkPolicyList{
+					Items: []adminnetworkpolicyapi.AdminNetworkPolicy{policy},
+				},
+			)
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchAdminNetworkPolicies()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminnetworkpolicyapi.AdminNetworkPolicyStatus{
+				Status: anpStatusSuccess,
+			}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("applies the BaselineAdminNetworkPolicy behind the NetworkPolicies and rejects its pass rules", func() {
+		app.Action = func(ctx *cli.Context) error {
+			subject := adminnetworkpolicyapi.AdminNetworkPolicySubject{NamespaceSelector: namespaceSelector}
+			ingress := []adminnetworkpolicyapi.AdminNetworkPolicyIngressRule{
+				{Action: adminnetworkpolicyapi.AdminNetworkPolicyRuleActionPass, From: peers},
+				{Action: adminnetworkpolicyapi.AdminNetworkPolicyRuleActionDeny, From: peers},
+			}
+			baselinePolicies := []adminnetworkpolicyapi.BaselineAdminNetworkPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: banpName},
+					Spec:       adminnetworkpolicyapi.BaselineAdminNetworkPolicySpec{Subject: subject, Ingress: ingress},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "other"},
+					Spec:       adminnetworkpolicyapi.BaselineAdminNetworkPolicySpec{Subject: subject, Ingress: ingress},
+				},
+			}
+			key := getBANPKey(banpName)
+
+			addPortGroupCmds(key)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 clear port_group " + portGroupUUID + " ports",
+				"ovn-nbctl --timeout=15 clear port_group " + portGroupUUID + " acls" +
+					getACLArgs(0, 998, getPeerMatch(key, knet.PolicyTypeIngress, 1), "drop", key+":ingress:1"),
+			})
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{*newNamespace(namespaceName)}},
+				&adminnetworkpolicyapi.BaselineAdminNetworkPolicyList{
+					Items: baselinePolicies,
+				},
+			)
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchBaselineAdminNetworkPolicies()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getBaselinePolicyStatus(banpName)).Should(gomega.Equal(adminnetworkpolicyapi.AdminNetworkPolicyStatus{
+				Status:   anpStatusFail,
+				Messages: []string{"invalid action \"Pass\" of rule ingress:0, not supported by the BaselineAdminNetworkPolicy"},
+			}))
+			gomega.Eventually(getBaselinePolicyStatus("other")).Should(gomega.Equal(adminnetworkpolicyapi.AdminNetworkPolicyStatus{
+				Status:   anpStatusFail,
+				Messages: []string{"only the BaselineAdminNetworkPolicy named default is applied"},
+			}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
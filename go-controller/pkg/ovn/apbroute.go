@@ -0,0 +1,463 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	adminpolicybasedrouteapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1"
+
+	kapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	apbRouteStatusSuccess = "Success"
+	apbRouteStatusFail    = "Fail"
+)
+
+// apbRouteDynamicHop is a dynamic next hop of an AdminPolicyBasedExternalRoute
+type apbRouteDynamicHop struct {
+	podSelector
+	networkAttachmentName string
+	bfdEnabled            bool
+	bfdParams             bfdParameters
+}
+
+func newAPBRouteBFDParameters(params adminpolicybasedrouteapi.BFDParameters) bfdParameters {
+	return bfdParameters{
+		minTx:      int(params.MinTx),
+		minRx:      int(params.MinRx),
+		detectMult: int(params.DetectMult),
+	}
+}
+
+// apbRoute is the state applied for an AdminPolicyBasedExternalRoute: the pods
+// it selects are routed through its gateways, on top of the gateways their
+// namespace is annotated with or served by
+type apbRoute struct {
+	from        podSelector
+	dynamicHops []apbRouteDynamicHop
+	gateways    []gatewayInfo
+	// pods selected by the policy, by namespace/name key
+	targetPods sets.String
+	// pods serving the dynamic hops of the policy, by namespace/name key
+	hopPods sets.String
+}
+
+// isTarget returns true if the pod is routed through the gateways of the policy
+func (r *apbRoute) isTarget(namespace *kapi.Namespace, pod *kapi.Pod) bool {
+	// host networked pods have no logical port to route
+	return !pod.Spec.HostNetwork && r.from.matches(namespace, pod)
+}
+
+// isHop returns true if the pod serves, or may serve, a dynamic hop of the policy
+func (r *apbRoute) isHop(namespace *kapi.Namespace, pod *kapi.Pod) bool {
+	key, _ := cache.MetaNamespaceKeyFunc(pod)
+	if r.hopPods.Has(key) {
+		return true
+	}
+	if namespace == nil {
+		return false
+	}
+	for _, hop := range r.dynamicHops {
+		if hop.matches(namespace, pod) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNamespace returns true if the policy selects pods of the namespace,
+// as targets or as gateways
+func (r *apbRoute) matchesNamespace(namespace *kapi.Namespace) bool {
+	if r.from.matchesNamespace(namespace) {
+		return true
+	}
+	for _, hop := range r.dynamicHops {
+		if hop.matchesNamespace(namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeHops returns the sorted IPs of the gateways of the policy
+func (r *apbRoute) activeHops() []string {
+	hops := sets.NewString()
+	for _, gateway := range r.gateways {
+		for _, gw := range gateway.gws {
+			hops.Insert(gw.String())
+		}
+	}
+	return hops.List()
+}
+
+// newAPBRoute builds the state of an AdminPolicyBasedExternalRoute from its
+// spec and the pods it selects. Invalid next hops are skipped and reported in
+// the returned errors, a nil apbRoute is only returned if the pods routed
+// through the gateways can not be selected.
+func (oc *Controller) newAPBRoute(policy *adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute) (*apbRoute, []error) {
+	from, err := newPodSelector(&policy.Spec.From.NamespaceSelector, &policy.Spec.From.PodSelector)
+	if err != nil {
+		return nil, []error{err}
+	}
+	route := &apbRoute{
+		from:       from,
+		targetPods: sets.NewString(),
+		hopPods:    sets.NewString(),
+	}
+	var errs []error
+	for _, hop := range policy.Spec.NextHops.StaticHops {
+		ip := net.ParseIP(hop.IP)
+		if ip == nil {
+			errs = append(errs, fmt.Errorf("invalid static hop IP %q", hop.IP))
+			continue
+		}
+		route.gateways = append(route.gateways, gatewayInfo{
+			gws:        []net.IP{ip},
+			bfdEnabled: hop.BFDEnabled,
+			bfdParams:  newAPBRouteBFDParameters(hop.BFDParameters),
+		})
+	}
+	for _, hop := range policy.Spec.NextHops.DynamicHops {
+		selector, err := newPodSelector(&hop.NamespaceSelector, &hop.PodSelector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid dynamic hop: %v", err))
+			continue
+		}
+		route.dynamicHops = append(route.dynamicHops, apbRouteDynamicHop{
+			podSelector:           selector,
+			networkAttachmentName: hop.NetworkAttachmentName,
+			bfdEnabled:            hop.BFDEnabled,
+			bfdParams:             newAPBRouteBFDParameters(hop.BFDParameters),
+		})
+	}
+	for _, hop := range route.dynamicHops {
+		pods, err := oc.listSelectedPods(hop.podSelector)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, pod := range pods {
+			key, _ := cache.MetaNamespaceKeyFunc(pod)
+			route.hopPods.Insert(key)
+			if !isGatewayPodReady(pod) {
+				continue
+			}
+			gws, err := getExGWPodIPs(pod, hop.networkAttachmentName)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if len(gws) == 0 {
+				klog.Warningf("No valid gateway IPs found for gateway pod %s of AdminPolicyBasedExternalRoute %s", key, policy.Name)
+				continue
+			}
+			route.gateways = append(route.gateways, gatewayInfo{gws: gws, bfdEnabled: hop.bfdEnabled, bfdParams: hop.bfdParams})
+		}
+	}
+	pods, err := oc.listSelectedPods(route.from)
+	if err != nil {
+		return nil, append(errs, err)
+	}
+	for _, pod := range pods {
+		if !pod.Spec.HostNetwork {
+			key, _ := cache.MetaNamespaceKeyFunc(pod)
+			route.targetPods.Insert(key)
+		}
+	}
+	return route, errs
+}
+
+// getAPBRouteGWsForPod returns the gateways of the AdminPolicyBasedExternalRoutes selecting the pod
+func (oc *Controller) getAPBRouteGWsForPod(pod *kapi.Pod) []gatewayInfo {
+	oc.apbRoutesMutex.Lock()
+	defer oc.apbRoutesMutex.Unlock()
+	return oc.apbRouteGWsForPod(pod)
+}
+
+// apbRouteGWsForPod returns the gateways of the AdminPolicyBasedExternalRoutes selecting the pod
+// This should only be called with apbRoutesMutex held
+func (oc *Controller) apbRouteGWsForPod(pod *kapi.Pod) []gatewayInfo {
+	if len(oc.apbRoutes) == 0 {
+		return nil
+	}
+	namespace, err := oc.watchFactory.GetNamespace(pod.Namespace)
+	if err != nil {
+		klog.Warningf("Unable to get namespace %s of pod %s: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	var gateways []gatewayInfo
+	for _, route := range oc.apbRoutes {
+		if route.isTarget(namespace, pod) {
+			gateways = append(gateways, route.gateways...)
+		}
+	}
+	return gateways
+}
+
+// addAPBRoutesForPod adds the routes of each pod IP to the gateways of the same IP family
+func (oc *Controller) addAPBRoutesForPod(gateways []gatewayInfo, podIfAddrs []*net.IPNet, namespace, node string) error {
+	for _, podIPNet := range podIfAddrs {
+		var familyGateways []gatewayInfo
+		for _, gateway := range gateways {
+			gws, err := util.MatchIPFamily(utilnet.IsIPv6(podIPNet.IP), gateway.gws)
+			if err == nil {
+				familyGateways = append(familyGateways, gatewayInfo{gws: gws, bfdEnabled: gateway.bfdEnabled, bfdParams: gateway.bfdParams})
+			}
+		}
+		if len(familyGateways) == 0 {
+			continue
+		}
+		if err := oc.addGWRoutesForPod(familyGateways, []*net.IPNet{podIPNet}, namespace, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getExternalGWRoutesForPod returns the gateways the pod IPs are routed through
+func (oc *Controller) getExternalGWRoutesForPod(namespace string, podIPNets []*net.IPNet) sets.String {
+	gws := sets.NewString()
+	nsInfo := oc.getNamespaceLocked(namespace)
+	if nsInfo == nil {
+		return gws
+	}
+	defer nsInfo.Unlock()
+	for _, podIPNet := range podIPNets {
+		for gw := range nsInfo.podExternalRoutes[podIPNet.IP.String()] {
+			gws.Insert(gw)
+		}
+	}
+	return gws
+}
+
+// ensureExternalGWRoutesForPod routes the pod through the gateways of its
+// namespace and apbGateways, the gateways of the AdminPolicyBasedExternalRoutes
+// selecting it, replacing its routes if they changed
+func (oc *Controller) ensureExternalGWRoutesForPod(pod *kapi.Pod, apbGateways []gatewayInfo) error {
+	portInfo, err := oc.logicalPortCache.get(podLogicalPortName(pod))
+	if err != nil {
+		// the routes of the pod are added along with its logical port
+		return nil
+	}
+	gateways := oc.getRoutingGWs(pod.Namespace)
+	allGateways := append(append([]gatewayInfo{}, gateways...), apbGateways...)
+	desired := sets.NewString()
+	for _, podIPNet := range portInfo.ips {
+		for _, gateway := range allGateways {
+			for _, gw := range gateway.gws {
+				if utilnet.IsIPv6(gw) == utilnet.IsIPv6(podIPNet.IP) {
+					desired.Insert(gw.String())
+				}
+			}
+		}
+	}
+	if desired.Equal(oc.getExternalGWRoutesForPod(pod.Namespace, portInfo.ips)) {
+		// the routes are unchanged but the BFD parameters of the gateways may not be
+		return setAPBRouteBFDParameters(apbGateways, pod.Spec.NodeName)
+	}
+
+	klog.Infof("Updating external gateway routes of pod %s/%s to %v", pod.Namespace, pod.Name, desired.List())
+	oc.deleteGWRoutesForPod(pod.Namespace, portInfo.ips)
+	if len(gateways) > 0 {
+		if err := oc.addGWRoutesForPod(gateways, portInfo.ips, pod.Namespace, pod.Spec.NodeName); err != nil {
+			return err
+		}
+	}
+	if err := oc.addAPBRoutesForPod(apbGateways, portInfo.ips, pod.Namespace, pod.Spec.NodeName); err != nil {
+		return err
+	}
+	if config.Gateway.DisableSNATMultipleGWs {
+		if desired.Len() == 0 {
+			return oc.addPerPodGRSNAT(pod, portInfo.ips)
+		}
+		oc.deletePerPodGRSNAT(pod.Spec.NodeName, portInfo.ips)
+	}
+	return nil
+}
+
+// setAPBRouteBFDParameters sets the BFD parameters of the sessions between the
+// gateway router of the node and the gateways
+func setAPBRouteBFDParameters(gateways []gatewayInfo, node string) error {
+	var errs []error
+	for _, gateway := range gateways {
+		if !gateway.bfdEnabled {
+			continue
+		}
+		for _, gw := range gateway.gws {
+			if err := setBFDParameters(gw.String(), types.GWRouterPrefix+node, gateway.bfdParams); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// syncAPBRoute applies the AdminPolicyBasedExternalRoute of the given name as
+// found in the informer cache, or removes it if it is not found, and reports
+// the outcome in its status
+func (oc *Controller) syncAPBRoute(name string) error {
+	oc.apbRoutesMutex.Lock()
+	defer oc.apbRoutesMutex.Unlock()
+
+	policy, err := oc.watchFactory.GetAPBRoute(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get AdminPolicyBasedExternalRoute %s: %v", name, err)
+	}
+	if apierrors.IsNotFound(err) {
+		policy = nil
+	}
+
+	var errs []error
+	pods := sets.NewString()
+	if route, ok := oc.apbRoutes[name]; ok {
+		pods = pods.Union(route.targetPods)
+		delete(oc.apbRoutes, name)
+	}
+	var route *apbRoute
+	if policy != nil {
+		route, errs = oc.newAPBRoute(policy)
+		if route != nil {
+			oc.apbRoutes[name] = route
+			pods = pods.Union(route.targetPods)
+		}
+	}
+
+	for _, key := range pods.List() {
+		namespace, podName, _ := cache.SplitMetaNamespaceKey(key)
+		pod, err := oc.watchFactory.GetPod(namespace, podName)
+		if err != nil {
+			// deleted pods have their routes removed along with their logical port
+			continue
+		}
+		if err := oc.ensureExternalGWRoutesForPod(pod, oc.apbRouteGWsForPod(pod)); err != nil {
+			errs = append(errs, fmt.Errorf("unable to update the external gateway routes of pod %s: %v", key, err))
+		}
+	}
+
+	if policy == nil {
+		return kerrors.NewAggregate(errs)
+	}
+	status := adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+		Status: apbRouteStatusSuccess,
+	}
+	if route != nil && len(route.gateways) > 0 {
+		status.ActiveHops = route.activeHops()
+	}
+	if len(errs) > 0 {
+		status.Status = apbRouteStatusFail
+		for _, err := range errs {
+			status.Messages = append(status.Messages, err.Error())
+		}
+		sort.Strings(status.Messages)
+	}
+	if err := oc.updateAPBRouteStatusWithRetry(policy.Name, status); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// syncAPBRoutesForPod updates the AdminPolicyBasedExternalRoutes the pod is a
+// target or a gateway of, on the pod add (oldPod is nil), update or delete
+// (newPod is nil)
+func (oc *Controller) syncAPBRoutesForPod(oldPod, newPod *kapi.Pod) {
+	pod := newPod
+	if pod == nil {
+		pod = oldPod
+	}
+	key, _ := cache.MetaNamespaceKeyFunc(pod)
+	// the namespace is gone on deletion of the pods of deleted namespaces
+	namespace, _ := oc.watchFactory.GetNamespace(pod.Namespace)
+
+	oc.apbRoutesMutex.Lock()
+	toSync := sets.NewString()
+	targetChanged := false
+	for name, route := range oc.apbRoutes {
+		if (oldPod != nil && route.isHop(namespace, oldPod)) || (newPod != nil && route.isHop(namespace, newPod)) {
+			toSync.Insert(name)
+		}
+		isTarget := newPod != nil && namespace != nil && route.isTarget(namespace, newPod)
+		if isTarget == route.targetPods.Has(key) {
+			continue
+		}
+		if isTarget {
+			route.targetPods.Insert(key)
+		} else {
+			route.targetPods.Delete(key)
+		}
+		// added pods get their routes along with their logical port and
+		// deleted pods lose them along with it
+		if oldPod != nil && newPod != nil {
+			targetChanged = true
+		}
+	}
+	if targetChanged {
+		if err := oc.ensureExternalGWRoutesForPod(newPod, oc.apbRouteGWsForPod(newPod)); err != nil {
+			klog.Errorf("Unable to update the external gateway routes of pod %s: %v", key, err)
+		}
+	}
+	oc.apbRoutesMutex.Unlock()
+
+	for _, name := range toSync.List() {
+		if err := oc.syncAPBRoute(name); err != nil {
+			klog.Errorf("Unable to sync AdminPolicyBasedExternalRoute %s: %v", name, err)
+		}
+	}
+}
+
+// syncAPBRoutesForNamespace syncs the AdminPolicyBasedExternalRoutes selecting
+// pods of the namespace, as it was (oldNamespace) or as it is (newNamespace)
+func (oc *Controller) syncAPBRoutesForNamespace(oldNamespace, newNamespace *kapi.Namespace) {
+	oc.apbRoutesMutex.Lock()
+	toSync := sets.NewString()
+	for name, route := range oc.apbRoutes {
+		if (oldNamespace != nil && route.matchesNamespace(oldNamespace)) || route.matchesNamespace(newNamespace) {
+			toSync.Insert(name)
+		}
+	}
+	oc.apbRoutesMutex.Unlock()
+
+	for _, name := range toSync.List() {
+		if err := oc.syncAPBRoute(name); err != nil {
+			klog.Errorf("Unable to sync AdminPolicyBasedExternalRoute %s: %v", name, err)
+		}
+	}
+}
+
+// updateAPBRouteStatusWithRetry sets the status of the policy of the given name, unless it is already set
+func (oc *Controller) updateAPBRouteStatusWithRetry(name string, status adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		policy, err := oc.watchFactory.GetAPBRoute(name)
+		if err != nil {
+			return err
+		}
+		if apbRouteStatusEqual(policy.Status, status) {
+			return nil
+		}
+		policy = policy.DeepCopy()
+		policy.Status = status
+		return oc.kube.UpdateAdminPolicyBasedExternalRoute(policy)
+	})
+	if retryErr != nil {
+		return fmt.Errorf("error in updating status on AdminPolicyBasedExternalRoute %s: %v", name, retryErr)
+	}
+	return nil
+}
+
+func apbRouteStatusEqual(a, b adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus) bool {
+	return a.Status == b.Status &&
+		sets.NewString(a.ActiveHops...).Equal(sets.NewString(b.ActiveHops...)) &&
+		sets.NewString(a.Messages...).Equal(sets.NewString(b.Messages...))
+}
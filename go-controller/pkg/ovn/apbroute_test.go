@@ -0,0 +1,308 @@
+package ovn
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	adminpolicybasedrouteapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/urfave/cli/v2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("OVN AdminPolicyBasedExternalRoute Operations", func() {
+	const (
+		policyName      string = "policy"
+		namespaceName   string = "namespace1"
+		gwNamespaceName string = "gateways"
+		gwPodName       string = "gw"
+		gwIP            string = "9.0.0.1"
+	)
+	var (
+		app     *cli.App
+		fakeOVN *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	newPolicy := func(nextHops adminpolicybasedrouteapi.ExternalNextHops) adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute {
+		return adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: policyName,
+			},
+			Spec: adminpolicybasedrouteapi.AdminPolicyBasedExternalRouteSpec{
+				From: adminpolicybasedrouteapi.ExternalNetworkSource{
+					NamespaceSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"name": namespaceName},
+					},
+				},
+				NextHops: nextHops,
+			},
+		}
+	}
+
+	getPolicyStatus := func() adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus {
+		policy, err := fakeOVN.fakeClient.APBRouteClient.K8sV1().AdminPolicyBasedExternalRoutes().Get(context.TODO(), policyName, metav1.GetOptions{})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return policy.Status
+	}
+
+	addRouteDelCmds := func() {
+		fExec.AddFakeCmdsNoOutputNoError([]string{
+			"ovn-nbctl --timeout=15 --if-exists --policy=src-ip lr-route-del GR_node1 10.128.1.3/32 " + gwIP,
+			"ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=bfd find Logical_Router_Static_Route output_port=rtoe-GR_node1 nexthop=" + gwIP + " bfd!=[]",
+			"ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find BFD logical_port=rtoe-GR_node1 dst_ip=" + gwIP,
+		})
+	}
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOVN = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	ginkgo.It("routes the selected pods through the static hops until the policy is deleted", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespaceT := *newNamespace(namespaceName)
+			t := newTPod(
+				"node1",
+				"10.128.1.0/24",
+				"10.128.1.2",
+				"10.128.1.1",
+				"myPod",
+				"10.128.1.3",
+				"0a:58:0a:80:01:03",
+				namespaceT.Name,
+			)
+			policy := newPolicy(adminpolicybasedrouteapi.ExternalNextHops{
+				StaticHops: []adminpolicybasedrouteapi.StaticHop{
+					{IP: gwIP, BFDEnabled: true},
+				},
+			})
+
+			t.baseCmds(fExec)
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespaceT}},
+				&v1.PodList{Items: []v1.Pod{*newPod(t.namespace, t.podName, t.nodeName, t.podIP)}},
+				&adminpolicybasedrouteapi.AdminPolicyBasedExternalRouteList{
+					Items: []adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute{policy},
+				},
+			)
+			t.populateLogicalSwitchCache(fakeOVN)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --may-exist --bfd --policy=src-ip --ecmp-symmetric-reply lr-route-add GR_node1 10.128.1.3/32 " + gwIP + " rtoe-GR_node1",
+				"ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find BFD logical_port=rtoe-GR_node1 dst_ip=" + gwIP,
+			})
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchPods()
+			fakeOVN.controller.WatchAPBRoutes()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+				Status:     apbRouteStatusSuccess,
+				ActiveHops: []string{gwIP},
+			}))
+
+			addRouteDelCmds()
+			err := fakeOVN.fakeClient.APBRouteClient.K8sV1().AdminPolicyBasedExternalRoutes().Delete(context.TODO(), policyName, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("resets the BFD parameters which are removed from the hops", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespaceT := *newNamespace(namespaceName)
+			t := newTPod(
+				"node1",
+				"10.128.1.0/24",
+				"10.128.1.2",
+				"10.128.1.1",
+				"myPod",
+				"10.128.1.3",
+				"0a:58:0a:80:01:03",
+				namespaceT.Name,
+			)
+			policy := newPolicy(adminpolicybasedrouteapi.ExternalNextHops{
+				StaticHops: []adminpolicybasedrouteapi.StaticHop{
+					{
+						IP:         gwIP,
+						BFDEnabled: true,
+						BFDParameters: adminpolicybasedrouteapi.BFDParameters{
+							MinTx:      100,
+							DetectMult: 5,
+						},
+					},
+				},
+			})
+
+			t.baseCmds(fExec)
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespaceT}},
+				&v1.PodList{Items: []v1.Pod{*newPod(t.namespace, t.podName, t.nodeName, t.podIP)}},
+				&adminpolicybasedrouteapi.AdminPolicyBasedExternalRouteList{
+					Items: []adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute{policy},
+				},
+			)
+			t.populateLogicalSwitchCache(fakeOVN)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --may-exist --bfd --policy=src-ip --ecmp-symmetric-reply lr-route-add GR_node1 10.128.1.3/32 " + gwIP + " rtoe-GR_node1",
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find BFD logical_port=rtoe-GR_node1 dst_ip=" + gwIP,
+				Output: "bfduuid\n",
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 set BFD bfduuid min_tx=100 detect_mult=5 -- clear BFD bfduuid min_rx",
+			})
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchPods()
+			fakeOVN.controller.WatchAPBRoutes()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+				Status:     apbRouteStatusSuccess,
+				ActiveHops: []string{gwIP},
+			}))
+
+			// dropping the detect multiplier clears it from the BFD entry
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find BFD logical_port=rtoe-GR_node1 dst_ip=" + gwIP,
+				Output: "bfduuid\n",
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 set BFD bfduuid min_tx=100 -- clear BFD bfduuid min_rx detect_mult",
+			})
+			updated, err := fakeOVN.fakeClient.APBRouteClient.K8sV1().AdminPolicyBasedExternalRoutes().Get(context.TODO(), policyName, metav1.GetOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			updated.Spec.NextHops.StaticHops[0].BFDParameters.DetectMult = 0
+			_, err = fakeOVN.fakeClient.APBRouteClient.K8sV1().AdminPolicyBasedExternalRoutes().Update(context.TODO(), updated, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("routes the selected pods through the gateway pods of the dynamic hops", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespaceT := *newNamespace(namespaceName)
+			gwNamespace := *newNamespace(gwNamespaceName)
+			t := newTPod(
+				"node1",
+				"10.128.1.0/24",
+				"10.128.1.2",
+				"10.128.1.1",
+				"myPod",
+				"10.128.1.3",
+				"0a:58:0a:80:01:03",
+				namespaceT.Name,
+			)
+			gwPod := *newPodWithLabels(gwNamespaceName, gwPodName, "node2", gwIP, map[string]string{"gateway": ""})
+			gwPod.Spec.HostNetwork = true
+			policy := newPolicy(adminpolicybasedrouteapi.ExternalNextHops{
+				DynamicHops: []adminpolicybasedrouteapi.DynamicHop{
+					{
+						NamespaceSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"name": gwNamespaceName},
+						},
+						PodSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"gateway": ""},
+						},
+					},
+				},
+			})
+
+			t.baseCmds(fExec)
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespaceT, gwNamespace}},
+				&v1.PodList{Items: []v1.Pod{*newPod(t.namespace, t.podName, t.nodeName, t.podIP)}},
+				&adminpolicybasedrouteapi.AdminPolicyBasedExternalRouteList{
+					Items: []adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute{policy},
+				},
+			)
+			t.populateLogicalSwitchCache(fakeOVN)
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchPods()
+			fakeOVN.controller.WatchAPBRoutes()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+				Status: apbRouteStatusSuccess,
+			}))
+
+			// the gateway pod coming up adds the routes
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --may-exist --policy=src-ip --ecmp-symmetric-reply lr-route-add GR_node1 10.128.1.3/32 " + gwIP,
+			})
+			_, err := fakeOVN.fakeClient.KubeClient.CoreV1().Pods(gwNamespaceName).Create(context.TODO(), &gwPod, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+				Status:     apbRouteStatusSuccess,
+				ActiveHops: []string{gwIP},
+			}))
+
+			// and it going away removes them
+			addRouteDelCmds()
+			err = fakeOVN.fakeClient.KubeClient.CoreV1().Pods(gwNamespaceName).Delete(context.TODO(), gwPodName, *metav1.NewDeleteOptions(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+				Status: apbRouteStatusSuccess,
+			}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("reports the invalid next hops in the status", func() {
+		app.Action = func(ctx *cli.Context) error {
+			policy := newPolicy(adminpolicybasedrouteapi.ExternalNextHops{
+				StaticHops: []adminpolicybasedrouteapi.StaticHop{
+					{IP: "not-an-ip"},
+				},
+			})
+
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{*newNamespace(namespaceName)}},
+				&adminpolicybasedrouteapi.AdminPolicyBasedExternalRouteList{
+					Items: []adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute{policy},
+				},
+			)
+			fakeOVN.controller.WatchNamespaces()
+			fakeOVN.controller.WatchAPBRoutes()
+
+			gomega.Eventually(getPolicyStatus).Should(gomega.Equal(adminpolicybasedrouteapi.AdminPolicyBasedRouteStatus{
+				Status:   apbRouteStatusFail,
+				Messages: []string{"invalid static hop IP \"not-an-ip\""},
+			}))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
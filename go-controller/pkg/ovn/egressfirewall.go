@@ -10,10 +10,17 @@ import (
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
@@ -25,29 +32,53 @@ const (
 	egressFirewallUpdateError      = "EgressFirewall Rules not correctly updated"
 )
 
+// reasons of the EgressFirewall status conditions
+const (
+	egressFirewallRulesApplied    = "RulesApplied"
+	egressFirewallRulesNotApplied = "RulesNotApplied"
+	egressFirewallRejected        = "Rejected"
+	egressFirewallAuditIgnored    = "AuditNotSupported"
+)
+
 type egressFirewall struct {
 	name        string
 	namespace   string
 	egressRules []*egressFirewallRule
+	// holds the reason each rule that could not be applied failed, by rule id
+	ruleErrors map[int]error
+	// auditAnnotated is true when the egressFirewall has the acl-audit annotation
+	auditAnnotated bool
+	// audit tells whether the deny rules are currently in audit mode
+	audit bool
 }
 
 type egressFirewallRule struct {
-	id     int
-	access egressfirewallapi.EgressFirewallRuleType
-	ports  []egressfirewallapi.EgressFirewallPort
-	to     destination
+	id      int
+	access  egressfirewallapi.EgressFirewallRuleType
+	ports   []egressfirewallapi.EgressFirewallPort
+	to      destination
+	applied bool
+	// match of the logical_router_policy/join_switch_acl created for the rule
+	match string
 }
 
 type destination struct {
 	cidrSelector string
 	dnsName      string
+	nodeSelector labels.Selector
+	// nodeAddressSet holds the IPs of the nodes matching nodeSelector and is
+	// kept up to date by nodeHandler
+	nodeAddressSet addressset.AddressSet
+	nodeHandler    *factory.Handler
 }
 
 func newEgressFirewall(egressFirewallPolicy *egressfirewallapi.EgressFirewall) *egressFirewall {
 	ef := &egressFirewall{
-		name:        egressFirewallPolicy.Name,
-		namespace:   egressFirewallPolicy.Namespace,
-		egressRules: make([]*egressFirewallRule, 0),
+		name:           egressFirewallPolicy.Name,
+		namespace:      egressFirewallPolicy.Namespace,
+		egressRules:    make([]*egressFirewallRule, 0),
+		ruleErrors:     make(map[int]error),
+		auditAnnotated: isACLAuditEnabled(egressFirewallPolicy.Annotations),
 	}
 	return ef
 }
@@ -58,8 +89,33 @@ func newEgressFirewallRule(rawEgressFirewallRule egressfirewallapi.EgressFirewal
 		access: rawEgressFirewallRule.Type,
 	}
 
+	destinations := 0
+	for _, set := range []bool{
+		rawEgressFirewallRule.To.CIDRSelector != "",
+		rawEgressFirewallRule.To.DNSName != "",
+		rawEgressFirewallRule.To.NodeSelector != nil,
+	} {
+		if set {
+			destinations++
+		}
+	}
+	if destinations > 1 {
+		return nil, fmt.Errorf("only one of cidrSelector, dnsName and nodeSelector can be set")
+	}
+
 	if rawEgressFirewallRule.To.DNSName != "" {
+		// the API rejects wildcard names, which can't be resolved, but objects
+		// stored before it did may still have them
+		if isWildcardDNSName(rawEgressFirewallRule.To.DNSName) {
+			return nil, fmt.Errorf("wildcard dnsName %s is not supported", rawEgressFirewallRule.To.DNSName)
+		}
 		efr.to.dnsName = rawEgressFirewallRule.To.DNSName
+	} else if rawEgressFirewallRule.To.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rawEgressFirewallRule.To.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		efr.to.nodeSelector = selector
 	} else {
 
 		_, _, err := net.ParseCIDR(rawEgressFirewallRule.To.CIDRSelector)
@@ -68,6 +124,14 @@ func newEgressFirewallRule(rawEgressFirewallRule egressfirewallapi.EgressFirewal
 		}
 		efr.to.cidrSelector = rawEgressFirewallRule.To.CIDRSelector
 	}
+	for _, port := range rawEgressFirewallRule.Ports {
+		if port.EndPort != 0 && port.Port == 0 {
+			return nil, fmt.Errorf("endPort %d for protocol %s requires a port", port.EndPort, port.Protocol)
+		}
+		if port.EndPort != 0 && port.EndPort < port.Port {
+			return nil, fmt.Errorf("invalid port range %d-%d for protocol %s", port.Port, port.EndPort, port.Protocol)
+		}
+	}
 	efr.ports = rawEgressFirewallRule.Ports
 
 	return efr, nil
@@ -88,6 +152,7 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 	}
 
 	ef := newEgressFirewall(egressFirewall)
+	ef.audit = getEgressFirewallAudit(nsInfo.aclAudit, ef.auditAnnotated)
 	nsInfo.egressFirewallPolicy = ef
 	var addErrors error
 	//the highest priority rule is reserved blocking all external traffic during update
@@ -111,6 +176,7 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 		if err != nil {
 			addErrors = errors.Wrapf(addErrors, "error: cannot create EgressFirewall Rule to destination %s for namespace %s - %v",
 				egressFirewallRule.To.CIDRSelector, egressFirewall.Namespace, err)
+			ef.ruleErrors[i] = err
 			continue
 
 		}
@@ -140,24 +206,36 @@ func (oc *Controller) updateEgressFirewall(oldEgressFirewall, newEgressFirewall
 	if err != nil {
 		return fmt.Errorf("cannot update egressfirewall in %s:%v", newEgressFirewall.Namespace, err)
 	}
+	if ef := nsInfo.egressFirewallPolicy; ef != nil && ef.name != newEgressFirewall.Name {
+		// the egressFirewall was rejected by addEgressFirewall, leave the applied one alone
+		nsInfo.Unlock()
+		return fmt.Errorf("error attempting to update egressFirewall %s in namespace %s which has egressFirewall %s",
+			newEgressFirewall.Name, newEgressFirewall.Namespace, ef.name)
+	}
 	addressSet := nsInfo.addressSet
+	audit := getEgressFirewallAudit(nsInfo.aclAudit, isACLAuditEnabled(newEgressFirewall.Annotations))
+	aclLoggingMeter := nsInfo.getACLLoggingMeter()
 	nsInfo.Unlock()
 	priority, err := strconv.Atoi(types.EgressFirewallStartPriority)
 	if err != nil {
 		return fmt.Errorf("cannot update egressfirewall in %s:%v", newEgressFirewall.Namespace, err)
 	}
 
-	ipv4ASHashName, ipv6ASHashName := addressSet.GetASHashNames()
-	match := generateMatch(ipv4ASHashName, ipv6ASHashName, []matchTarget{
-		{matchKindV4CIDR, "0.0.0.0/0"},
-		{matchKindV6CIDR, "::/0"},
-	},
-		[]egressfirewallapi.EgressFirewallPort{},
-	)
+	// nothing is blocked in audit mode, so there is no need to block the traffic during the update
+	if !audit {
+		ipv4ASHashName, ipv6ASHashName := addressSet.GetASHashNames()
+		match := generateMatch(ipv4ASHashName, ipv6ASHashName, []matchTarget{
+			{matchKindV4CIDR, "0.0.0.0/0"},
+			{matchKindV6CIDR, "::/0"},
+		},
+			[]egressfirewallapi.EgressFirewallPort{},
+		)
 
-	err = createEgressFirewallRules(priority, match, "drop", newEgressFirewall.Namespace+"-blockAll")
-	if err != nil {
-		return fmt.Errorf("cannot update egressfirewall in %s:%v", newEgressFirewall.Namespace, err)
+		err = createEgressFirewallRules(priority, match, "drop", newEgressFirewall.Namespace+"-blockAll",
+			getEgressFirewallACLName(newEgressFirewall.Namespace, "blockAll"), "", aclLoggingMeter)
+		if err != nil {
+			return fmt.Errorf("cannot update egressfirewall in %s:%v", newEgressFirewall.Namespace, err)
+		}
 	}
 
 	updateErrors := oc.deleteEgressFirewall(oldEgressFirewall)
@@ -175,14 +253,22 @@ func (oc *Controller) updateEgressFirewall(oldEgressFirewall, newEgressFirewall
 func (oc *Controller) deleteEgressFirewall(egressFirewall *egressfirewallapi.EgressFirewall) error {
 	klog.Infof("Deleting egress Firewall %s in namespace %s", egressFirewall.Name, egressFirewall.Namespace)
 	deleteDNS := false
+	var nodeSelectorRules []*egressFirewallRule
 
 	nsInfo := oc.getNamespaceLocked(egressFirewall.Namespace)
 	if nsInfo != nil {
+		if ef := nsInfo.egressFirewallPolicy; ef == nil || ef.name != egressFirewall.Name {
+			// the egressFirewall was rejected by addEgressFirewall, nothing was applied
+			nsInfo.Unlock()
+			return nil
+		}
 		// clear it so an error does not prevent future egressFirewalls
 		for _, rule := range nsInfo.egressFirewallPolicy.egressRules {
 			if len(rule.to.dnsName) > 0 {
 				deleteDNS = true
-				break
+			}
+			if rule.to.nodeSelector != nil {
+				nodeSelectorRules = append(nodeSelectorRules, rule)
 			}
 		}
 		nsInfo.egressFirewallPolicy = nil
@@ -192,7 +278,17 @@ func (oc *Controller) deleteEgressFirewall(egressFirewall *egressfirewallapi.Egr
 		oc.egressFirewallDNS.Delete(egressFirewall.Namespace)
 	}
 
-	return deleteEgressFirewallRules(egressFirewall.Namespace)
+	deletionErrors := deleteEgressFirewallRules(egressFirewall.Namespace)
+	for _, rule := range nodeSelectorRules {
+		if err := oc.deleteEgressFirewallNodeSelector(rule); err != nil {
+			if deletionErrors == nil {
+				deletionErrors = err
+			} else {
+				deletionErrors = errors.Wrapf(deletionErrors, "%v", err)
+			}
+		}
+	}
+	return deletionErrors
 }
 
 func (oc *Controller) updateEgressFirewallWithRetry(egressfirewall *egressfirewallapi.EgressFirewall) error {
@@ -206,49 +302,369 @@ func (oc *Controller) updateEgressFirewallWithRetry(egressfirewall *egressfirewa
 	return nil
 }
 
+// setEgressFirewallStatus fills in the status of the egressFirewall from the result of
+// adding it, err, and of each of its rules. errStatus is the summary used on failure.
+func (oc *Controller) setEgressFirewallStatus(egressFirewall *egressfirewallapi.EgressFirewall, err error, errStatus string) {
+	var rejectedBy string
+	var ruleStatuses []egressfirewallapi.EgressFirewallRuleStatus
+	auditIgnored := false
+	nsInfo := oc.getNamespaceLocked(egressFirewall.Namespace)
+	if nsInfo != nil {
+		auditIgnored = config.Gateway.Mode == config.GatewayModeLocal &&
+			(nsInfo.aclAudit || isACLAuditEnabled(egressFirewall.Annotations))
+		if ef := nsInfo.egressFirewallPolicy; ef != nil && ef.name != egressFirewall.Name {
+			rejectedBy = ef.name
+		} else if ef != nil {
+			ruleStatuses = oc.getEgressFirewallRuleStatuses(ef, len(egressFirewall.Spec.Egress))
+		}
+		nsInfo.Unlock()
+	}
+
+	notApplied := 0
+	for _, ruleStatus := range ruleStatuses {
+		if !ruleStatus.Applied {
+			notApplied++
+		}
+	}
+	ready := metav1.Condition{
+		Type:               egressfirewallapi.EgressFirewallReady,
+		ObservedGeneration: egressFirewall.Generation,
+	}
+	switch {
+	case rejectedBy != "":
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = egressFirewallRejected
+		ready.Message = fmt.Sprintf("namespace %s already has EgressFirewall %s, only one EgressFirewall per namespace is supported",
+			egressFirewall.Namespace, rejectedBy)
+	case err != nil:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = egressFirewallRulesNotApplied
+		ready.Message = err.Error()
+	case notApplied > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = egressFirewallRulesNotApplied
+		ready.Message = fmt.Sprintf("%d of %d rules are not applied", notApplied, len(ruleStatuses))
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = egressFirewallRulesApplied
+		ready.Message = "all the rules are applied"
+	}
+	degraded := ready
+	degraded.Type = egressfirewallapi.EgressFirewallDegraded
+	if ready.Status == metav1.ConditionTrue {
+		degraded.Status = metav1.ConditionFalse
+	} else {
+		degraded.Status = metav1.ConditionTrue
+	}
+	if ready.Status == metav1.ConditionTrue && auditIgnored {
+		// the deny rules cannot be audited with logical router policies, they are enforced
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = egressFirewallAuditIgnored
+		degraded.Message = fmt.Sprintf("the %s annotation is not supported in local gateway mode, the Deny rules are enforced",
+			aclAuditAnnotation)
+	}
+
+	meta.SetStatusCondition(&egressFirewall.Status.Conditions, ready)
+	meta.SetStatusCondition(&egressFirewall.Status.Conditions, degraded)
+	egressFirewall.Status.Rules = ruleStatuses
+	if ready.Status == metav1.ConditionTrue {
+		egressFirewall.Status.Status = egressFirewallAppliedCorrectly
+	} else {
+		egressFirewall.Status.Status = errStatus
+	}
+}
+
+// getEgressFirewallRuleStatuses returns the status of the first numRules rules of the egressFirewall
+func (oc *Controller) getEgressFirewallRuleStatuses(ef *egressFirewall, numRules int) []egressfirewallapi.EgressFirewallRuleStatus {
+	rules := make(map[int]*egressFirewallRule, len(ef.egressRules))
+	for _, rule := range ef.egressRules {
+		rules[rule.id] = rule
+	}
+	ruleStatuses := make([]egressfirewallapi.EgressFirewallRuleStatus, 0, numRules)
+	for i := 0; i < numRules; i++ {
+		ruleStatus := egressfirewallapi.EgressFirewallRuleStatus{Index: i}
+		rule := rules[i]
+		if err, ok := ef.ruleErrors[i]; ok {
+			ruleStatus.Message = err.Error()
+		} else if rule == nil {
+			ruleStatus.Message = "rule ignored, the EgressFirewall has too many rules"
+		} else if !rule.applied {
+			ruleStatus.Message = "rule not applied because of an error in another rule"
+		} else {
+			ruleStatus.Applied = true
+		}
+		if rule != nil && rule.to.dnsName != "" && oc.egressFirewallDNS != nil {
+			resolvedIPs := oc.egressFirewallDNS.GetResolvedIPCount(rule.to.dnsName)
+			ruleStatus.ResolvedIPs = &resolvedIPs
+		}
+		ruleStatuses = append(ruleStatuses, ruleStatus)
+	}
+	return ruleStatuses
+}
+
+// updateEgressFirewallDNSStatus refreshes the number of resolved IPs of the dnsName rules
+// in the status of the egressFirewall of the given namespace
+func (oc *Controller) updateEgressFirewallDNSStatus(namespace string) {
+	var name string
+	resolvedIPs := make(map[int]int)
+	nsInfo := oc.getNamespaceLocked(namespace)
+	if nsInfo == nil {
+		return
+	}
+	if ef := nsInfo.egressFirewallPolicy; ef != nil {
+		name = ef.name
+		for _, rule := range ef.egressRules {
+			if rule.to.dnsName != "" {
+				resolvedIPs[rule.id] = oc.egressFirewallDNS.GetResolvedIPCount(rule.to.dnsName)
+			}
+		}
+	}
+	nsInfo.Unlock()
+	if name == "" {
+		return
+	}
+
+	egressFirewall, err := oc.watchFactory.GetEgressFirewall(namespace, name)
+	if err != nil {
+		klog.Errorf("Cannot get EgressFirewall %s/%s to update its status: %v", namespace, name, err)
+		return
+	}
+	egressFirewall = egressFirewall.DeepCopy()
+	changed := false
+	for i := range egressFirewall.Status.Rules {
+		ruleStatus := &egressFirewall.Status.Rules[i]
+		count, ok := resolvedIPs[ruleStatus.Index]
+		if !ok || (ruleStatus.ResolvedIPs != nil && *ruleStatus.ResolvedIPs == count) {
+			continue
+		}
+		ruleStatus.ResolvedIPs = &count
+		changed = true
+	}
+	if changed {
+		if err := oc.updateEgressFirewallWithRetry(egressFirewall); err != nil {
+			klog.Error(err)
+		}
+	}
+}
+
 func (oc *Controller) addEgressFirewallRules(hashedAddressSetNameIPv4, hashedAddressSetNameIPv6, namespace string, efStartPriority int) error {
-	var err error
 	ef := oc.namespaces[namespace].egressFirewallPolicy
+	aclLogging := oc.namespaces[namespace].aclLogging
+	aclLoggingMeter := oc.namespaces[namespace].getACLLoggingMeter()
 	for _, rule := range ef.egressRules {
-		var action string
-		var matchTargets []matchTarget
-		if rule.access == egressfirewallapi.EgressFirewallRuleAllow {
-			action = "allow"
-		} else {
-			action = "drop"
+		err := oc.addEgressFirewallRule(ef, rule, hashedAddressSetNameIPv4, hashedAddressSetNameIPv6, efStartPriority, aclLogging, aclLoggingMeter)
+		if err != nil {
+			ef.ruleErrors[rule.id] = err
+			// do not leave the rules added so far, and their address sets and node handlers, behind
+			if cleanupErr := oc.deleteEgressFirewallRulesResources(ef); cleanupErr != nil {
+				klog.Errorf("Failed to clean up egressFirewall %s in namespace %s: %v", ef.name, ef.namespace, cleanupErr)
+			}
+			return err
 		}
-		if rule.to.cidrSelector != "" {
-			if utilnet.IsIPv6CIDRString(rule.to.cidrSelector) {
-				matchTargets = []matchTarget{{matchKindV6CIDR, rule.to.cidrSelector}}
+		rule.applied = true
+	}
+	return nil
+}
+
+// deleteEgressFirewallRulesResources deletes the ACLs of the rules of the egressFirewall, as
+// well as the DNS and node address sets and the node handlers they use.
+// Caller must hold the namespace's namespaceInfo object lock.
+func (oc *Controller) deleteEgressFirewallRulesResources(ef *egressFirewall) error {
+	deleteDNS := false
+	deletionErrors := deleteEgressFirewallRules(ef.namespace)
+	for _, rule := range ef.egressRules {
+		rule.applied = false
+		if rule.to.dnsName != "" {
+			deleteDNS = true
+		}
+		if err := oc.deleteEgressFirewallNodeSelector(rule); err != nil {
+			if deletionErrors == nil {
+				deletionErrors = err
 			} else {
-				matchTargets = []matchTarget{{matchKindV4CIDR, rule.to.cidrSelector}}
+				deletionErrors = errors.Wrapf(deletionErrors, "%v", err)
 			}
+		}
+	}
+	if deleteDNS {
+		oc.egressFirewallDNS.Delete(ef.namespace)
+	}
+	return deletionErrors
+}
+
+// addEgressFirewallRule creates the logical_router_policy/join_switch_acl for a single egressFirewall rule
+func (oc *Controller) addEgressFirewallRule(ef *egressFirewall, rule *egressFirewallRule, hashedAddressSetNameIPv4, hashedAddressSetNameIPv6 string,
+	efStartPriority int, aclLogging ACLLoggingLevels, aclLoggingMeter string) error {
+	var err error
+	var action string
+	var aclLoggingSeverity string
+	var matchTargets []matchTarget
+	aclName := getEgressFirewallACLName(ef.namespace, strconv.Itoa(rule.id))
+	if rule.access == egressfirewallapi.EgressFirewallRuleAllow {
+		action = "allow"
+		aclLoggingSeverity = aclLogging.Allow
+	} else {
+		action = getDenyACLAction(ef.audit)
+		aclLoggingSeverity = aclLogging.Deny
+		if ef.audit {
+			aclName = getEgressFirewallACLName(getDenyACLName(ef.namespace, true), strconv.Itoa(rule.id))
+			aclLoggingSeverity = getACLLoggingSeverity(aclLoggingSeverity)
+		}
+	}
+	if rule.to.cidrSelector != "" {
+		if utilnet.IsIPv6CIDRString(rule.to.cidrSelector) {
+			matchTargets = []matchTarget{{matchKindV6CIDR, rule.to.cidrSelector}}
 		} else {
-			// rule based on DNS NAME
-			dnsNameAddressSets, err := oc.egressFirewallDNS.Add(ef.namespace, rule.to.dnsName)
-			if err != nil {
-				return fmt.Errorf("error with EgressFirewallDNS - %v", err)
-			}
-			dnsNameIPv4ASHashName, dnsNameIPv6ASHashName := dnsNameAddressSets.GetASHashNames()
-			if dnsNameIPv4ASHashName != "" {
-				matchTargets = append(matchTargets, matchTarget{matchKindV4AddressSet, dnsNameIPv4ASHashName})
-			}
-			if dnsNameIPv6ASHashName != "" {
-				matchTargets = append(matchTargets, matchTarget{matchKindV6AddressSet, dnsNameIPv6ASHashName})
-			}
+			matchTargets = []matchTarget{{matchKindV4CIDR, rule.to.cidrSelector}}
 		}
-		match := generateMatch(hashedAddressSetNameIPv4, hashedAddressSetNameIPv6, matchTargets, rule.ports)
-		err = createEgressFirewallRules(efStartPriority-rule.id, match, action, ef.namespace)
+	} else if rule.to.nodeSelector != nil {
+		// rule based on the IPs of the selected nodes
+		err = oc.addEgressFirewallNodeSelector(ef.namespace, rule)
 		if err != nil {
 			return err
 		}
+		nodeIPv4ASHashName, nodeIPv6ASHashName := rule.to.nodeAddressSet.GetASHashNames()
+		if nodeIPv4ASHashName != "" {
+			matchTargets = append(matchTargets, matchTarget{matchKindV4AddressSet, nodeIPv4ASHashName})
+		}
+		if nodeIPv6ASHashName != "" {
+			matchTargets = append(matchTargets, matchTarget{matchKindV6AddressSet, nodeIPv6ASHashName})
+		}
+	} else {
+		// rule based on DNS NAME
+		dnsNameAddressSets, err := oc.egressFirewallDNS.Add(ef.namespace, rule.to.dnsName)
+		if err != nil {
+			return fmt.Errorf("error with EgressFirewallDNS - %v", err)
+		}
+		dnsNameIPv4ASHashName, dnsNameIPv6ASHashName := dnsNameAddressSets.GetASHashNames()
+		if dnsNameIPv4ASHashName != "" {
+			matchTargets = append(matchTargets, matchTarget{matchKindV4AddressSet, dnsNameIPv4ASHashName})
+		}
+		if dnsNameIPv6ASHashName != "" {
+			matchTargets = append(matchTargets, matchTarget{matchKindV6AddressSet, dnsNameIPv6ASHashName})
+		}
+	}
+	rule.match = generateMatch(hashedAddressSetNameIPv4, hashedAddressSetNameIPv6, matchTargets, rule.ports)
+	return createEgressFirewallRules(efStartPriority-rule.id, rule.match, action, ef.namespace, aclName, aclLoggingSeverity, aclLoggingMeter)
+}
+
+// getEgressFirewallNodeAddrSetName returns the name of the address set holding the
+// IPs of the nodes selected by the egressFirewall rule with the given id
+func getEgressFirewallNodeAddrSetName(namespace string, ruleID int) string {
+	return fmt.Sprintf("%s.egressfirewall-nodes.%d", namespace, ruleID)
+}
+
+// getEgressFirewallNodeIPs returns the internal IPs and the primary interface IPs of the node
+func getEgressFirewallNodeIPs(node *kapi.Node) sets.String {
+	nodeIPs := sets.NewString()
+	v4Addr, v6Addr := getNodeInternalAddrs(node)
+	for _, ip := range []net.IP{v4Addr, v6Addr} {
+		if ip != nil {
+			nodeIPs.Insert(ip.String())
+		}
+	}
+	v4IfAddr, v6IfAddr, err := util.ParseNodePrimaryIfAddr(node)
+	if err != nil {
+		klog.V(5).Infof("Unable to get primary interface addresses of node %s: %v", node.Name, err)
+		return nodeIPs
+	}
+	for _, ifAddr := range []string{v4IfAddr, v6IfAddr} {
+		if ifAddr == "" {
+			continue
+		}
+		ip, _, err := net.ParseCIDR(ifAddr)
+		if err != nil {
+			klog.Errorf("Invalid primary interface address %s on node %s: %v", ifAddr, node.Name, err)
+			continue
+		}
+		nodeIPs.Insert(ip.String())
+	}
+	return nodeIPs
+}
+
+func ipsFromStrings(ips sets.String) []net.IP {
+	netIPs := make([]net.IP, 0, ips.Len())
+	for _, ip := range ips.List() {
+		netIPs = append(netIPs, net.ParseIP(ip))
+	}
+	return netIPs
+}
+
+// addEgressFirewallNodeSelector creates the address set for an egressFirewall rule
+// with a nodeSelector destination and starts watching the selected nodes so that
+// their IPs are added to or removed from it as nodes come and go
+func (oc *Controller) addEgressFirewallNodeSelector(namespace string, rule *egressFirewallRule) error {
+	var err error
+	rule.to.nodeAddressSet, err = oc.addressSetFactory.NewAddressSet(getEgressFirewallNodeAddrSetName(namespace, rule.id), nil)
+	if err != nil {
+		return fmt.Errorf("cannot create address set for egressFirewall rule %d in namespace %s: %v",
+			rule.id, namespace, err)
+	}
+	addressSet := rule.to.nodeAddressSet
+	rule.to.nodeHandler = oc.watchFactory.AddFilteredNodeHandler(rule.to.nodeSelector, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			node := obj.(*kapi.Node)
+			if err := addressSet.AddIPs(ipsFromStrings(getEgressFirewallNodeIPs(node))); err != nil {
+				klog.Errorf("Failed to add IPs of node %s to egressFirewall address set %s: %v",
+					node.Name, addressSet.GetName(), err)
+			}
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			oldNode := old.(*kapi.Node)
+			newNode := newer.(*kapi.Node)
+			oldIPs := getEgressFirewallNodeIPs(oldNode)
+			newIPs := getEgressFirewallNodeIPs(newNode)
+			if oldIPs.Equal(newIPs) {
+				return
+			}
+			if err := addressSet.AddIPs(ipsFromStrings(newIPs.Difference(oldIPs))); err != nil {
+				klog.Errorf("Failed to add IPs of node %s to egressFirewall address set %s: %v",
+					newNode.Name, addressSet.GetName(), err)
+			}
+			if err := addressSet.DeleteIPs(ipsFromStrings(oldIPs.Difference(newIPs))); err != nil {
+				klog.Errorf("Failed to delete IPs of node %s from egressFirewall address set %s: %v",
+					newNode.Name, addressSet.GetName(), err)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			node := obj.(*kapi.Node)
+			if err := addressSet.DeleteIPs(ipsFromStrings(getEgressFirewallNodeIPs(node))); err != nil {
+				klog.Errorf("Failed to delete IPs of node %s from egressFirewall address set %s: %v",
+					node.Name, addressSet.GetName(), err)
+			}
+		},
+	}, nil)
+	return nil
+}
+
+// deleteEgressFirewallNodeSelector stops watching the nodes selected by an egressFirewall
+// rule and destroys the address set holding their IPs
+func (oc *Controller) deleteEgressFirewallNodeSelector(rule *egressFirewallRule) error {
+	if rule.to.nodeHandler != nil {
+		oc.watchFactory.RemoveNodeHandler(rule.to.nodeHandler)
+		rule.to.nodeHandler = nil
+	}
+	if rule.to.nodeAddressSet != nil {
+		if err := rule.to.nodeAddressSet.Destroy(); err != nil {
+			return fmt.Errorf("failed to destroy egressFirewall address set %s: %v",
+				rule.to.nodeAddressSet.GetName(), err)
+		}
+		rule.to.nodeAddressSet = nil
 	}
 	return nil
 }
 
+// getEgressFirewallACLName returns the name of the ACL of an egressFirewall rule, made of the
+// namespace and the rule index, that identifies the rule in the ACL logs
+func getEgressFirewallACLName(namespace, ruleIndex string) string {
+	suffix := "_" + ruleIndex
+	return fmt.Sprintf("%.*s%s", 63-len(suffix), namespace, suffix)
+}
+
 // createEgressFirewallRules uses the previously generated elements and creates the
-// logical_router_policy/join_switch_acl for a specific egressFirewallRouter
-func createEgressFirewallRules(priority int, match, action, externalID string) error {
+// logical_router_policy/join_switch_acl for a specific egressFirewallRouter. The
+// join_switch_acl is named aclName and logs with the aclLogging severity, if set, rate limited
+// by the aclLoggingMeter meter. Logging is not supported by logical_router_policies.
+func createEgressFirewallRules(priority int, match, action, externalID, aclName, aclLogging, aclLoggingMeter string) error {
 	if config.Gateway.Mode == config.GatewayModeLocal {
 		_, stderr, err := util.RunOVNNbctl("--id=@logical_router_policy", "create", "logical_router_policy",
 			fmt.Sprintf("priority=%d", priority),
@@ -273,6 +689,9 @@ func createEgressFirewallRules(priority int, match, action, externalID string) e
 			_, stderr, err := util.RunOVNNbctl("--id=@acl", "create", "acl",
 				fmt.Sprintf("priority=%d", priority),
 				fmt.Sprintf("direction=%s", fromLport), match, "action="+action,
+				fmt.Sprintf("log=%t", aclLogging != ""), fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
+				fmt.Sprintf("meter=%s", aclLoggingMeter),
+				fmt.Sprintf("name=%s", aclName),
 				fmt.Sprintf("external-ids:egressFirewall=%s", externalID),
 				"--", "add", "logical_switch", types.OVNJoinSwitch,
 				"acls", "@acl")
@@ -290,6 +709,94 @@ func createEgressFirewallRules(priority int, match, action, externalID string) e
 	return nil
 }
 
+// setEgressFirewallACLLogging updates the logging of the join switch ACLs of the egressFirewall
+// in the namespace to the given levels. Deny ACLs in audit mode are always logged.
+func setEgressFirewallACLLogging(namespace string, aclLogging ACLLoggingLevels, audit bool) error {
+	if config.Gateway.Mode == config.GatewayModeLocal {
+		return nil
+	}
+	denySeverity := aclLogging.Deny
+	if audit {
+		denySeverity = getACLLoggingSeverity(denySeverity)
+	}
+	for _, acl := range []struct{ action, severity string }{{"allow", aclLogging.Allow}, {getDenyACLAction(audit), denySeverity}} {
+		action, aclLoggingSeverity := acl.action, acl.severity
+		stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find", "ACL",
+			fmt.Sprintf("external-ids:egressFirewall=%s", namespace), "action="+action)
+		if err != nil {
+			return fmt.Errorf("cannot find the egressFirewall ACLs of namespace %s, stderr: %q (%v)", namespace, stderr, err)
+		}
+		for _, uuid := range strings.Fields(stdout) {
+			_, stderr, err := util.RunOVNNbctl("set", "acl", uuid,
+				fmt.Sprintf("log=%t", aclLoggingSeverity != ""), fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLoggingSeverity)))
+			if err != nil {
+				return fmt.Errorf("failed to modify the egressFirewall ACL %s of namespace %s, stderr: %q (%v)",
+					uuid, namespace, stderr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// getEgressFirewallAudit returns whether the deny rules of an egressFirewall are in audit
+// mode, given the acl-audit annotations of its namespace and of the egressFirewall itself.
+// In local gateway mode the rules are logical router policies, which cannot log, so the
+// annotations are ignored and the deny rules are always enforced.
+func getEgressFirewallAudit(namespaceAudit, egressFirewallAudit bool) bool {
+	if config.Gateway.Mode == config.GatewayModeLocal {
+		return false
+	}
+	return namespaceAudit || egressFirewallAudit
+}
+
+// updateEgressFirewallAudit applies a change of the acl-audit annotation of the egressFirewall
+func (oc *Controller) updateEgressFirewallAudit(egressFirewall *egressfirewallapi.EgressFirewall) error {
+	nsInfo := oc.getNamespaceLocked(egressFirewall.Namespace)
+	if nsInfo == nil {
+		return nil
+	}
+	defer nsInfo.Unlock()
+	ef := nsInfo.egressFirewallPolicy
+	if ef == nil || ef.name != egressFirewall.Name {
+		return nil
+	}
+	ef.auditAnnotated = isACLAuditEnabled(egressFirewall.Annotations)
+	return oc.setEgressFirewallAudit(ef, getEgressFirewallAudit(nsInfo.aclAudit, ef.auditAnnotated), nsInfo.aclLogging)
+}
+
+// setEgressFirewallAudit switches the deny rules of the egressFirewall in or out of audit mode
+func (oc *Controller) setEgressFirewallAudit(ef *egressFirewall, audit bool, aclLogging ACLLoggingLevels) error {
+	if ef.audit == audit {
+		return nil
+	}
+	aclLoggingSeverity := aclLogging.Deny
+	if audit {
+		aclLoggingSeverity = getACLLoggingSeverity(aclLoggingSeverity)
+	}
+	for _, rule := range ef.egressRules {
+		if rule.access == egressfirewallapi.EgressFirewallRuleAllow || !rule.applied {
+			continue
+		}
+		stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find", "ACL",
+			rule.match, "action="+getDenyACLAction(ef.audit), fmt.Sprintf("external-ids:egressFirewall=%s", ef.namespace))
+		if err != nil {
+			return fmt.Errorf("cannot find the egressFirewall rule %d of namespace %s, stderr: %q (%v)",
+				rule.id, ef.namespace, stderr, err)
+		}
+		for _, uuid := range strings.Fields(stdout) {
+			_, stderr, err := util.RunOVNNbctl("set", "ACL", uuid, "action="+getDenyACLAction(audit),
+				fmt.Sprintf("log=%t", aclLoggingSeverity != ""), fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLoggingSeverity)),
+				fmt.Sprintf("name=%s", getEgressFirewallACLName(getDenyACLName(ef.namespace, audit), strconv.Itoa(rule.id))))
+			if err != nil {
+				return fmt.Errorf("failed to set audit mode to %t for the egressFirewall rule %d of namespace %s, stderr: %q (%v)",
+					audit, rule.id, ef.namespace, stderr, err)
+			}
+		}
+	}
+	ef.audit = audit
+	return nil
+}
+
 // deleteEgressFirewallRules delete the specific logical router policy/join switch Acls
 func deleteEgressFirewallRules(externalID string) error {
 	var deletionErrors error
@@ -425,19 +932,19 @@ func egressGetL4Match(ports []egressfirewallapi.EgressFirewallPort) string {
 			if port.Port == 0 {
 				udpString = "udp"
 			} else {
-				udpString = fmt.Sprintf("%s udp.dst == %d ||", udpString, port.Port)
+				udpString = fmt.Sprintf("%s %s ||", udpString, egressGetPortMatch("udp", port))
 			}
 		} else if kapi.Protocol(port.Protocol) == kapi.ProtocolTCP && tcpString != "tcp" {
 			if port.Port == 0 {
 				tcpString = "tcp"
 			} else {
-				tcpString = fmt.Sprintf("%s tcp.dst == %d ||", tcpString, port.Port)
+				tcpString = fmt.Sprintf("%s %s ||", tcpString, egressGetPortMatch("tcp", port))
 			}
 		} else if kapi.Protocol(port.Protocol) == kapi.ProtocolSCTP && sctpString != "sctp" {
 			if port.Port == 0 {
 				sctpString = "sctp"
 			} else {
-				sctpString = fmt.Sprintf("%s sctp.dst == %d ||", sctpString, port.Port)
+				sctpString = fmt.Sprintf("%s %s ||", sctpString, egressGetPortMatch("sctp", port))
 			}
 		}
 	}
@@ -479,6 +986,15 @@ func egressGetL4Match(ports []egressfirewallapi.EgressFirewallPort) string {
 	return fmt.Sprintf("(%s)", l4Match)
 }
 
+// egressGetPortMatch generates the match on the destination port, or port range,
+// of an egressFirewall rule port for the given protocol
+func egressGetPortMatch(protocol string, port egressfirewallapi.EgressFirewallPort) string {
+	if port.EndPort > port.Port {
+		return fmt.Sprintf("(%s.dst >= %d && %s.dst <= %d)", protocol, port.Port, protocol, port.EndPort)
+	}
+	return fmt.Sprintf("%s.dst == %d", protocol, port.Port)
+}
+
 func getClusterSubnetsExclusion() string {
 	var exclusion string
 	for _, clusterSubnet := range config.Default.ClusterSubnets {
@@ -3,6 +3,7 @@ package ovn
 import (
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 )
 
@@ -22,9 +25,16 @@ type EgressDNS struct {
 	dnsEntries map[string]*dnsEntry
 	// allows for the creation of addresssets
 	addressSetFactory addressset.AddressSetFactory
+	// called with each namespace using a dnsName when the IPs it resolves to change,
+	// from a goroutine of its own so that it may take the namespace lock
+	onUpdate func(namespace string)
+	// the namespaces to call onUpdate with
+	updates workqueue.Interface
 
 	// Report change when Add operation is done
-	added          chan string
+	added chan struct{}
+	// the dnsNames added since the goroutine of Run last resolved them
+	pendingNames   []string
 	stopChan       chan struct{}
 	controllerStop <-chan struct{}
 }
@@ -39,6 +49,11 @@ type dnsEntry struct {
 	dnsAddressSet addressset.AddressSet
 }
 
+// isWildcardDNSName returns true if the dnsName is of the form *.example.com
+func isWildcardDNSName(dnsName string) bool {
+	return strings.HasPrefix(dnsName, "*.")
+}
+
 func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, controllerStop <-chan struct{}) (*EgressDNS, error) {
 	dnsInfo, err := util.NewDNS("/etc/resolv.conf")
 	if err != nil {
@@ -50,7 +65,8 @@ func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, controllerStop
 		dnsEntries:        make(map[string]*dnsEntry),
 		addressSetFactory: addressSetFactory,
 
-		added:          make(chan string, 1),
+		updates:        workqueue.New(),
+		added:          make(chan struct{}, 1),
 		stopChan:       make(chan struct{}),
 		controllerStop: controllerStop,
 	}
@@ -114,22 +130,59 @@ func (e *EgressDNS) Update(dns string) (bool, error) {
 
 func (e *EgressDNS) updateEntryForName(dnsName string) error {
 	e.lock.Lock()
-	defer e.lock.Unlock()
+	entry, ok := e.dnsEntries[dnsName]
+	if !ok {
+		// deleted since it was queried
+		e.lock.Unlock()
+		return nil
+	}
 	ips := e.dns.GetIPs(dnsName)
-	e.dnsEntries[dnsName].dnsResolves = ips
+	changed := !ipsToStringSet(entry.dnsResolves).Equal(ipsToStringSet(ips))
+	entry.dnsResolves = ips
+	namespaces := make([]string, 0, len(entry.namespaces))
+	for namespace := range entry.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
 
-	if err := e.dnsEntries[dnsName].dnsAddressSet.SetIPs(ips); err != nil {
+	if err := entry.dnsAddressSet.SetIPs(ips); err != nil {
+		e.lock.Unlock()
 		return fmt.Errorf("cannot add IPs from EgressFirewall AddressSet %s: %v", dnsName, err)
 	}
+	e.lock.Unlock()
+	if changed && e.onUpdate != nil {
+		for _, namespace := range namespaces {
+			e.updates.Add(namespace)
+		}
+	}
 	return nil
 }
 
+func ipsToStringSet(ips []net.IP) sets.String {
+	ipSet := sets.NewString()
+	for _, ip := range ips {
+		ipSet.Insert(ip.String())
+	}
+	return ipSet
+}
+
+// GetResolvedIPCount returns the number of IPs the dnsName currently resolves to
+func (e *EgressDNS) GetResolvedIPCount(dnsName string) int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if entry, ok := e.dnsEntries[dnsName]; ok {
+		return len(entry.dnsResolves)
+	}
+	return 0
+}
+
 // Run spawns a goroutine that handles updates to the dns entries for dnsNames used in
 // EgressFirewalls. The loop runs after receiving one of two signals
 // 1. a new dnsName has been added and a signal is sent to add the new DNS name, if an
 //    EgressFirewall uses a DNS name already added by another egressFirewall the previous
 //    entry is used
 // 2. If the defaultInterval has run (30 min) without updating the DNS server is manually queried
+// It also spawns the goroutine calling onUpdate for the namespaces whose dnsNames resolve
+// to new IPs, so that the resolution is never held up by the namespace locks or the API.
 func (e *EgressDNS) Run(defaultInterval time.Duration) {
 	var dnsName string
 	var ttl time.Time
@@ -137,15 +190,28 @@ func (e *EgressDNS) Run(defaultInterval time.Duration) {
 	// initially the next DNS Query happens at the default interval
 	durationTillNextQuery := defaultInterval
 	go func() {
+		for {
+			namespace, shutdown := e.updates.Get()
+			if shutdown {
+				return
+			}
+			e.onUpdate(namespace.(string))
+			e.updates.Done(namespace)
+		}
+	}()
+	go func() {
+		defer e.updates.ShutDown()
 		for {
 			// Wait for the given duration or until something gets added
 			select {
-			case dnsName := <-e.added:
-				if err := e.dns.Add(dnsName); err != nil {
-					utilruntime.HandleError(err)
-				}
-				if err := e.updateEntryForName(dnsName); err != nil {
-					utilruntime.HandleError(err)
+			case <-e.added:
+				for _, dnsName := range e.takePendingNames() {
+					if err := e.dns.Add(dnsName); err != nil {
+						utilruntime.HandleError(err)
+					}
+					if err := e.updateEntryForName(dnsName); err != nil {
+						utilruntime.HandleError(err)
+					}
 				}
 			case <-time.After(durationTillNextQuery):
 				if len(dnsName) > 0 {
@@ -178,6 +244,28 @@ func (e *EgressDNS) Shutdown() {
 	close(e.stopChan)
 }
 
+// signalAdded queues the dnsName for the goroutine of Run to resolve it. It does not
+// block, so that Add does not wait for the DNS queries in progress.
+// Caller must hold e.lock.
 func (e *EgressDNS) signalAdded(dnsName string) {
-	e.added <- dnsName
+	e.pendingNames = append(e.pendingNames, dnsName)
+	select {
+	case e.added <- struct{}{}:
+	default:
+		// already signaled, the pending names are all taken at once
+	}
+}
+
+// takePendingNames returns the dnsNames added since the last call that are still in use
+func (e *EgressDNS) takePendingNames() []string {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	dnsNames := make([]string, 0, len(e.pendingNames))
+	for _, dnsName := range e.pendingNames {
+		if _, ok := e.dnsEntries[dnsName]; ok {
+			dnsNames = append(dnsNames, dnsName)
+		}
+	}
+	e.pendingNames = nil
+	return dnsNames
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	"github.com/miekg/dns"
+	"github.com/onsi/gomega"
 	utilnet "k8s.io/utils/net"
 )
 
@@ -427,3 +428,33 @@ func (e *EgressDNS) getDNSEntry(dnsName string) (map[string]struct{}, []net.IP,
 
 	return nil, nil, nil
 }
+
+func TestAddDoesNotWaitForTheQueries(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	config.IPv4Mode = true
+	config.IPv6Mode = false
+	mockDnsOps := new(util_mocks.DNSOps)
+	util.SetDNSLibOpsMockInst(mockDnsOps)
+	mockDnsOps.On("ClientConfigFromFile", mock.AnythingOfType("string")).Return(&dns.ClientConfig{
+		Servers: []string{"1.1.1.1"},
+		Port:    "1234"}, nil).Once()
+	egressDNS, err := NewEgressDNS(addressset.NewFakeAddressSetFactory(), make(chan struct{}))
+	assert.Nil(t, err)
+
+	// nothing resolves the names as Run is not called
+	done := make(chan struct{})
+	go func() {
+		for _, dnsName := range []string{"www.test.com", "www.test.org", "www.test.net"} {
+			_, err := egressDNS.Add("namespace1", dnsName)
+			assert.Nil(t, err)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout: Add is blocked")
+	}
+	assert.Equal(t, []string{"www.test.com", "www.test.org", "www.test.net"}, egressDNS.takePendingNames())
+	mockDnsOps.AssertExpectations(t)
+}
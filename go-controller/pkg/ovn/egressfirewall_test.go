@@ -16,6 +16,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -236,6 +237,54 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for local gateway mode",
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+		ginkgo.It("enforces the deny rules of an egressfirewall with the acl-audit annotation", func() {
+			app.Action = func(ctx *cli.Context) error {
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --id=@logical_router_policy create logical_router_policy priority=9999 match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && ip4.dst != 10.128.0.0/14\" action=drop external-ids:egressFirewall=namespace1 -- add logical_router ovn_cluster_router policies @logical_router_policy",
+				})
+				namespace1 := *newNamespace("namespace1")
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Deny",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				})
+				egressFirewall.Annotations = map[string]string{aclAuditAnnotation: "true"}
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Eventually(func() *metav1.Condition {
+					ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					return meta.FindStatusCondition(ef.Status.Conditions, egressfirewallapi.EgressFirewallDegraded)
+				}).ShouldNot(gomega.BeNil())
+				ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(meta.IsStatusConditionTrue(ef.Status.Conditions, egressfirewallapi.EgressFirewallReady)).To(gomega.BeTrue())
+				degraded := meta.FindStatusCondition(ef.Status.Conditions, egressfirewallapi.EgressFirewallDegraded)
+				gomega.Expect(degraded.Status).To(gomega.Equal(metav1.ConditionTrue))
+				gomega.Expect(degraded.Reason).To(gomega.Equal(egressFirewallAuditIgnored))
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 		ginkgo.It("correctly deletes an egressfirewall", func() {
 			app.Action = func(ctx *cli.Context) error {
 				const (
@@ -419,7 +468,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 				)
 				fExec.AddFakeCmdsNoOutputNoError([]string{
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
-					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
 				})
 
 				namespace1 := *newNamespace("namespace1")
@@ -476,7 +525,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 				)
 				fExec.AddFakeCmdsNoOutputNoError([]string{
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip6.dst == 2002::1234:abcd:ffff:c0a8:101/64) && (ip4.src == $a10481622940199974102 || ip6.src == $a10481620741176717680) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
-					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip6.dst == 2002::1234:abcd:ffff:c0a8:101/64) && (ip4.src == $a10481622940199974102 || ip6.src == $a10481620741176717680) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip6.dst == 2002::1234:abcd:ffff:c0a8:101/64) && (ip4.src == $a10481622940199974102 || ip6.src == $a10481620741176717680) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
 				})
 
 				namespace1 := *newNamespace("namespace1")
@@ -534,7 +583,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 				)
 				fExec.AddFakeCmdsNoOutputNoError([]string{
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && ((udp && ( udp.dst == 100 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1",
-					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && ((udp && ( udp.dst == 100 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && ((udp && ( udp.dst == 100 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
 				})
 				namespace1 := *newNamespace("namespace1")
 				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
@@ -586,6 +635,587 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+		ginkgo.It("correctly creates an egressfirewall allowing traffic to selected nodes", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const (
+					node1Name string = "node1"
+					node2Name string = "node2"
+				)
+				nodeAddrSetName := getEgressFirewallNodeAddrSetName("namespace1", 0)
+				nodeAddrSetHashName, _ := addressset.MakeAddressSetHashNames(nodeAddrSetName)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == $" + nodeAddrSetHashName + ") && ip4.src == $a10481622940199974102 && ((tcp && ( tcp.dst == 10250 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == $" + nodeAddrSetHashName + ") && ip4.src == $a10481622940199974102 && ((tcp && ( tcp.dst == 10250 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+				})
+				namespace1 := *newNamespace("namespace1")
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Allow",
+						Ports: []egressfirewallapi.EgressFirewallPort{
+							{
+								Protocol: "TCP",
+								Port:     10250,
+							},
+						},
+						To: egressfirewallapi.EgressFirewallDestination{
+							NodeSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"pool": "infra"},
+							},
+						},
+					},
+				})
+				node1 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   node1Name,
+						Labels: map[string]string{"pool": "infra"},
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": "{\"ipv4\":\"192.168.126.10/24\"}",
+						},
+					},
+					Status: v1.NodeStatus{
+						Addresses: []v1.NodeAddress{
+							{
+								Type:    v1.NodeInternalIP,
+								Address: "10.0.0.1",
+							},
+						},
+					},
+				}
+				node2 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node2Name,
+					},
+					Status: v1.NodeStatus{
+						Addresses: []v1.NodeAddress{
+							{
+								Type:    v1.NodeInternalIP,
+								Address: "10.0.0.2",
+							},
+						},
+					},
+				}
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							node1,
+							node2,
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				fakeOVN.asf.EventuallyExpectAddressSetWithIPs(nodeAddrSetName, []string{"10.0.0.1", "192.168.126.10"})
+
+				// labelling node2 adds it to the address set
+				node2.Labels = map[string]string{"pool": "infra"}
+				_, err := fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node2, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				fakeOVN.asf.EventuallyExpectAddressSetWithIPs(nodeAddrSetName, []string{"10.0.0.1", "192.168.126.10", "10.0.0.2"})
+
+				// deleting node1 removes it from the address set
+				err = fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Delete(context.TODO(), node1Name, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				fakeOVN.asf.EventuallyExpectAddressSetWithIPs(nodeAddrSetName, []string{"10.0.0.2"})
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("cleans up the address set of a nodeSelector rule that cannot be added", func() {
+			app.Action = func(ctx *cli.Context) error {
+				nodeAddrSetName := getEgressFirewallNodeAddrSetName("namespace1", 0)
+				nodeAddrSetHashName, _ := addressset.MakeAddressSetHashNames(nodeAddrSetName)
+				match := "match=\"(ip4.dst == $" + nodeAddrSetHashName + ") && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\""
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + match + " action=allow external-ids:egressFirewall=namespace1",
+				})
+				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd: "ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport " + match + " action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					Err: fmt.Errorf("transaction failed"),
+				})
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:egressFirewall=namespace1",
+				})
+				namespace1 := *newNamespace("namespace1")
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Allow",
+						To: egressfirewallapi.EgressFirewallDestination{
+							NodeSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"pool": "infra"},
+							},
+						},
+					},
+				})
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				fakeOVN.asf.EventuallyExpectNoAddressSet(nodeAddrSetName)
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("logs the egressfirewall ACLs according to the namespace ACL logging annotation", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const (
+					node1Name string = "node1"
+				)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop log=true severity=alert meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:egressFirewall=namespace1 action=allow",
+				})
+				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:egressFirewall=namespace1 action=drop",
+					Output: fakeUUID,
+				})
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 set acl " + fakeUUID + " log=true severity=warning",
+				})
+				namespace1 := *newNamespace("namespace1")
+				namespace1.Annotations = map[string]string{aclLoggingAnnotation: `{"deny": "alert"}`}
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Deny",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				})
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							{
+								Status: v1.NodeStatus{
+									Phase: v1.NodeRunning,
+								},
+								ObjectMeta: newObjectMeta(node1Name, ""),
+							},
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+				gomega.Eventually(func() bool {
+					nsInfo := fakeOVN.controller.getNamespaceLocked(namespace1.Name)
+					if nsInfo == nil {
+						return false
+					}
+					defer nsInfo.Unlock()
+					return nsInfo.egressFirewallPolicy != nil
+				}).Should(gomega.BeTrue())
+
+				namespace1.Annotations[aclLoggingAnnotation] = `{"deny": "warning"}`
+				_, err := fakeOVN.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), &namespace1, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("puts the egressfirewall deny ACLs in audit mode according to the namespace annotation", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const (
+					node1Name string = "node1"
+				)
+				match := "match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\""
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + match + " action=allow-related external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport " + match + " action=allow-related log=true severity=info meter=acl-logging name=audit_namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+				})
+				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + match + " action=allow-related external-ids:egressFirewall=namespace1",
+					Output: fakeUUID,
+				})
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 set ACL " + fakeUUID + " action=drop log=false severity=info name=namespace1_0",
+				})
+				namespace1 := *newNamespace("namespace1")
+				namespace1.Annotations = map[string]string{aclAuditAnnotation: "true"}
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Deny",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				})
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							{
+								Status: v1.NodeStatus{
+									Phase: v1.NodeRunning,
+								},
+								ObjectMeta: newObjectMeta(node1Name, ""),
+							},
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+				gomega.Eventually(func() bool {
+					nsInfo := fakeOVN.controller.getNamespaceLocked(namespace1.Name)
+					if nsInfo == nil {
+						return false
+					}
+					defer nsInfo.Unlock()
+					return nsInfo.egressFirewallPolicy != nil && nsInfo.egressFirewallPolicy.egressRules[0].applied
+				}).Should(gomega.BeTrue())
+
+				delete(namespace1.Annotations, aclAuditAnnotation)
+				_, err := fakeOVN.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), &namespace1, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("puts the egressfirewall deny ACLs in audit mode according to its own annotation", func() {
+			app.Action = func(ctx *cli.Context) error {
+				match := "match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\""
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + match + " action=drop external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport " + match + " action=drop log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+				})
+				namespace1 := *newNamespace("namespace1")
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Deny",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				})
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + match + " action=drop external-ids:egressFirewall=namespace1",
+					Output: fakeUUID,
+				})
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 set ACL " + fakeUUID + " action=allow-related log=true severity=info name=audit_namespace1_0",
+				})
+				egressFirewall.Annotations = map[string]string{aclAuditAnnotation: "true"}
+				_, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Update(context.TODO(), egressFirewall, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("reports the status of each rule and the conditions of an egressfirewall", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const (
+					node1Name string = "node1"
+				)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9998 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_1 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+				})
+				namespace1 := *newNamespace("namespace1")
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Allow",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4",
+						},
+					},
+					{
+						Type: "Allow",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				})
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							{
+								Status: v1.NodeStatus{
+									Phase: v1.NodeRunning,
+								},
+								ObjectMeta: newObjectMeta(node1Name, ""),
+							},
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Eventually(func() []egressfirewallapi.EgressFirewallRuleStatus {
+					ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					return ef.Status.Rules
+				}).Should(gomega.Equal([]egressfirewallapi.EgressFirewallRuleStatus{
+					{
+						Index:   0,
+						Applied: false,
+						Message: "invalid CIDR address: 1.2.3.4",
+					},
+					{
+						Index:   1,
+						Applied: true,
+					},
+				}))
+				ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(ef.Status.Status).To(gomega.Equal(egressFirewallAddError))
+				ready := meta.FindStatusCondition(ef.Status.Conditions, egressfirewallapi.EgressFirewallReady)
+				gomega.Expect(ready).NotTo(gomega.BeNil())
+				gomega.Expect(ready.Status).To(gomega.Equal(metav1.ConditionFalse))
+				gomega.Expect(ready.Reason).To(gomega.Equal(egressFirewallRulesNotApplied))
+				gomega.Expect(meta.IsStatusConditionTrue(ef.Status.Conditions, egressfirewallapi.EgressFirewallDegraded)).To(gomega.BeTrue())
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("reports wildcard dnsName rules as not applied", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const (
+					node1Name string = "node1"
+				)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9998 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_1 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+				})
+				namespace1 := *newNamespace("namespace1")
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Allow",
+						To: egressfirewallapi.EgressFirewallDestination{
+							DNSName: "*.example.com",
+						},
+					},
+					{
+						Type: "Allow",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				})
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							{
+								Status: v1.NodeStatus{
+									Phase: v1.NodeRunning,
+								},
+								ObjectMeta: newObjectMeta(node1Name, ""),
+							},
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Eventually(func() []egressfirewallapi.EgressFirewallRuleStatus {
+					ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					return ef.Status.Rules
+				}).Should(gomega.Equal([]egressfirewallapi.EgressFirewallRuleStatus{
+					{
+						Index:   0,
+						Applied: false,
+						Message: "wildcard dnsName *.example.com is not supported",
+					},
+					{
+						Index:   1,
+						Applied: true,
+					},
+				}))
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("reports why a second egressfirewall in a namespace is rejected", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const (
+					node1Name string = "node1"
+				)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+				})
+				namespace1 := *newNamespace("namespace1")
+				rules := []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: "Allow",
+						To: egressfirewallapi.EgressFirewallDestination{
+							CIDRSelector: "1.2.3.4/23",
+						},
+					},
+				}
+				egressFirewall := newEgressFirewallObject("default", namespace1.Name, rules)
+				fakeOVN.start(ctx,
+					&egressfirewallapi.EgressFirewallList{
+						Items: []egressfirewallapi.EgressFirewall{
+							*egressFirewall,
+						},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{
+							{
+								Status: v1.NodeStatus{
+									Phase: v1.NodeRunning,
+								},
+								ObjectMeta: newObjectMeta(node1Name, ""),
+							},
+						},
+					})
+
+				fakeOVN.controller.WatchNamespaces()
+				fakeOVN.controller.WatchEgressFirewall()
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Eventually(func() bool {
+					ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).Get(context.TODO(), egressFirewall.Name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					return meta.IsStatusConditionTrue(ef.Status.Conditions, egressfirewallapi.EgressFirewallReady)
+				}).Should(gomega.BeTrue())
+
+				secondEgressFirewall := newEgressFirewallObject("second", namespace1.Name, rules)
+				secondEgressFirewall.UID = "second"
+				_, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(secondEgressFirewall.Namespace).Create(context.TODO(), secondEgressFirewall, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() *metav1.Condition {
+					ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(secondEgressFirewall.Namespace).Get(context.TODO(), secondEgressFirewall.Name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					return meta.FindStatusCondition(ef.Status.Conditions, egressfirewallapi.EgressFirewallReady)
+				}).ShouldNot(gomega.BeNil())
+				ef, err := fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(secondEgressFirewall.Namespace).Get(context.TODO(), secondEgressFirewall.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				ready := meta.FindStatusCondition(ef.Status.Conditions, egressfirewallapi.EgressFirewallReady)
+				gomega.Expect(ready.Status).To(gomega.Equal(metav1.ConditionFalse))
+				gomega.Expect(ready.Reason).To(gomega.Equal(egressFirewallRejected))
+				gomega.Expect(ready.Message).To(gomega.ContainSubstring("already has EgressFirewall default"))
+				gomega.Expect(ef.Status.Rules).To(gomega.BeEmpty())
+
+				// updating and deleting the rejected egressfirewall leaves the applied one alone
+				ef.Spec.Egress = append(ef.Spec.Egress, egressfirewallapi.EgressFirewallRule{
+					Type: "Deny",
+					To: egressfirewallapi.EgressFirewallDestination{
+						CIDRSelector: "0.0.0.0/0",
+					},
+				})
+				_, err = fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(ef.Namespace).Update(context.TODO(), ef, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = fakeOVN.fakeClient.EgressFirewallClient.K8sV1().EgressFirewalls(ef.Namespace).Delete(context.TODO(), ef.Name, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Consistently(func() string {
+					nsInfo := fakeOVN.controller.getNamespaceLocked(namespace1.Name)
+					gomega.Expect(nsInfo).NotTo(gomega.BeNil())
+					defer nsInfo.Unlock()
+					if nsInfo.egressFirewallPolicy == nil {
+						return ""
+					}
+					return nsInfo.egressFirewallPolicy.name
+				}).Should(gomega.Equal(egressFirewall.Name))
+				gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 		ginkgo.It("correctly deletes an egressfirewall", func() {
 			app.Action = func(ctx *cli.Context) error {
 				const (
@@ -595,7 +1225,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.5/23) && " +
 						"ip4.src == $a10481622940199974102 && ((tcp && ( tcp.dst == 100 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
 					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.5/23) && " +
-						"ip4.src == $a10481622940199974102 && ((tcp && ( tcp.dst == 100 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+						"ip4.src == $a10481622940199974102 && ((tcp && ( tcp.dst == 100 ))) && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
 				})
 				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
 					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:egressFirewall=namespace1",
@@ -663,9 +1293,9 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 				)
 				fExec.AddFakeCmdsNoOutputNoError([]string{
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1",
-					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=allow log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 0.0.0.0/0 || ip6.dst == ::/0) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1-blockAll",
-					"ovn-nbctl --timeout=15 --id=@acl create acl priority=10000 direction=from-lport match=\"(ip4.dst == 0.0.0.0/0 || ip6.dst == ::/0) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1-blockAll -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=10000 direction=from-lport match=\"(ip4.dst == 0.0.0.0/0 || ip6.dst == ::/0) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop log=false severity=info meter=acl-logging name=namespace1_blockAll external-ids:egressFirewall=namespace1-blockAll -- add logical_switch join acls @acl",
 				})
 				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
 					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:egressFirewall=namespace1",
@@ -674,7 +1304,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations for shared gateway mode",
 				fExec.AddFakeCmdsNoOutputNoError([]string{
 					"ovn-nbctl --timeout=15 remove logical_switch join acls " + fmt.Sprintf("%s", fakeUUID),
 					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1",
-					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
+					"ovn-nbctl --timeout=15 --id=@acl create acl priority=9999 direction=from-lport match=\"(ip4.dst == 1.2.3.4/23) && ip4.src == $a10481622940199974102 && inport == \\\"" + t.JoinSwitchToGWRouterPrefix + t.OVNClusterRouter + "\\\"\" action=drop log=false severity=info meter=acl-logging name=namespace1_0 external-ids:egressFirewall=namespace1 -- add logical_switch join acls @acl",
 				})
 				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
 					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:egressFirewall=namespace1-blockAll",
@@ -796,6 +1426,40 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 				},
 				expectedMatch: "((udp && ( udp.dst == 400 )) || (tcp && ( tcp.dst == 100 || tcp.dst == 102 )) || (sctp && ( sctp.dst == 13 )))",
 			},
+			{
+				ports: []egressfirewallapi.EgressFirewallPort{
+					{
+						Protocol: "TCP",
+						Port:     30000,
+						EndPort:  32767,
+					},
+				},
+				expectedMatch: "((tcp && ( (tcp.dst >= 30000 && tcp.dst <= 32767) )))",
+			},
+			{
+				ports: []egressfirewallapi.EgressFirewallPort{
+					{
+						Protocol: "TCP",
+						Port:     80,
+					},
+					{
+						Protocol: "TCP",
+						Port:     8000,
+						EndPort:  8080,
+					},
+					{
+						Protocol: "UDP",
+						Port:     53,
+						EndPort:  53,
+					},
+					{
+						Protocol: "SCTP",
+						Port:     38412,
+						EndPort:  38472,
+					},
+				},
+				expectedMatch: "((udp && ( udp.dst == 53 )) || (tcp && ( tcp.dst == 80 || (tcp.dst >= 8000 && tcp.dst <= 8080) )) || (sctp && ( (sctp.dst >= 38412 && sctp.dst <= 38472) )))",
+			},
 		}
 		for _, test := range testcases {
 			l4Match := egressGetL4Match(test.ports)
@@ -915,6 +1579,56 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 					to:     destination{cidrSelector: "2002::1234:abcd:ffff:c0a8:101/64"},
 				},
 			},
+			{
+				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
+					Type:  egressfirewallapi.EgressFirewallRuleAllow,
+					Ports: []egressfirewallapi.EgressFirewallPort{{Protocol: "TCP", Port: 30000, EndPort: 32767}},
+					To:    egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.0/24"},
+				},
+				id:  3,
+				err: false,
+				output: egressFirewallRule{
+					id:     3,
+					access: egressfirewallapi.EgressFirewallRuleAllow,
+					ports:  []egressfirewallapi.EgressFirewallPort{{Protocol: "TCP", Port: 30000, EndPort: 32767}},
+					to:     destination{cidrSelector: "1.2.3.0/24"},
+				},
+			},
+			{
+				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
+					Type:  egressfirewallapi.EgressFirewallRuleAllow,
+					Ports: []egressfirewallapi.EgressFirewallPort{{Protocol: "TCP", Port: 32767, EndPort: 30000}},
+					To:    egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.0/24"},
+				},
+				id:        4,
+				err:       true,
+				errOutput: "invalid port range 32767-30000 for protocol TCP",
+				output:    egressFirewallRule{},
+			},
+			{
+				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
+					Type:  egressfirewallapi.EgressFirewallRuleAllow,
+					Ports: []egressfirewallapi.EgressFirewallPort{{Protocol: "TCP", EndPort: 32767}},
+					To:    egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.0/24"},
+				},
+				id:        4,
+				err:       true,
+				errOutput: "endPort 32767 for protocol TCP requires a port",
+				output:    egressFirewallRule{},
+			},
+			{
+				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
+					Type: egressfirewallapi.EgressFirewallRuleAllow,
+					To: egressfirewallapi.EgressFirewallDestination{
+						CIDRSelector: "1.2.3.0/24",
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "infra"}},
+					},
+				},
+				id:        5,
+				err:       true,
+				errOutput: "only one of cidrSelector, dnsName and nodeSelector can be set",
+				output:    egressFirewallRule{},
+			},
 		}
 		for _, tc := range testcases {
 			output, err := newEgressFirewallRule(tc.egressFirewallRule, tc.id)
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
 
 	utilnet "k8s.io/utils/net"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
@@ -21,6 +24,80 @@ import (
 type gatewayInfo struct {
 	gws        []net.IP
 	bfdEnabled bool
+	bfdParams  bfdParameters
+}
+
+// bfdParameters are the parameters of the BFD sessions to a gateway, zero
+// values leave the OVN defaults
+type bfdParameters struct {
+	minTx      int
+	minRx      int
+	detectMult int
+}
+
+// nbctlArgs returns the commands setting the parameters on the BFD entry, the
+// columns of the parameters which are not set are cleared so that the OVN
+// defaults apply again once a parameter is dropped
+func (p bfdParameters) nbctlArgs(uuid string) []string {
+	var set, clear []string
+	for _, column := range []struct {
+		name  string
+		value int
+	}{
+		{"min_tx", p.minTx},
+		{"min_rx", p.minRx},
+		{"detect_mult", p.detectMult},
+	} {
+		if column.value > 0 {
+			set = append(set, fmt.Sprintf("%s=%d", column.name, column.value))
+		} else {
+			clear = append(clear, column.name)
+		}
+	}
+	var args []string
+	if len(set) > 0 {
+		args = append(append(args, "set", "BFD", uuid), set...)
+	}
+	if len(clear) > 0 {
+		if len(args) > 0 {
+			args = append(args, "--")
+		}
+		args = append(append(args, "clear", "BFD", uuid), clear...)
+	}
+	return args
+}
+
+// parseBFDParameters parses the BFD parameters annotations of a namespace or a
+// gateway pod
+func parseBFDParameters(annotations map[string]string) (bfdParameters, error) {
+	var params bfdParameters
+	for annotation, value := range map[string]*int{
+		bfdMinTxAnnotation:      &params.minTx,
+		bfdMinRxAnnotation:      &params.minRx,
+		bfdDetectMultAnnotation: &params.detectMult,
+	} {
+		annotationValue, ok := annotations[annotation]
+		if !ok {
+			continue
+		}
+		v, err := strconv.Atoi(annotationValue)
+		if err != nil || v <= 0 {
+			return bfdParameters{}, fmt.Errorf("invalid %s annotation value %q, must be a positive integer",
+				annotation, annotationValue)
+		}
+		*value = v
+	}
+	return params, nil
+}
+
+// bfdAnnotationsChanged returns true if the annotations enabling or tuning BFD changed
+func bfdAnnotationsChanged(oldAnnotations, newAnnotations map[string]string) bool {
+	for _, annotation := range []string{bfdAnnotation, bfdMinTxAnnotation, bfdMinRxAnnotation, bfdDetectMultAnnotation} {
+		if oldAnnotations[annotation] != newAnnotations[annotation] {
+			return true
+		}
+	}
+	return false
 }
 
 // addPodExternalGW handles detecting if a pod is serving as an external gateway for namespace(s) and adding routes
@@ -30,22 +107,74 @@ func (oc *Controller) addPodExternalGW(pod *kapi.Pod) error {
 	if podRoutingNamespaceAnno == "" {
 		return nil
 	}
+	if !isGatewayPodReady(pod) {
+		// the gateway pod is added back once ready
+		klog.Infof("External gateway pod: %s, is not ready or terminating", pod.Name)
+		oc.deletePodExternalGW(pod)
+		return nil
+	}
 	enableBFD := false
 	if _, ok := pod.Annotations[bfdAnnotation]; ok {
 		enableBFD = true
 	}
+	bfdParams, err := parseBFDParameters(pod.Annotations)
+	if err != nil {
+		klog.Warningf("Using the default BFD parameters for external gateway pod %s: %v", pod.Name, err)
+	}
 
 	klog.Infof("External gateway pod: %s, detected for namespace(s) %s", pod.Name, podRoutingNamespaceAnno)
-	var foundGws []net.IP
+	if pod.Annotations[routingNetworkAnnotation] == "" && !pod.Spec.HostNetwork {
+		klog.Errorf("Ignoring pod %s as an external gateway candidate. Invalid combination "+
+			"of host network: %t and routing-network annotation: %s", pod.Name, pod.Spec.HostNetwork,
+			pod.Annotations[routingNetworkAnnotation])
+		return nil
+	}
+	foundGws, err := getExGWPodIPs(pod, pod.Annotations[routingNetworkAnnotation])
+	if err != nil {
+		return err
+	}
 
-	if pod.Annotations[routingNetworkAnnotation] != "" {
+	// if we found any gateways then we need to update current pods routing in the relevant namespace
+	if len(foundGws) == 0 {
+		klog.Warningf("No valid gateway IPs found for requested external gateway pod: %s", pod.Name)
+		return nil
+	}
+
+	for _, namespace := range strings.Split(podRoutingNamespaceAnno, ",") {
+		err := oc.addPodExternalGWForNamespace(namespace, pod, gatewayInfo{gws: foundGws, bfdEnabled: enableBFD, bfdParams: bfdParams})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isGatewayPodReady returns true if the gateway pod can attract traffic: it is
+// ready and not terminating
+func isGatewayPodReady(pod *kapi.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == kapi.PodReady {
+			return condition.Status == kapi.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getExGWPodIPs returns the gateway IPs served by a pod: its IPs on the network
+// attachment named networkName if set, its host network IPs otherwise
+func getExGWPodIPs(pod *kapi.Pod, networkName string) ([]net.IP, error) {
+	var foundGws []net.IP
+	if networkName != "" {
 		var multusNetworks []nettypes.NetworkStatus
 		err := json.Unmarshal([]byte(pod.ObjectMeta.Annotations[nettypes.NetworkStatusAnnot]), &multusNetworks)
 		if err != nil {
-			return fmt.Errorf("unable to unmarshall annotation k8s.v1.cni.cncf.io/network-status on pod %s: %v", pod.Name, err)
+			return nil, fmt.Errorf("unable to unmarshall annotation k8s.v1.cni.cncf.io/network-status on pod %s: %v", pod.Name, err)
 		}
 		for _, multusNetwork := range multusNetworks {
-			if multusNetwork.Name == pod.Annotations[routingNetworkAnnotation] {
+			if multusNetwork.Name == networkName {
 				for _, gwIP := range multusNetwork.IPs {
 					ip := net.ParseIP(gwIP)
 					if ip != nil {
@@ -62,25 +191,10 @@ func (oc *Controller) addPodExternalGW(pod *kapi.Pod) error {
 			}
 		}
 	} else {
-		klog.Errorf("Ignoring pod %s as an external gateway candidate. Invalid combination "+
-			"of host network: %t and routing-network annotation: %s", pod.Name, pod.Spec.HostNetwork,
-			pod.Annotations[routingNetworkAnnotation])
-		return nil
-	}
-
-	// if we found any gateways then we need to update current pods routing in the relevant namespace
-	if len(foundGws) == 0 {
-		klog.Warningf("No valid gateway IPs found for requested external gateway pod: %s", pod.Name)
-		return nil
+		return nil, fmt.Errorf("pod %s/%s is neither host networked nor given a network attachment",
+			pod.Namespace, pod.Name)
 	}
-
-	for _, namespace := range strings.Split(podRoutingNamespaceAnno, ",") {
-		err := oc.addPodExternalGWForNamespace(namespace, pod, gatewayInfo{gws: foundGws, bfdEnabled: enableBFD})
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return foundGws, nil
 }
 
 // addPodExternalGWForNamespace handles adding routes to all pods in that namespace for a pod GW
@@ -133,6 +247,11 @@ func (oc *Controller) addGWRoutesForNamespace(namespace string, egress gatewayIn
 				if err != nil {
 					return fmt.Errorf("unable to add src-ip route to GR router, stderr:%q, err:%v", stderr, err)
 				}
+				if egress.bfdEnabled {
+					if err := setBFDParameters(gw.String(), gr, egress.bfdParams); err != nil {
+						return err
+					}
+				}
 				if err := oc.addHybridRoutePolicyForPod(net.ParseIP(podIP.IP), pod.Spec.NodeName); err != nil {
 					return err
 				}
@@ -306,6 +425,11 @@ func (oc *Controller) addGWRoutesForPod(gateways []gatewayInfo, podIfAddrs []*ne
 					if err != nil {
 						return fmt.Errorf("unable to add external gwStr src-ip route to GR router, stderr:%q, err:%gw", stderr, err)
 					}
+					if gateway.bfdEnabled {
+						if err := setBFDParameters(gwStr, gr, gateway.bfdParams); err != nil {
+							return err
+						}
+					}
 					if err := oc.addHybridRoutePolicyForPod(podIPNet.IP, node); err != nil {
 						return err
 					}
@@ -507,3 +631,124 @@ func cleanUpBFDEntry(gatewayIP, gatewayRouter string) {
 		}
 	}
 }
+
+// setBFDParameters sets the BFD parameters of the session between the gateway
+// router and the gateway, resetting the ones which are not configured
+func setBFDParameters(gatewayIP, gatewayRouter string, params bfdParameters) error {
+	portName := types.GWRouterToExtSwitchPrefix + gatewayRouter
+	uuids, stderr, err := util.RunOVNNbctl(
+		"--format=csv", "--data=bare", "--no-heading", "--columns=_uuid", "find", "BFD", "logical_port="+portName, "dst_ip="+gatewayIP)
+	if err != nil {
+		return fmt.Errorf("failed to find the BFD entry for %s %s, stderr: %q, error: %v", portName, gatewayIP, stderr, err)
+	}
+	if strings.TrimSpace(uuids) == "" {
+		if params == (bfdParameters{}) {
+			// there are no parameters to reset
+			return nil
+		}
+		return fmt.Errorf("did not find the BFD entry for %s %s", portName, gatewayIP)
+	}
+	for _, uuid := range strings.Split(strings.TrimSpace(uuids), "\n") {
+		_, stderr, err = util.RunOVNNbctl(params.nbctlArgs(uuid)...)
+		if err != nil {
+			return fmt.Errorf("failed to set the parameters of BFD %s, stderr: %q, error: %v", uuid, stderr, err)
+		}
+	}
+	return nil
+}
+
+// bfdStatusUp is the status of a BFD session which is up, the gateway is used
+// by the ECMP routes to it as long as its session is up
+const bfdStatusUp = "up"
+
+// bfdSession is a BFD session from a gateway router to an external gateway
+type bfdSession struct {
+	gatewayRouter string
+	gatewayIP     string
+}
+
+// syncExternalGWBFDStatus reads the status of the BFD sessions to the external
+// gateways from the NB database, records it in metrics, and posts events on the
+// namespaces routed through the gateways whose session went down or came back up
+func (oc *Controller) syncExternalGWBFDStatus() {
+	output, stderr, err := util.RunOVNNbctl("--format=csv", "--data=bare", "--no-heading",
+		"--columns=logical_port,dst_ip,status", "find", "BFD")
+	if err != nil {
+		klog.Errorf("Failed to list the BFD sessions, stderr: %q, error: %v", stderr, err)
+		return
+	}
+	sessions := make(map[bfdSession]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], types.GWRouterToExtSwitchPrefix) {
+			continue
+		}
+		gatewayIP := net.ParseIP(fields[1])
+		if gatewayIP == nil {
+			continue
+		}
+		session := bfdSession{
+			gatewayRouter: strings.TrimPrefix(fields[0], types.GWRouterToExtSwitchPrefix),
+			gatewayIP:     gatewayIP.String(),
+		}
+		status := fields[2]
+		sessions[session] = status
+		metrics.RecordExternalGatewayBFDStatus(session.gatewayRouter, session.gatewayIP, status == bfdStatusUp)
+
+		// sessions start down, only report the transitions of the known ones
+		oldStatus, ok := oc.bfdSessionStatus[session]
+		if !ok || (oldStatus == bfdStatusUp) == (status == bfdStatusUp) {
+			continue
+		}
+		for _, namespace := range oc.getNamespacesRoutedThroughGW(session) {
+			nsRef := kapi.ObjectReference{
+				Kind:      "Namespace",
+				Name:      namespace,
+				Namespace: namespace,
+			}
+			if status == bfdStatusUp {
+				oc.recorder.Eventf(&nsRef, kapi.EventTypeNormal, "ExternalGatewayUp",
+					"BFD session from %s to external gateway %s is up, the gateway is used again by the pods of namespace %s",
+					session.gatewayRouter, session.gatewayIP, namespace)
+			} else {
+				oc.recorder.Eventf(&nsRef, kapi.EventTypeWarning, "ExternalGatewayDown",
+					"BFD session from %s to external gateway %s is %s, the gateway is not used by the pods of namespace %s",
+					session.gatewayRouter, session.gatewayIP, status, namespace)
+			}
+		}
+	}
+	for session := range oc.bfdSessionStatus {
+		if _, ok := sessions[session]; !ok {
+			metrics.DeleteExternalGatewayBFDStatus(session.gatewayRouter, session.gatewayIP)
+		}
+	}
+	oc.bfdSessionStatus = sessions
+}
+
+// getNamespacesRoutedThroughGW returns the namespaces with pods routed from the
+// gateway router through the gateway of the BFD session
+func (oc *Controller) getNamespacesRoutedThroughGW(session bfdSession) []string {
+	oc.namespacesMutex.Lock()
+	names := make([]string, 0, len(oc.namespaces))
+	for name := range oc.namespaces {
+		names = append(names, name)
+	}
+	oc.namespacesMutex.Unlock()
+
+	var namespaces []string
+	for _, name := range names {
+		nsInfo := oc.getNamespaceLocked(name)
+		if nsInfo == nil {
+			continue
+		}
+		for _, gwToGr := range nsInfo.podExternalRoutes {
+			if gwToGr[session.gatewayIP] == session.gatewayRouter {
+				namespaces = append(namespaces, name)
+				break
+			}
+		}
+		nsInfo.Unlock()
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
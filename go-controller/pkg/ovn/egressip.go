@@ -1,10 +1,16 @@
 package ovn
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"math"
 	"net"
+	"net/http"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -13,12 +19,15 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 	kapi "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
@@ -32,19 +41,54 @@ type egressIPDialer interface {
 
 var dialer egressIPDialer = &egressIPDial{}
 
+// reasons of the EgressIP status conditions and of the unassigned egress IPs
+const (
+	egressIPAllAssigned         = "AllAssigned"
+	egressIPPartiallyAssigned   = "PartiallyAssigned"
+	egressIPNotAssigned         = "NotAssigned"
+	egressIPNoAssignableNodes   = "NoAssignableNodes"
+	egressIPNoMatchingNode      = "NoMatchingNode"
+	egressIPNodeAlreadyAssigned = "NodeAlreadyAssigned"
+	egressIPCapacityExhausted   = "CapacityExhausted"
+	egressIPAlreadyAllocated    = "AlreadyAllocated"
+	egressIPInvalid             = "InvalidEgressIP"
+	egressIPIsNodeIP            = "NodeIP"
+	egressIPRejected            = "Rejected"
+)
+
+const (
+	// range of the packet marks of the EgressIPs
+	egressIPMarkMin = 50000
+	egressIPMarkMax = 55000
+)
+
+// egressIPUnlimitedCapacity is the capacity of the egress nodes which do not publish any
+const egressIPUnlimitedCapacity = math.MaxInt32
+
 const (
 	// In case we restart we need accept executing ovn-nbctl commands with this error.
 	// The ovn-nbctl API does not support `--may-exist` for `lr-policy-add`
 	policyAlreadyExistsMsg = "Same routing policy already existed"
 )
 
+// egressIPExcludedDestinationPolicyName is the name of the no-reroute logical router policies of the
+// egress IP excluded destinations
+const egressIPExcludedDestinationPolicyName = "egressip-excluded-destination"
+
 func (oc *Controller) addEgressIP(eIP *egressipv1.EgressIP) error {
 	// If the status is set at this point, then we know it's valid from syncEgressIP and we have no assignment to do.
 	// Just initialize all watchers (which should not re-create any already existing items in the OVN DB)
 	if len(eIP.Status.Items) == 0 {
-		if err := oc.assignEgressIPs(eIP); err != nil {
+		err := oc.assignEgressIPs(eIP)
+		oc.recordEgressIPAssignments(eIP.Name, &eIP.Status, len(eIP.Spec.EgressIPs))
+		if err != nil {
 			return fmt.Errorf("unable to assign egress IP: %s, error: %v", eIP.Name, err)
 		}
+	} else {
+		oc.recordEgressIPAssignments(eIP.Name, &eIP.Status, len(eIP.Spec.EgressIPs))
+	}
+	if err := oc.setEgressIPMark(eIP); err != nil {
+		return err
 	}
 
 	oc.eIPC.namespaceHandlerMutex.Lock()
@@ -78,7 +122,24 @@ func (oc *Controller) addEgressIP(eIP *egressipv1.EgressIP) error {
 
 func (oc *Controller) deleteEgressIP(eIP *egressipv1.EgressIP) error {
 	oc.releaseEgressIPs(eIP)
+	oc.recordEgressIPAssignments(eIP.Name, nil, 0)
+	oc.deleteEgressIPHandlers(eIP)
+
+	namespaces, err := oc.kube.GetNamespaces(eIP.Spec.NamespaceSelector)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces.Items {
+		if err := oc.deleteNamespacePodsEgressIP(eIP, &namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// deleteEgressIPHandlers stops watching the namespaces and pods matched by the EgressIP,
+// without removing the OVN setup of these pods
+func (oc *Controller) deleteEgressIPHandlers(eIP *egressipv1.EgressIP) {
 	oc.eIPC.namespaceHandlerMutex.Lock()
 	defer oc.eIPC.namespaceHandlerMutex.Unlock()
 	if nH, exists := oc.eIPC.namespaceHandlerCache[getEgressIPKey(eIP)]; exists {
@@ -92,17 +153,6 @@ func (oc *Controller) deleteEgressIP(eIP *egressipv1.EgressIP) error {
 		oc.watchFactory.RemovePodHandler(&pH)
 		delete(oc.eIPC.podHandlerCache, getEgressIPKey(eIP))
 	}
-
-	namespaces, err := oc.kube.GetNamespaces(eIP.Spec.NamespaceSelector)
-	if err != nil {
-		return err
-	}
-	for _, namespace := range namespaces.Items {
-		if err := oc.deleteNamespacePodsEgressIP(eIP, &namespace); err != nil {
-			return err
-		}
-	}
-	return nil
 }
 
 func (oc *Controller) isEgressNodeReady(egressNode *kapi.Node) bool {
@@ -132,6 +182,9 @@ func (oc *Controller) syncEgressIPs(eIPs []interface{}) {
 			klog.Errorf("Spurious object in syncEgressIPs: %v", eIP)
 			continue
 		}
+		if mark, err := strconv.Atoi(eIP.Annotations[types.EgressIPMarkAnnotation]); err == nil && !oc.eIPC.isEgressIPMarkUsed(mark) {
+			oc.eIPC.marks[eIP.Name] = mark
+		}
 		var validAssignment bool
 		for _, eIPStatus := range eIP.Status.Items {
 			validAssignment = false
@@ -153,7 +206,12 @@ func (oc *Controller) syncEgressIPs(eIPs []interface{}) {
 				klog.Errorf("Allocator error: EgressIP allocation: %s is the IP of node: %s ", ip.String(), node.name)
 				break
 			}
-			if utilnet.IsIPv6(ip) && eNode.v6Subnet != nil {
+			if eIPStatus.Interface != "" {
+				if iface, ok := eNode.getEgressInterface(ip); !ok || iface != eIPStatus.Interface {
+					klog.Errorf("Allocator error: EgressIP allocation: %s on interface: %s of node: %s which cannot host it", ip.String(), eIPStatus.Interface, eIPStatus.Node)
+					break
+				}
+			} else if utilnet.IsIPv6(ip) && eNode.v6Subnet != nil {
 				if !eNode.v6Subnet.Contains(ip) {
 					klog.Errorf("Allocator error: EgressIP allocation: %s on subnet: %s which cannot host it", ip.String(), eNode.v6Subnet.String())
 					break
@@ -202,6 +260,11 @@ func (oc *Controller) isAnyClusterNodeIP(ip net.IP) *egressNode {
 		if ip.Equal(eNode.v6IP) || ip.Equal(eNode.v4IP) {
 			return eNode
 		}
+		for _, egressInterface := range eNode.egressInterfaces {
+			if ip.Equal(egressInterface.v6IP) || ip.Equal(egressInterface.v4IP) {
+				return eNode
+			}
+		}
 	}
 	return nil
 }
@@ -292,16 +355,26 @@ func (oc *Controller) deleteNamespacePodsEgressIP(eIP *egressipv1.EgressIP, name
 func (oc *Controller) assignEgressIPs(eIP *egressipv1.EgressIP) error {
 	oc.eIPC.allocatorMutex.Lock()
 	assignments := []egressipv1.EgressIPStatusItem{}
+	// reasons holds why each egress IP which could not be assigned was not
+	unassigned := map[string]egressipv1.EgressIPStatusUnassignedItem{}
 	defer func() {
 		eIP.Status.Items = assignments
+		setEgressIPStatusConditions(eIP, unassigned)
 		oc.eIPC.allocatorMutex.Unlock()
 	}()
+	eIPRef := kapi.ObjectReference{
+		Kind: "EgressIP",
+		Name: eIP.Name,
+	}
 	assignableNodes, existingAllocations := oc.getSortedEgressData()
 	if len(assignableNodes) == 0 {
 		oc.eIPC.assignmentRetry[eIP.Name] = true
-		eIPRef := kapi.ObjectReference{
-			Kind: "EgressIP",
-			Name: eIP.Name,
+		for _, egressIP := range eIP.Spec.EgressIPs {
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPNoAssignableNodes,
+				Message:  fmt.Sprintf("no node is labeled with %s, or none of them is ready and reachable", util.GetNodeEgressLabel()),
+			}
 		}
 		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "NoMatchingNodeFound", "no assignable nodes for EgressIP: %s, please tag at least one node with label: %s", eIP.Name, util.GetNodeEgressLabel())
 		return fmt.Errorf("no assignable nodes")
@@ -311,17 +384,19 @@ func (oc *Controller) assignEgressIPs(eIP *egressipv1.EgressIP) error {
 		klog.V(5).Infof("Will attempt assignment for egress IP: %s", egressIP)
 		eIPC := net.ParseIP(egressIP)
 		if eIPC == nil {
-			eIPRef := kapi.ObjectReference{
-				Kind: "EgressIP",
-				Name: eIP.Name,
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPInvalid,
+				Message:  "not a valid IP address",
 			}
 			oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "InvalidEgressIP", "egress IP: %s for object EgressIP: %s is not a valid IP address", egressIP, eIP.Name)
 			return fmt.Errorf("unable to parse provided EgressIP: %s, invalid", egressIP)
 		}
 		if node := oc.isAnyClusterNodeIP(eIPC); node != nil {
-			eIPRef := kapi.ObjectReference{
-				Kind: "EgressIP",
-				Name: eIP.Name,
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPIsNodeIP,
+				Message:  fmt.Sprintf("the IP address of node %s cannot be an egress IP", node.name),
 			}
 			oc.recorder.Eventf(
 				&eIPRef,
@@ -331,48 +406,150 @@ func (oc *Controller) assignEgressIPs(eIP *egressipv1.EgressIP) error {
 			)
 			return fmt.Errorf("egress IP: %v is the IP address of node: %s", eIPC, node.name)
 		}
+		if _, exists := existingAllocations[eIPC.String()]; exists {
+			klog.V(5).Infof("EgressIP: %v is already allocated, skipping", eIPC)
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPAlreadyAllocated,
+				Message:  "the egress IP is already assigned to a node for another EgressIP",
+			}
+			continue
+		}
+		assigned, nodeInUse, nodeFull := false, false, false
 		for i := 0; i < len(assignableNodes); i++ {
 			klog.V(5).Infof("Attempting assignment on egress node: %+v", assignableNodes[i])
-			if _, exists := existingAllocations[eIPC.String()]; exists {
-				klog.V(5).Infof("EgressIP: %v is already allocated, skipping", eIPC)
-				break
-			}
-			if assignableNodes[i].tainted {
-				klog.V(5).Infof("Node: %s is already in use by another egress IP for this EgressIP: %s, trying another node", assignableNodes[i].name, eIP.Name)
-				continue
-			}
-			if (assignableNodes[i].v6Subnet != nil && assignableNodes[i].v6Subnet.Contains(eIPC)) ||
-				(assignableNodes[i].v4Subnet != nil && assignableNodes[i].v4Subnet.Contains(eIPC)) {
+			if egressInterface, ok := assignableNodes[i].getEgressInterface(eIPC); ok {
+				if assignableNodes[i].tainted {
+					klog.V(5).Infof("Node: %s is already in use by another egress IP for this EgressIP: %s, trying another node", assignableNodes[i].name, eIP.Name)
+					nodeInUse = true
+					continue
+				}
+				if !assignableNodes[i].hasCapacity() {
+					klog.V(5).Infof("Node: %s hosts its capacity of %d egress IPs, trying another node", assignableNodes[i].name, assignableNodes[i].capacity)
+					nodeFull = true
+					continue
+				}
 				assignableNodes[i].tainted, oc.eIPC.allocator[assignableNodes[i].name].allocations[eIPC.String()] = true, true
 				assignments = append(assignments, egressipv1.EgressIPStatusItem{
-					EgressIP: eIPC.String(),
-					Node:     assignableNodes[i].name,
+					EgressIP:  eIPC.String(),
+					Node:      assignableNodes[i].name,
+					Interface: egressInterface,
 				})
 				klog.V(5).Infof("Successful assignment of egress IP: %s on node: %+v", egressIP, assignableNodes[i])
+				assigned = true
 				break
 			}
 		}
+		if assigned {
+			continue
+		}
+		if nodeFull {
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPCapacityExhausted,
+				Message:  "every node which can host the egress IP hosts as many egress IPs as its capacity allows",
+			}
+		} else if nodeInUse {
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPNodeAlreadyAssigned,
+				Message:  "every node which can host the egress IP already hosts another egress IP of this EgressIP",
+			}
+		} else {
+			unassigned[egressIP] = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPNoMatchingNode,
+				Message:  "no assignable node has a subnet containing the egress IP",
+			}
+		}
 	}
 	if len(assignments) == 0 {
 		oc.eIPC.assignmentRetry[eIP.Name] = true
-		eIPRef := kapi.ObjectReference{
-			Kind: "EgressIP",
-			Name: eIP.Name,
-		}
-		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "NoMatchingNodeFound", "No matching nodes found, which can host any of the egress IPs: %v for object EgressIP: %s", eIP.Spec.EgressIPs, eIP.Name)
+		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "NoMatchingNodeFound", "No matching nodes found, which can host any of the egress IPs: %v for object EgressIP: %s, unassigned: %s", eIP.Spec.EgressIPs, eIP.Name, getUnassignedEgressIPReasons(eIP, unassigned))
 		return fmt.Errorf("no matching host found")
 	}
 	if len(assignments) < len(eIP.Spec.EgressIPs) {
 		oc.eIPC.assignmentRetry[eIP.Name] = true
-		eIPRef := kapi.ObjectReference{
-			Kind: "EgressIP",
-			Name: eIP.Name,
-		}
-		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "UnassignedRequest", "Not all egress IPs for EgressIP: %s could be assigned, please tag more nodes", eIP.Name)
+		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "UnassignedRequest", "Not all egress IPs for EgressIP: %s could be assigned, please tag more nodes, unassigned: %s", eIP.Name, getUnassignedEgressIPReasons(eIP, unassigned))
 	}
 	return nil
 }
 
+// getUnassignedEgressIPReasons lists the unassigned egress IPs of the EgressIP with the reason why
+// they are not assigned, for events
+func getUnassignedEgressIPReasons(eIP *egressipv1.EgressIP, unassigned map[string]egressipv1.EgressIPStatusUnassignedItem) string {
+	reasons := []string{}
+	for _, egressIP := range eIP.Spec.EgressIPs {
+		if item, ok := unassigned[egressIP]; ok {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", egressIP, item.Reason))
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// setEgressIPStatusConditions sets the unassigned egress IPs and the conditions in the status of
+// the EgressIP from its assignments and the reasons why the other egress IPs are not assigned
+func setEgressIPStatusConditions(eIP *egressipv1.EgressIP, unassigned map[string]egressipv1.EgressIPStatusUnassignedItem) {
+	assigned := sets.NewString()
+	for _, item := range eIP.Status.Items {
+		assigned.Insert(item.EgressIP)
+	}
+	eIP.Status.Unassigned = nil
+	for _, egressIP := range eIP.Spec.EgressIPs {
+		if ip := net.ParseIP(egressIP); ip != nil && assigned.Has(ip.String()) {
+			continue
+		}
+		item, ok := unassigned[egressIP]
+		if !ok {
+			// the assignment stopped before reaching this egress IP
+			item = egressipv1.EgressIPStatusUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   egressIPRejected,
+				Message:  "the EgressIP is rejected because another of its egress IPs is not supported",
+			}
+		}
+		eIP.Status.Unassigned = append(eIP.Status.Unassigned, item)
+	}
+
+	condition := metav1.Condition{
+		Type:   egressipv1.EgressIPAssigned,
+		Status: metav1.ConditionTrue,
+		Reason: egressIPAllAssigned,
+		Message: fmt.Sprintf("%d of %d egress IPs assigned",
+			len(eIP.Status.Items), len(eIP.Spec.EgressIPs)),
+	}
+	if len(eIP.Status.Unassigned) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = egressIPPartiallyAssigned
+		if len(eIP.Status.Items) == 0 {
+			condition.Reason = egressIPNotAssigned
+		}
+	}
+	meta.SetStatusCondition(&eIP.Status.Conditions, condition)
+}
+
+// recordEgressIPAssignments updates the assignment counts of the EgressIP, and the
+// metrics counting the assigned and unassigned egress IPs. A nil EgressIP status
+// removes the EgressIP from the counts.
+func (oc *Controller) recordEgressIPAssignments(name string, status *egressipv1.EgressIPStatus, numEgressIPs int) {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	if status == nil {
+		delete(oc.eIPC.assignmentCounts, name)
+	} else {
+		oc.eIPC.assignmentCounts[name] = egressIPAssignmentCount{
+			assigned:   len(status.Items),
+			unassigned: numEgressIPs - len(status.Items),
+		}
+	}
+	var assigned, unassigned int
+	for _, count := range oc.eIPC.assignmentCounts {
+		assigned += count.assigned
+		unassigned += count.unassigned
+	}
+	metrics.RecordEgressIPCount(float64(assigned), float64(unassigned))
+}
+
 func (oc *Controller) releaseEgressIPs(eIP *egressipv1.EgressIP) {
 	oc.eIPC.allocatorMutex.Lock()
 	defer oc.eIPC.allocatorMutex.Unlock()
@@ -383,6 +560,59 @@ func (oc *Controller) releaseEgressIPs(eIP *egressipv1.EgressIP) {
 		}
 		klog.V(5).Infof("Remaining allocations on node are: %+v", oc.eIPC.allocator[status.Node])
 	}
+	delete(oc.eIPC.marks, eIP.Name)
+}
+
+// setEgressIPMark annotates the EgressIP with its packet mark if any of its egress IPs is
+// assigned to a secondary host interface
+func (oc *Controller) setEgressIPMark(eIP *egressipv1.EgressIP) error {
+	for _, status := range eIP.Status.Items {
+		if status.Interface != "" {
+			return oc.allocateEgressIPMark(eIP)
+		}
+	}
+	return nil
+}
+
+// allocateEgressIPMark annotates the EgressIP with its packet mark, keeping the mark it is
+// already annotated with if no other EgressIP uses it
+func (oc *Controller) allocateEgressIPMark(eIP *egressipv1.EgressIP) error {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	mark, exists := oc.eIPC.marks[eIP.Name]
+	if !exists {
+		var err error
+		mark, err = strconv.Atoi(eIP.Annotations[types.EgressIPMarkAnnotation])
+		if err != nil || mark < egressIPMarkMin || mark > egressIPMarkMax || oc.eIPC.isEgressIPMarkUsed(mark) {
+			for mark = egressIPMarkMin; mark <= egressIPMarkMax && oc.eIPC.isEgressIPMarkUsed(mark); mark++ {
+			}
+			if mark > egressIPMarkMax {
+				return fmt.Errorf("no packet mark left for EgressIP: %s", eIP.Name)
+			}
+		}
+		oc.eIPC.marks[eIP.Name] = mark
+	}
+	if eIP.Annotations == nil {
+		eIP.Annotations = map[string]string{}
+	}
+	eIP.Annotations[types.EgressIPMarkAnnotation] = strconv.Itoa(mark)
+	return nil
+}
+
+func (e *egressIPController) isEgressIPMarkUsed(mark int) bool {
+	for _, m := range e.marks {
+		if m == mark {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *egressIPController) getEgressIPMark(egressIPName string) (int, bool) {
+	e.allocatorMutex.Lock()
+	defer e.allocatorMutex.Unlock()
+	mark, exists := e.marks[egressIPName]
+	return mark, exists
 }
 
 func (oc *Controller) getSortedEgressData() ([]egressNode, map[string]bool) {
@@ -397,7 +627,10 @@ func (oc *Controller) getSortedEgressData() ([]egressNode, map[string]bool) {
 		}
 	}
 	sort.Slice(assignableNodes, func(i, j int) bool {
-		return len(assignableNodes[i].allocations) < len(assignableNodes[j].allocations)
+		if len(assignableNodes[i].allocations) != len(assignableNodes[j].allocations) {
+			return len(assignableNodes[i].allocations) < len(assignableNodes[j].allocations)
+		}
+		return assignableNodes[i].name < assignableNodes[j].name
 	})
 	return assignableNodes, allAllocations
 }
@@ -437,10 +670,17 @@ func (oc *Controller) addEgressNode(egressNode *kapi.Node) error {
 		klog.Errorf("Unable to configure GARP on external logical switch port for egress node: %s, "+
 			"this will result in packet drops during egress IP re-assignment, stdout: %s, stderr: %s, err: %v", egressNode.Name, stdout, stderr, err)
 	}
+	oc.retryEgressIPAssignments(egressNode.Name)
+	return nil
+}
+
+// retryEgressIPAssignments re-assigns the EgressIPs which have unassigned egress IPs, once the
+// node can host more egress IPs
+func (oc *Controller) retryEgressIPAssignments(nodeName string) {
 	oc.eIPC.assignmentRetryMutex.Lock()
 	defer oc.eIPC.assignmentRetryMutex.Unlock()
 	for eIPName := range oc.eIPC.assignmentRetry {
-		klog.V(5).Infof("Re-assignment for EgressIP: %s attempted by new node: %s", eIPName, egressNode.Name)
+		klog.V(5).Infof("Re-assignment for EgressIP: %s attempted by node: %s", eIPName, nodeName)
 		eIP, err := oc.kube.GetEgressIP(eIPName)
 		if errors.IsNotFound(err) {
 			klog.Errorf("Re-assignment for EgressIP: EgressIP: %s not found in the api-server, err: %v", eIPName, err)
@@ -460,7 +700,6 @@ func (oc *Controller) addEgressNode(egressNode *kapi.Node) error {
 			delete(oc.eIPC.assignmentRetry, eIP.Name)
 		}
 	}
-	return nil
 }
 
 func (oc *Controller) deleteEgressNode(egressNode *kapi.Node) error {
@@ -514,6 +753,262 @@ func (oc *Controller) reassignEgressIP(eIP *egressipv1.EgressIP) (*egressipv1.Eg
 	return eIP, reassignError
 }
 
+// rebalanceEgressIPs moves one egress IP from the most loaded egress node to a less loaded
+// one, when they host a number of egress IPs which differs by more than one. Nodes which
+// recover or get labeled thereby get egress IPs back over time. At most one egress IP is
+// moved per EgressIPRebalanceInterval.
+func (oc *Controller) rebalanceEgressIPs() {
+	oc.eIPC.assignmentRetryMutex.Lock()
+	defer oc.eIPC.assignmentRetryMutex.Unlock()
+	interval := time.Duration(config.OVNKubernetesFeature.EgressIPRebalanceInterval) * time.Second
+	if time.Since(oc.eIPC.lastRebalance) < interval {
+		return
+	}
+	egressIPs, err := oc.kube.GetEgressIPs()
+	if err != nil {
+		klog.Errorf("Rebalancing of egress IPs: unable to list EgressIPs, err: %v", err)
+		return
+	}
+	eIP, status, newStatus := oc.getEgressIPMove(egressIPs.Items)
+	if eIP == nil {
+		return
+	}
+	oc.eIPC.lastRebalance = time.Now()
+	if err := oc.moveEgressIP(eIP, status, newStatus); err != nil {
+		klog.Errorf("Rebalancing of egress IPs: unable to move egress IP: %s of EgressIP: %s from node: %s to node: %s, err: %v",
+			status.EgressIP, eIP.Name, status.Node, newStatus.Node, err)
+	}
+}
+
+// getEgressIPMove returns an egress IP assignment of the most loaded egress node which can be
+// moved to a less loaded one, together with its assignment to that node. It returns a nil
+// EgressIP if the egress IPs are evenly spread, or if none of them can be moved.
+func (oc *Controller) getEgressIPMove(eIPs []egressipv1.EgressIP) (*egressipv1.EgressIP, egressipv1.EgressIPStatusItem, egressipv1.EgressIPStatusItem) {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	assignableNodes, _ := oc.getSortedEgressData()
+	sort.Slice(eIPs, func(i, j int) bool {
+		return eIPs[i].Name < eIPs[j].Name
+	})
+	for i := len(assignableNodes) - 1; i > 0; i-- {
+		from := assignableNodes[i]
+		for j := 0; j < i; j++ {
+			to := assignableNodes[j]
+			if len(from.allocations)-len(to.allocations) <= 1 {
+				break
+			}
+			if !to.hasCapacity() {
+				continue
+			}
+			for k := range eIPs {
+				if status, newStatus, ok := getMovableEgressIP(&eIPs[k], from.name, &to); ok {
+					return &eIPs[k], status, newStatus
+				}
+			}
+		}
+	}
+	return nil, egressipv1.EgressIPStatusItem{}, egressipv1.EgressIPStatusItem{}
+}
+
+// getMovableEgressIP returns the egress IP of the EgressIP assigned to node from which node to
+// can host, and its assignment to node to, if the EgressIP has no other egress IP on node to
+func getMovableEgressIP(eIP *egressipv1.EgressIP, from string, to *egressNode) (egressipv1.EgressIPStatusItem, egressipv1.EgressIPStatusItem, bool) {
+	var status, newStatus egressipv1.EgressIPStatusItem
+	movable := false
+	for _, item := range eIP.Status.Items {
+		if item.Node == to.name {
+			return status, newStatus, false
+		}
+		if item.Node != from || movable {
+			continue
+		}
+		ip := net.ParseIP(item.EgressIP)
+		if ip == nil {
+			continue
+		}
+		if egressInterface, ok := to.getEgressInterface(ip); ok {
+			status, movable = item, true
+			newStatus = egressipv1.EgressIPStatusItem{
+				EgressIP:  item.EgressIP,
+				Node:      to.name,
+				Interface: egressInterface,
+			}
+		}
+	}
+	return status, newStatus, movable
+}
+
+// moveEgressIP replaces the egress IP assignment of the EgressIP with the new one. The NAT rules
+// and reroute policies of the pods towards the old node are replaced by the ones towards the new
+// node in a single transaction, so that the pods are never left without an egress IP.
+func (oc *Controller) moveEgressIP(eIP *egressipv1.EgressIP, status, newStatus egressipv1.EgressIPStatusItem) error {
+	klog.Infof("Rebalancing of egress IPs: moving egress IP: %s of EgressIP: %s from node: %s to node: %s",
+		status.EgressIP, eIP.Name, status.Node, newStatus.Node)
+	if newStatus.Interface != "" {
+		if err := oc.allocateEgressIPMark(eIP); err != nil {
+			return err
+		}
+	}
+	pods, err := oc.getEgressIPPods(eIP)
+	if err != nil {
+		return err
+	}
+	if err := oc.eIPC.swapEgressIPAssignment(eIP.Name, status, newStatus, pods); err != nil {
+		return err
+	}
+
+	for i := range eIP.Status.Items {
+		if eIP.Status.Items[i] == status {
+			eIP.Status.Items[i] = newStatus
+		}
+	}
+	if err := oc.updateEgressIPWithRetry(eIP); err != nil {
+		for i := range eIP.Status.Items {
+			if eIP.Status.Items[i] == newStatus {
+				eIP.Status.Items[i] = status
+			}
+		}
+		if revertErr := oc.eIPC.swapEgressIPAssignment(eIP.Name, newStatus, status, pods); revertErr != nil {
+			klog.Errorf("Rebalancing of egress IPs: unable to restore egress IP: %s of EgressIP: %s on node: %s, err: %v",
+				status.EgressIP, eIP.Name, status.Node, revertErr)
+		}
+		return err
+	}
+	oc.eIPC.allocatorMutex.Lock()
+	if eNode, exists := oc.eIPC.allocator[status.Node]; exists {
+		delete(eNode.allocations, status.EgressIP)
+	}
+	if eNode, exists := oc.eIPC.allocator[newStatus.Node]; exists {
+		eNode.allocations[status.EgressIP] = true
+	}
+	oc.eIPC.allocatorMutex.Unlock()
+
+	// Re-create the watchers of the EgressIP, so that pods matched from now on get the new
+	// assignment, after moving the pods the old watchers matched meanwhile
+	oc.deleteEgressIPHandlers(eIP)
+	movedPods := sets.NewString()
+	for _, pod := range pods {
+		movedPods.Insert(pod.Namespace + "/" + pod.Name)
+	}
+	var addedPods []kapi.Pod
+	newPods, err := oc.getEgressIPPods(eIP)
+	if err == nil {
+		for _, pod := range newPods {
+			if !movedPods.Has(pod.Namespace + "/" + pod.Name) {
+				addedPods = append(addedPods, pod)
+			}
+		}
+		err = oc.eIPC.swapEgressIPAssignment(eIP.Name, status, newStatus, addedPods)
+	}
+	if addErr := oc.addEgressIP(eIP); addErr != nil {
+		return addErr
+	}
+	return err
+}
+
+// swapEgressIPAssignment replaces the NAT rules and reroute policies of the pods for the old
+// egress IP assignment with the ones for the new assignment, in a single transaction
+func (e *egressIPController) swapEgressIPAssignment(egressIPName string, oldStatus, newStatus egressipv1.EgressIPStatusItem, pods []kapi.Pod) error {
+	removeArgs, err := e.getEgressIPAssignmentArgs(egressIPName, oldStatus, pods, true)
+	if err != nil {
+		return err
+	}
+	createArgs, err := e.getEgressIPAssignmentArgs(egressIPName, newStatus, pods, false)
+	if err != nil {
+		return err
+	}
+	args := append(removeArgs, createArgs...)
+	if len(args) == 0 {
+		return nil
+	}
+	if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+		return fmt.Errorf("unable to move egress IP: %s from node: %s to node: %s, stderr: %s, err: %v",
+			oldStatus.EgressIP, oldStatus.Node, newStatus.Node, stderr, err)
+	}
+	return nil
+}
+
+// getEgressIPAssignmentArgs returns the ovn-nbctl commands creating, or removing if remove is
+// true, the NAT rules and reroute policies of the pods for the egress IP assignment, skipping
+// the ones which already exist, or don't. Each row created gets its own id so that the
+// commands can be run in a single transaction.
+func (e *egressIPController) getEgressIPAssignmentArgs(egressIPName string, status egressipv1.EgressIPStatusItem, pods []kapi.Pod, remove bool) ([]string, error) {
+	if len(pods) == 0 {
+		return nil, nil
+	}
+	isEgressIPv6 := utilnet.IsIPv6String(status.EgressIP)
+	gatewayRouterIP, err := e.getEgressIPNextHop(status, isEgressIPv6)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve next hop for node: %s, err: %v", status.Node, err)
+	}
+	var args []string
+	var rows int
+	for _, pod := range pods {
+		for _, podIP := range e.getPodIPs(&pod) {
+			if utilnet.IsIPv6(podIP) != isEgressIPv6 {
+				continue
+			}
+			filterOption := fmt.Sprintf("ip4.src == %s", podIP.String())
+			if isEgressIPv6 {
+				filterOption = fmt.Sprintf("ip6.src == %s", podIP.String())
+			}
+			policyIDs, err := findReroutePolicyIDs(filterOption, egressIPName, gatewayRouterIP)
+			if err != nil {
+				return nil, err
+			}
+			var natIDs []string
+			if status.Interface == "" {
+				natIDs, err = findNatIDs(egressIPName, podIP.String(), status.EgressIP, status.Node)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if remove {
+				for _, policyID := range policyIDs {
+					args = append(append(args, "--"), reroutePolicyRemoveArgs(policyID)...)
+				}
+				for _, natID := range natIDs {
+					args = append(append(args, "--"), natRuleRemoveArgs(natID, status)...)
+				}
+				continue
+			}
+			rows++
+			if status.Interface == "" && natIDs == nil {
+				args = append(append(args, "--"), natRuleCreateArgs(fmt.Sprintf("@nat%d", rows), podIP, status, egressIPName)...)
+			}
+			if policyIDs == nil {
+				policyArgs, err := e.reroutePolicyCreateArgs(fmt.Sprintf("@lr-policy%d", rows), filterOption, gatewayRouterIP, status, egressIPName)
+				if err != nil {
+					return nil, err
+				}
+				args = append(append(args, "--"), policyArgs...)
+			}
+		}
+	}
+	return args, nil
+}
+
+// getEgressIPPods returns the pods matched by the EgressIP which have an OVN egress setup
+func (oc *Controller) getEgressIPPods(eIP *egressipv1.EgressIP) ([]kapi.Pod, error) {
+	namespaces, err := oc.kube.GetNamespaces(eIP.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	egressPods := []kapi.Pod{}
+	for _, namespace := range namespaces.Items {
+		pods, err := oc.kube.GetPods(namespace.Name, eIP.Spec.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			if !pod.Spec.HostNetwork && !oc.eIPC.needsRetry(&pod) && oc.eIPC.getPodIPs(&pod) != nil {
+				egressPods = append(egressPods, pod)
+			}
+		}
+	}
+	return egressPods, nil
+}
+
 func (oc *Controller) initEgressIPAllocator(node *kapi.Node) (err error) {
 	oc.eIPC.allocatorMutex.Lock()
 	defer oc.eIPC.allocatorMutex.Unlock()
@@ -545,9 +1040,63 @@ func (oc *Controller) initEgressIPAllocator(node *kapi.Node) (err error) {
 			allocations: make(map[string]bool),
 		}
 	}
+	// the secondary host interfaces, the capacity and the management port of the node can be
+	// published after the node is initialized
+	oc.eIPC.allocator[node.Name].egressInterfaces = getNodeEgressInterfaces(node)
+	oc.eIPC.allocator[node.Name].capacity = getNodeEgressIPCapacity(node)
+	if hostSubnets, err := util.ParseNodeHostSubnetAnnotation(node); err == nil {
+		mgmtPortIPs := []*net.IPNet{}
+		for _, hostSubnet := range hostSubnets {
+			mgmtPortIPs = append(mgmtPortIPs, util.GetNodeManagementIfAddr(hostSubnet))
+		}
+		oc.eIPC.mgmtPortIPCache.Store(node.Name, mgmtPortIPs)
+	}
 	return nil
 }
 
+// getNodeEgressInterfaces returns the secondary host interfaces which the node published for
+// egress IPs, skipping the ones which cannot be parsed
+func getNodeEgressInterfaces(node *kapi.Node) []egressInterface {
+	nodeEgressInterfaces, err := util.ParseNodeEgressInterfaces(node)
+	if err != nil {
+		if !util.IsAnnotationNotSetError(err) {
+			klog.Errorf("Unable to use the secondary interfaces of node: %s for egress assignment, err: %v", node.Name, err)
+		}
+		return nil
+	}
+	egressInterfaces := []egressInterface{}
+	for _, nodeEgressInterface := range nodeEgressInterfaces {
+		egressInterface := egressInterface{name: nodeEgressInterface.Name}
+		var err error
+		if nodeEgressInterface.IPv4 != "" {
+			if egressInterface.v4IP, egressInterface.v4Subnet, err = net.ParseCIDR(nodeEgressInterface.IPv4); err != nil {
+				klog.Errorf("Unable to use interface: %s of node: %s for egress assignment, err: %v", nodeEgressInterface.Name, node.Name, err)
+				continue
+			}
+		}
+		if nodeEgressInterface.IPv6 != "" {
+			if egressInterface.v6IP, egressInterface.v6Subnet, err = net.ParseCIDR(nodeEgressInterface.IPv6); err != nil {
+				klog.Errorf("Unable to use interface: %s of node: %s for egress assignment, err: %v", nodeEgressInterface.Name, node.Name, err)
+				continue
+			}
+		}
+		egressInterfaces = append(egressInterfaces, egressInterface)
+	}
+	return egressInterfaces
+}
+
+// getNodeEgressIPCapacity returns the maximum number of egress IPs the node can host
+func getNodeEgressIPCapacity(node *kapi.Node) int {
+	capacity, err := util.ParseNodeEgressIPCapacity(node)
+	if err != nil {
+		if !util.IsAnnotationNotSetError(err) {
+			klog.Errorf("Unable to use the egress IP capacity of node: %s, err: %v", node.Name, err)
+		}
+		return egressIPUnlimitedCapacity
+	}
+	return capacity
+}
+
 func (oc *Controller) addNodeForEgress(node *v1.Node) error {
 	v4Addr, v6Addr := getNodeInternalAddrs(node)
 	v4ClusterSubnet, v6ClusterSubnet := getClusterSubnets()
@@ -575,6 +1124,17 @@ func (oc *Controller) deleteNodeForEgress(node *v1.Node) error {
 func (oc *Controller) initClusterEgressPolicies(nodes []interface{}) {
 	v4ClusterSubnet, v6ClusterSubnet := getClusterSubnets()
 	createDefaultNoReroutePodPolicies(v4ClusterSubnet, v6ClusterSubnet)
+	if err := syncExcludedDestinationNoReroutePolicies(v4ClusterSubnet, v6ClusterSubnet); err != nil {
+		klog.Errorf("Unable to sync the no-reroute logical router policies of the egress IP excluded destinations: %v", err)
+	}
+	if config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort != 0 {
+		healthCheck, err := newEgressIPHealthCheck()
+		if err != nil {
+			klog.Errorf("Cannot probe the EgressIP health endpoint of the nodes, falling back to the discard port: %v", err)
+		} else {
+			dialer = healthCheck
+		}
+	}
 	go oc.checkEgressNodesReachability()
 }
 
@@ -590,6 +1150,50 @@ type egressNode struct {
 	isEgressAssignable bool
 	tainted            bool
 	name               string
+
+	// number of consecutive failed reachability checks
+	reachabilityFailures int
+
+	// secondary host interfaces which can host egress IPs
+	egressInterfaces []egressInterface
+
+	// maximum number of egress IPs the node can host
+	capacity int
+}
+
+// hasCapacity returns true if the node can host another egress IP. A node whose capacity is
+// lowered keeps the egress IPs it hosts.
+func (n *egressNode) hasCapacity() bool {
+	return len(n.allocations) < n.capacity
+}
+
+// egressInterface is a secondary host interface of an egress node
+type egressInterface struct {
+	name     string
+	v4IP     net.IP
+	v6IP     net.IP
+	v4Subnet *net.IPNet
+	v6Subnet *net.IPNet
+}
+
+// getEgressInterface returns the interface of the node whose subnet contains the egress IP, which
+// is empty for the primary interface. The primary interface is preferred.
+func (n *egressNode) getEgressInterface(ip net.IP) (string, bool) {
+	if (n.v6Subnet != nil && n.v6Subnet.Contains(ip)) || (n.v4Subnet != nil && n.v4Subnet.Contains(ip)) {
+		return "", true
+	}
+	for _, egressInterface := range n.egressInterfaces {
+		if (egressInterface.v6Subnet != nil && egressInterface.v6Subnet.Contains(ip)) ||
+			(egressInterface.v4Subnet != nil && egressInterface.v4Subnet.Contains(ip)) {
+			return egressInterface.name, true
+		}
+	}
+	return "", false
+}
+
+type egressIPAssignmentCount struct {
+	assigned   int
+	unassigned int
 }
 
 type egressIPController struct {
@@ -599,12 +1203,18 @@ type egressIPController struct {
 	// Cache of gateway join router IPs, usefull since these should not change often
 	gatewayIPCache sync.Map
 
+	// Cache of management port IPs, used as next hop for egress IPs on secondary host interfaces
+	mgmtPortIPCache sync.Map
+
 	// Mutex used for syncing the map retrying EgressIP objects
 	assignmentRetryMutex *sync.Mutex
 
 	// Cache used for retrying EgressIP objects which were created before any node existed.
 	assignmentRetry map[string]bool
 
+	// The time of the last egress IP move of the rebalancer, protected by assignmentRetryMutex
+	lastRebalance time.Time
+
 	// Mutex used for syncing the egressIP namespace handlers
 	namespaceHandlerMutex *sync.Mutex
 
@@ -621,6 +1231,12 @@ type egressIPController struct {
 	// used for egress IP assignments
 	allocator map[string]*egressNode
 
+	// The number of assigned and unassigned egress IPs of each EgressIP, protected by allocatorMutex
+	assignmentCounts map[string]egressIPAssignmentCount
+
+	// The packet marks of the EgressIPs with egress IPs on secondary host interfaces, protected by allocatorMutex
+	marks map[string]int
+
 	// A mutex for allocator
 	allocatorMutex *sync.Mutex
 }
@@ -696,6 +1312,28 @@ func (e *egressIPController) getGatewayRouterJoinIP(node string, wantsIPv6 bool)
 	}
 }
 
+// getEgressIPNextHop returns the IP the traffic of the pods is rerouted to for the egress IP assignment:
+// the gateway router of the egress node, or its management port if the egress IP is assigned to a
+// secondary host interface, in which case the node itself SNATs and routes the traffic
+func (e *egressIPController) getEgressIPNextHop(status egressipv1.EgressIPStatusItem, wantsIPv6 bool) (net.IP, error) {
+	if status.Interface == "" {
+		return e.getGatewayRouterJoinIP(status.Node, wantsIPv6)
+	}
+	item, exists := e.mgmtPortIPCache.Load(status.Node)
+	if !exists {
+		return nil, fmt.Errorf("node %s management port IP is unknown", status.Node)
+	}
+	mgmtPortIPs, ok := item.([]*net.IPNet)
+	if !ok {
+		return nil, fmt.Errorf("unable to cast node: %s management port IP cache item to correct type", status.Node)
+	}
+	mgmtPortIP, err := util.MatchIPNetFamily(wantsIPv6, mgmtPortIPs)
+	if err != nil {
+		return nil, fmt.Errorf("could not find node %s management port IP: %v", status.Node, err)
+	}
+	return mgmtPortIP.IP, nil
+}
+
 func (e *egressIPController) getPodIPs(pod *kapi.Pod) []net.IP {
 	if len(pod.Status.PodIPs) == 0 {
 		return nil
@@ -717,9 +1355,9 @@ func (e *egressIPController) needsRetry(pod *kapi.Pod) bool {
 // local gateway mode case
 func (e *egressIPController) createEgressReroutePolicy(podIps []net.IP, status egressipv1.EgressIPStatusItem, egressIPName string) error {
 	isEgressIPv6 := utilnet.IsIPv6String(status.EgressIP)
-	gatewayRouterIP, err := e.getGatewayRouterJoinIP(status.Node, isEgressIPv6)
+	gatewayRouterIP, err := e.getEgressIPNextHop(status, isEgressIPv6)
 	if err != nil {
-		return fmt.Errorf("unable to retrieve gateway IP for node: %s, err: %v", status.Node, err)
+		return fmt.Errorf("unable to retrieve next hop for node: %s, err: %v", status.Node, err)
 	}
 	for _, podIP := range podIps {
 		var err error
@@ -734,22 +1372,11 @@ func (e *egressIPController) createEgressReroutePolicy(podIps []net.IP, status e
 			return err
 		}
 		if policyIDs == nil {
-			_, stderr, err = util.RunOVNNbctl(
-				"--id=@lr-policy",
-				"create",
-				"logical_router_policy",
-				"action=reroute",
-				fmt.Sprintf("match=\"%s\"", filterOption),
-				fmt.Sprintf("priority=%v", types.EgressIPReroutePriority),
-				fmt.Sprintf("nexthop=%s", gatewayRouterIP),
-				fmt.Sprintf("external_ids:name=%s", egressIPName),
-				"--",
-				"add",
-				"logical_router",
-				types.OVNClusterRouter,
-				"policies",
-				"@lr-policy",
-			)
+			args, err := e.reroutePolicyCreateArgs("@lr-policy", filterOption, gatewayRouterIP, status, egressIPName)
+			if err != nil {
+				return err
+			}
+			_, stderr, err = util.RunOVNNbctl(args...)
 			if err != nil {
 				return fmt.Errorf("unable to create logical router policy: %s, stderr: %s, err: %v", status.EgressIP, stderr, err)
 			}
@@ -760,9 +1387,9 @@ func (e *egressIPController) createEgressReroutePolicy(podIps []net.IP, status e
 
 func (e *egressIPController) deleteEgressReroutePolicy(podIps []net.IP, status egressipv1.EgressIPStatusItem, egressIPName string) error {
 	isEgressIPv6 := utilnet.IsIPv6String(status.EgressIP)
-	gatewayRouterIP, err := e.getGatewayRouterJoinIP(status.Node, isEgressIPv6)
+	gatewayRouterIP, err := e.getEgressIPNextHop(status, isEgressIPv6)
 	if err != nil {
-		return fmt.Errorf("unable to retrieve gateway IP for node: %s, err: %v", status.Node, err)
+		return fmt.Errorf("unable to retrieve next hop for node: %s, err: %v", status.Node, err)
 	}
 	for _, podIP := range podIps {
 		var filterOption string
@@ -776,13 +1403,7 @@ func (e *egressIPController) deleteEgressReroutePolicy(podIps []net.IP, status e
 			return err
 		}
 		for _, policyID := range policyIDs {
-			_, stderr, err := util.RunOVNNbctl(
-				"remove",
-				"logical_router",
-				types.OVNClusterRouter,
-				"policies",
-				policyID,
-			)
+			_, stderr, err := util.RunOVNNbctl(reroutePolicyRemoveArgs(policyID)...)
 			if err != nil {
 				return fmt.Errorf("unable to remove logical router policy: %s, stderr: %s, err: %v", status.EgressIP, stderr, err)
 			}
@@ -791,6 +1412,48 @@ func (e *egressIPController) deleteEgressReroutePolicy(podIps []net.IP, status e
 	return nil
 }
 
+// reroutePolicyCreateArgs returns the ovn-nbctl arguments creating the reroute policy of the
+// pod IP matched by filterOption towards the egress node, as the row id
+func (e *egressIPController) reroutePolicyCreateArgs(id, filterOption string, gatewayRouterIP net.IP, status egressipv1.EgressIPStatusItem, egressIPName string) ([]string, error) {
+	args := []string{
+		"--id=" + id,
+		"create",
+		"logical_router_policy",
+		"action=reroute",
+		fmt.Sprintf("match=\"%s\"", filterOption),
+		fmt.Sprintf("priority=%v", types.EgressIPReroutePriority),
+		fmt.Sprintf("nexthop=%s", gatewayRouterIP),
+		fmt.Sprintf("external_ids:name=%s", egressIPName),
+	}
+	if status.Interface != "" {
+		// the egress node SNATs the traffic with this mark to the egress IP
+		mark, exists := e.getEgressIPMark(egressIPName)
+		if !exists {
+			return nil, fmt.Errorf("no packet mark allocated for EgressIP: %s", egressIPName)
+		}
+		args = append(args, fmt.Sprintf("options:pkt_mark=%d", mark))
+	}
+	return append(args,
+		"--",
+		"add",
+		"logical_router",
+		types.OVNClusterRouter,
+		"policies",
+		id,
+	), nil
+}
+
+// reroutePolicyRemoveArgs returns the ovn-nbctl arguments removing the reroute policy
+func reroutePolicyRemoveArgs(policyID string) []string {
+	return []string{
+		"remove",
+		"logical_router",
+		types.OVNClusterRouter,
+		"policies",
+		policyID,
+	}
+}
+
 func findReroutePolicyIDs(filterOption, egressIPName string, gatewayRouterIP net.IP) ([]string, error) {
 	policyIDs, stderr, err := util.RunOVNNbctl(
 		"--format=csv",
@@ -820,7 +1483,13 @@ func (oc *Controller) checkEgressNodesReachability() {
 		for _, eNode := range oc.eIPC.allocator {
 			if eNode.isEgressAssignable && eNode.isReady {
 				wasReachable := eNode.isReachable
-				isReachable := oc.isReachable(eNode)
+				isReachable := true
+				if oc.isReachable(eNode) {
+					eNode.reachabilityFailures = 0
+				} else {
+					eNode.reachabilityFailures++
+					isReachable = eNode.reachabilityFailures < config.OVNKubernetesFeature.EgressIPReachabilityFailureThreshold
+				}
 				if wasReachable && !isReachable {
 					reAddOrDelete[eNode.name] = true
 				} else if !wasReachable && isReachable {
@@ -847,7 +1516,10 @@ func (oc *Controller) checkEgressNodesReachability() {
 				}
 			}
 		}
-		time.Sleep(5 * time.Second)
+		if config.OVNKubernetesFeature.EnableEgressIPRebalance {
+			oc.rebalanceEgressIPs()
+		}
+		time.Sleep(time.Duration(config.OVNKubernetesFeature.EgressIPReachabilityCheckInterval) * time.Second)
 	}
 }
 
@@ -875,7 +1547,7 @@ type egressIPDial struct{}
 // refused" error; but the code below assumes that anything other than timeout or "no
 // route" indicates that the node is online.
 func (e *egressIPDial) dial(ip net.IP) bool {
-	timeout := time.Second
+	timeout := time.Duration(config.OVNKubernetesFeature.EgressIPReachabilityCheckTimeout) * time.Second
 	conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip.String(), "9"), timeout)
 	if conn != nil {
 		conn.Close()
@@ -891,6 +1563,52 @@ func (e *egressIPDial) dial(ip net.IP) bool {
 	return true
 }
 
+// egressIPHealthCheck probes the EgressIP health endpoint served by ovnkube-node, which
+// reports whether the data path of the node is ready
+type egressIPHealthCheck struct {
+	client *http.Client
+	scheme string
+}
+
+func newEgressIPHealthCheck() (*egressIPHealthCheck, error) {
+	e := &egressIPHealthCheck{
+		client: &http.Client{
+			Timeout: time.Duration(config.OVNKubernetesFeature.EgressIPReachabilityCheckTimeout) * time.Second,
+		},
+		scheme: "http",
+	}
+	if caCert := config.OVNKubernetesFeature.EgressIPNodeHealthCheckCACert; caCert != "" {
+		pemData, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate %s: %v", caCert, err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("failed to parse the CA certificate %s", caCert)
+		}
+		e.client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs}}
+		e.scheme = "https"
+	}
+	return e, nil
+}
+
+func (e *egressIPHealthCheck) dial(ip net.IP) bool {
+	url := fmt.Sprintf("%s://%s%s", e.scheme,
+		net.JoinHostPort(ip.String(), strconv.Itoa(config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort)),
+		types.EgressIPNodeHealthCheckPath)
+	resp, err := e.client.Get(url)
+	if err != nil {
+		klog.V(5).Infof("EgressIP health check of %s failed: %v", url, err)
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		klog.V(5).Infof("EgressIP health check of %s failed with status %s", url, resp.Status)
+		return false
+	}
+	return true
+}
+
 func getClusterSubnets() (*net.IPNet, *net.IPNet) {
 	var v4ClusterSubnet, v6ClusterSubnet *net.IPNet
 	for _, clusterSubnet := range config.Default.ClusterSubnets {
@@ -937,6 +1655,91 @@ func createDefaultNoReroutePodPolicies(v4ClusterSubnet, v6ClusterSubnet *net.IPN
 	}
 }
 
+// syncExcludedDestinationNoReroutePolicies ensures that egress pods reach the configured excluded
+// destinations with the node IP, and removes the policies of destinations no longer excluded
+func syncExcludedDestinationNoReroutePolicies(v4ClusterSubnet, v6ClusterSubnet *net.IPNet) error {
+	existingPolicyIDs, err := findExcludedDestinationPolicyIDs("")
+	if err != nil {
+		return err
+	}
+	policyIDs := sets.NewString()
+	for _, destination := range config.OVNKubernetesFeature.EgressIPExcludedDestinations {
+		var match string
+		if utilnet.IsIPv6CIDR(destination) && v6ClusterSubnet != nil {
+			match = fmt.Sprintf("ip6.src == %s && ip6.dst == %s", v6ClusterSubnet.String(), destination.String())
+		} else if !utilnet.IsIPv6CIDR(destination) && v4ClusterSubnet != nil {
+			match = fmt.Sprintf("ip4.src == %s && ip4.dst == %s", v4ClusterSubnet.String(), destination.String())
+		} else {
+			klog.Warningf("Egress IP excluded destination: %s does not match the IP family of any cluster subnet, ignoring it", destination)
+			continue
+		}
+		ids, err := findExcludedDestinationPolicyIDs(match)
+		if err != nil {
+			return err
+		}
+		if ids != nil {
+			policyIDs.Insert(ids...)
+			continue
+		}
+		_, stderr, err := util.RunOVNNbctl(
+			"--id=@lr-policy",
+			"create",
+			"logical_router_policy",
+			"action=allow",
+			fmt.Sprintf("match=\"%s\"", match),
+			fmt.Sprintf("priority=%v", types.DefaultNoRereoutePriority),
+			fmt.Sprintf("external_ids:name=%s", egressIPExcludedDestinationPolicyName),
+			"--",
+			"add",
+			"logical_router",
+			types.OVNClusterRouter,
+			"policies",
+			"@lr-policy",
+		)
+		if err != nil {
+			return fmt.Errorf("unable to create no-reroute logical router policy for excluded destination: %s, stderr: %s, err: %v", destination, stderr, err)
+		}
+	}
+	for _, policyID := range existingPolicyIDs {
+		if policyIDs.Has(policyID) {
+			continue
+		}
+		_, stderr, err := util.RunOVNNbctl("remove", "logical_router", types.OVNClusterRouter, "policies", policyID)
+		if err != nil {
+			return fmt.Errorf("unable to remove stale no-reroute logical router policy: %s, stderr: %s, err: %v", policyID, stderr, err)
+		}
+	}
+	return nil
+}
+
+// findExcludedDestinationPolicyIDs returns the no-reroute logical router policies of the egress
+// IP excluded destinations with the match, or all of them if the match is empty
+func findExcludedDestinationPolicyIDs(match string) ([]string, error) {
+	args := []string{
+		"--format=csv",
+		"--data=bare",
+		"--no-heading",
+		"--columns=_uuid",
+		"find",
+		"logical_router_policy",
+	}
+	if match != "" {
+		args = append(args, fmt.Sprintf("match=\"%s\"", match))
+	}
+	args = append(args,
+		fmt.Sprintf("priority=%v", types.DefaultNoRereoutePriority),
+		fmt.Sprintf("external_ids:name=%s", egressIPExcludedDestinationPolicyName),
+	)
+	policyIDs, stderr, err := util.RunOVNNbctl(args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find no-reroute logical router policies of excluded destinations, stderr: %s, err: %v", stderr, err)
+	}
+	if policyIDs == "" {
+		return nil, nil
+	}
+	return strings.Split(policyIDs, "\n"), nil
+}
+
 // createDefaultNoRerouteNodePolicies ensures egress pods east<->west traffic with hostNetwork pods,
 // i.e: ensuring that an egress pod can still communicate with a hostNetwork pod / service backed by hostNetwork pods
 func createDefaultNoRerouteNodePolicies(v4NodeAddr, v6NodeAddr net.IP, v4ClusterSubnet, v6ClusterSubnet *net.IPNet) error {
@@ -976,29 +1779,18 @@ func deleteDefaultNoRerouteNodePolicies(v4NodeAddr, v6NodeAddr net.IP, v4Cluster
 }
 
 func createNATRule(podIPs []net.IP, status egressipv1.EgressIPStatusItem, egressIPName string) error {
+	if status.Interface != "" {
+		// the egress node SNATs the traffic of egress IPs assigned to its secondary host interfaces
+		return nil
+	}
 	for _, podIP := range podIPs {
 		if (utilnet.IsIPv6String(status.EgressIP) && utilnet.IsIPv6(podIP)) || (!utilnet.IsIPv6String(status.EgressIP) && !utilnet.IsIPv6(podIP)) {
-			natIDs, err := findNatIDs(egressIPName, podIP.String(), status.EgressIP)
+			natIDs, err := findNatIDs(egressIPName, podIP.String(), status.EgressIP, status.Node)
 			if err != nil {
 				return err
 			}
 			if natIDs == nil {
-				_, stderr, err := util.RunOVNNbctl(
-					"--id=@nat",
-					"create",
-					"nat",
-					"type=snat",
-					fmt.Sprintf("logical_port=k8s-%s", status.Node),
-					fmt.Sprintf("external_ip=%s", status.EgressIP),
-					fmt.Sprintf("logical_ip=%s", podIP),
-					fmt.Sprintf("external_ids:name=%s", egressIPName),
-					"--",
-					"add",
-					"logical_router",
-					fmt.Sprintf("GR_%s", status.Node),
-					"nat",
-					"@nat",
-				)
+				_, stderr, err := util.RunOVNNbctl(natRuleCreateArgs("@nat", podIP, status, egressIPName)...)
 				if err != nil {
 					return fmt.Errorf("unable to create nat rule, stderr: %s, err: %v", stderr, err)
 				}
@@ -1009,20 +1801,17 @@ func createNATRule(podIPs []net.IP, status egressipv1.EgressIPStatusItem, egress
 }
 
 func deleteNATRule(podIPs []net.IP, status egressipv1.EgressIPStatusItem, egressIPName string) error {
+	if status.Interface != "" {
+		return nil
+	}
 	for _, podIP := range podIPs {
 		if (utilnet.IsIPv6String(status.EgressIP) && utilnet.IsIPv6(podIP)) || (!utilnet.IsIPv6String(status.EgressIP) && !utilnet.IsIPv6(podIP)) {
-			natIDs, err := findNatIDs(egressIPName, podIP.String(), status.EgressIP)
+			natIDs, err := findNatIDs(egressIPName, podIP.String(), status.EgressIP, status.Node)
 			if err != nil {
 				return err
 			}
 			for _, natID := range natIDs {
-				_, stderr, err := util.RunOVNNbctl(
-					"remove",
-					"logical_router",
-					fmt.Sprintf("GR_%s", status.Node),
-					"nat",
-					natID,
-				)
+				_, stderr, err := util.RunOVNNbctl(natRuleRemoveArgs(natID, status)...)
 				if err != nil {
 					return fmt.Errorf("unable to remove nat from logical_router, stderr: %s, err: %v", stderr, err)
 				}
@@ -1032,7 +1821,42 @@ func deleteNATRule(podIPs []net.IP, status egressipv1.EgressIPStatusItem, egress
 	return nil
 }
 
-func findNatIDs(egressIPName, podIP, egressIP string) ([]string, error) {
+// natRuleCreateArgs returns the ovn-nbctl arguments creating the NAT rule of the pod IP on the
+// gateway router of the egress node, as the row id
+func natRuleCreateArgs(id string, podIP net.IP, status egressipv1.EgressIPStatusItem, egressIPName string) []string {
+	return []string{
+		"--id=" + id,
+		"create",
+		"nat",
+		"type=snat",
+		fmt.Sprintf("logical_port=k8s-%s", status.Node),
+		fmt.Sprintf("external_ip=%s", status.EgressIP),
+		fmt.Sprintf("logical_ip=%s", podIP),
+		fmt.Sprintf("external_ids:name=%s", egressIPName),
+		"--",
+		"add",
+		"logical_router",
+		fmt.Sprintf("GR_%s", status.Node),
+		"nat",
+		id,
+	}
+}
+
+// natRuleRemoveArgs returns the ovn-nbctl arguments removing the NAT rule from the gateway
+// router of the egress node
+func natRuleRemoveArgs(natID string, status egressipv1.EgressIPStatusItem) []string {
+	return []string{
+		"remove",
+		"logical_router",
+		fmt.Sprintf("GR_%s", status.Node),
+		"nat",
+		natID,
+	}
+}
+
+// findNatIDs finds the NAT rules of the pod on the given node, so that a stale NAT rule
+// left on another node by an interrupted move is never mistaken for this one
+func findNatIDs(egressIPName, podIP, egressIP, node string) ([]string, error) {
 	natIDs, stderr, err := util.RunOVNNbctl(
 		"--format=csv",
 		"--data=bare",
@@ -1043,6 +1867,7 @@ func findNatIDs(egressIPName, podIP, egressIP string) ([]string, error) {
 		fmt.Sprintf("external_ids:name=%s", egressIPName),
 		fmt.Sprintf("logical_ip=%s", podIP),
 		fmt.Sprintf("external_ip=%s", egressIP),
+		fmt.Sprintf("logical_port=k8s-%s", node),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to find nat ID, stderr: %s, err: %v", stderr, err)
@@ -2,8 +2,16 @@ package ovn
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
@@ -89,6 +97,7 @@ func setupNode(nodeName string, ipNets []string, mockAllocationIPs []string) egr
 		isReady:            true,
 		isReachable:        true,
 		isEgressAssignable: true,
+		capacity:           egressIPUnlimitedCapacity,
 	}
 	return node
 }
@@ -245,6 +254,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 					},
 				)
@@ -294,7 +304,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip4.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv4),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip4.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv4, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP, node2.Name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.Name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.Name),
 					},
 				)
@@ -384,6 +394,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 					},
 				)
@@ -432,7 +443,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip4.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv4),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip4.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv4, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP, node2.Name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.Name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.Name),
 					},
 				)
@@ -504,7 +515,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP, node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -536,7 +547,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				)
 				fakeOvn.fakeExec.AddFakeCmd(
 					&ovntest.ExpectedCmd{
-						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat %s %s %s", fmt.Sprintf("external_ids:name=%s", egressIPName), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ip=%s", egressIP.String())),
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat %s %s %s %s", fmt.Sprintf("external_ids:name=%s", egressIPName), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ip=%s", egressIP.String()), fmt.Sprintf("logical_port=k8s-%s", node2.name)),
 						Output: natID,
 					},
 				)
@@ -605,7 +616,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP, node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -701,7 +712,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", podV6IP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", podV6IP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, podV6IP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, podV6IP, egressIP, node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", podV6IP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -827,7 +838,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP, node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -859,7 +870,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				)
 				fakeOvn.fakeExec.AddFakeCmd(
 					&ovntest.ExpectedCmd{
-						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat %s %s %s", fmt.Sprintf("external_ids:name=%s", egressIPName), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ip=%s", egressIP.String())),
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat %s %s %s %s", fmt.Sprintf("external_ids:name=%s", egressIPName), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ip=%s", egressIP.String()), fmt.Sprintf("logical_port=k8s-%s", node2.name)),
 						Output: natID,
 					},
 				)
@@ -992,7 +1003,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP, node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", egressIP), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -1036,7 +1047,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				)
 				fakeOvn.fakeExec.AddFakeCmd(
 					&ovntest.ExpectedCmd{
-						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat %s %s %s", fmt.Sprintf("external_ids:name=%s", egressIPName), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ip=%s", egressIP.String())),
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat %s %s %s %s", fmt.Sprintf("external_ids:name=%s", egressIPName), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ip=%s", egressIP.String()), fmt.Sprintf("logical_port=k8s-%s", node2.name)),
 						Output: natID,
 					},
 				)
@@ -1049,7 +1060,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, updatedEgressIP.String()),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, updatedEgressIP.String(), node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", updatedEgressIP.String()), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -1116,7 +1127,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthop=%s", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, eIP.Name, nodeLogicalRouterIPv6),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"%s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", fmt.Sprintf("ip6.src == %s", egressPod.Status.PodIP), types.EgressIPReroutePriority, nodeLogicalRouterIPv6, eIP.Name, types.OVNClusterRouter),
-						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s", eIP.Name, egressPod.Status.PodIP, egressIP.String()),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP.Name, egressPod.Status.PodIP, egressIP.String(), node2.name),
 						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node2.name), fmt.Sprintf("external_ip=%s", egressIP.String()), fmt.Sprintf("logical_ip=%s", egressPod.Status.PodIP), fmt.Sprintf("external_ids:name=%s", eIP.Name), node2.name),
 					},
 				)
@@ -1210,6 +1221,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
 					},
@@ -1277,6 +1289,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 					},
 				)
@@ -1352,6 +1365,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressNodes()
@@ -1464,6 +1478,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
 					},
@@ -1550,6 +1565,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 					},
 				)
@@ -1645,6 +1661,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressNodes()
@@ -1769,6 +1786,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 
@@ -1891,6 +1909,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
 						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
 					},
@@ -2120,6 +2139,134 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("should report why egress IPs are not assigned in the status", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP1 := "0:0:0:0:0:feff:c0a8:8e0d"
+				egressIP2 := "0:0:0:0:0:feff:c0a8:8e0e"
+				egressIP3 := "0:0:0:1:0:feff:c0a8:8e0f"
+
+				node1 := setupNode(node1Name, []string{"0:0:0:0:0:feff:c0a8:8e0c/64"}, []string{})
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1, egressIP2, egressIP3},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.HaveLen(1))
+				gomega.Expect(eIP.Status.Unassigned).To(gomega.Equal([]egressipv1.EgressIPStatusUnassignedItem{
+					{
+						EgressIP: egressIP2,
+						Reason:   egressIPNodeAlreadyAssigned,
+						Message:  "every node which can host the egress IP already hosts another egress IP of this EgressIP",
+					},
+					{
+						EgressIP: egressIP3,
+						Reason:   egressIPNoMatchingNode,
+						Message:  "no assignable node has a subnet containing the egress IP",
+					},
+				}))
+				gomega.Expect(eIP.Status.Conditions).To(gomega.HaveLen(1))
+				gomega.Expect(eIP.Status.Conditions[0].Type).To(gomega.Equal(egressipv1.EgressIPAssigned))
+				gomega.Expect(eIP.Status.Conditions[0].Status).To(gomega.Equal(metav1.ConditionFalse))
+				gomega.Expect(eIP.Status.Conditions[0].Reason).To(gomega.Equal(egressIPPartiallyAssigned))
+				gomega.Expect(eIP.Status.Conditions[0].Message).To(gomega.Equal("1 of 3 egress IPs assigned"))
+
+				fakeOvn.controller.recordEgressIPAssignments(eIP.Name, &eIP.Status, len(eIP.Spec.EgressIPs))
+				gomega.Expect(fakeOvn.controller.eIPC.assignmentCounts[eIP.Name]).To(gomega.Equal(egressIPAssignmentCount{assigned: 1, unassigned: 2}))
+				fakeOvn.controller.recordEgressIPAssignments(eIP.Name, nil, 0)
+				gomega.Expect(fakeOvn.controller.eIPC.assignmentCounts).NotTo(gomega.HaveKey(eIP.Name))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should respect the egress IP capacity of the nodes", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP1 := "0:0:0:0:0:feff:c0a8:8e0d"
+				egressIP2 := "0:0:0:0:0:feff:c0a8:8e0e"
+
+				node1 := setupNode(node1Name, []string{"0:0:0:0:0:feff:c0a8:8e0c/64"}, []string{"0:0:0:0:0:feff:c0a8:8e32"})
+				node1.capacity = 1
+				node2 := setupNode(node2Name, []string{"0:0:0:0:0:feff:c0a8:8e0c/64"}, []string{"0:0:0:0:0:feff:c0a8:8e23", "0:0:0:0:0:feff:c0a8:8e24"})
+				node2.capacity = 3
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1, egressIP2},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.Equal([]egressipv1.EgressIPStatusItem{
+					{
+						EgressIP: net.ParseIP(egressIP1).String(),
+						Node:     node2.name,
+					},
+				}))
+				gomega.Expect(eIP.Status.Unassigned).To(gomega.Equal([]egressipv1.EgressIPStatusUnassignedItem{
+					{
+						EgressIP: egressIP2,
+						Reason:   egressIPCapacityExhausted,
+						Message:  "every node which can host the egress IP hosts as many egress IPs as its capacity allows",
+					},
+				}))
+				gomega.Expect(node2.allocations).To(gomega.HaveLen(3))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should prefer the least loaded node with the same number of egress IPs by name", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP := "0:0:0:0:0:feff:c0a8:8e0d"
+
+				node1 := setupNode(node1Name, []string{"0:0:0:0:0:feff:c0a8:8e0c/64"}, []string{"0:0:0:0:0:feff:c0a8:8e32"})
+				node2 := setupNode(node2Name, []string{"0:0:0:0:0:feff:c0a8:8e0c/64"}, []string{"0:0:0:0:0:feff:c0a8:8e23"})
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.HaveLen(1))
+				gomega.Expect(eIP.Status.Items[0].Node).To(gomega.Equal(node1.name))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
 		ginkgo.It("should not be able to allocate already allocated IP", func() {
 			app.Action = func(ctx *cli.Context) error {
 
@@ -2369,6 +2516,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2408,6 +2556,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2448,6 +2597,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2510,6 +2660,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2565,6 +2716,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2615,6 +2767,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2671,6 +2824,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2722,6 +2876,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2771,6 +2926,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2819,6 +2975,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2863,6 +3020,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2913,6 +3071,7 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
 					[]string{
 						fmt.Sprintf("ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
 					},
 				)
 				fakeOvn.controller.WatchEgressIP()
@@ -2951,4 +3110,584 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 	})
+
+	ginkgo.Context("Excluded destinations", func() {
+
+		ginkgo.It("should create no-reroute policies for the excluded destinations and remove the stale ones", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				v4ClusterSubnet, v6ClusterSubnet := getClusterSubnets()
+				gomega.Expect(v6ClusterSubnet).To(gomega.BeNil())
+				excludedMatch := fmt.Sprintf("ip4.src == %s && ip4.dst == 10.0.0.0/8", v4ClusterSubnet)
+				newMatch := fmt.Sprintf("ip4.src == %s && ip4.dst == 172.16.10.5/32", v4ClusterSubnet)
+
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=%s external_ids:name=%s", types.DefaultNoRereoutePriority, egressIPExcludedDestinationPolicyName),
+						Output: "policy1\npolicy2",
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s", excludedMatch, types.DefaultNoRereoutePriority, egressIPExcludedDestinationPolicyName),
+						Output: "policy1",
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s", newMatch, types.DefaultNoRereoutePriority, egressIPExcludedDestinationPolicyName),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=allow match=\"%s\" priority=%s external_ids:name=%s -- add logical_router %s policies @lr-policy", newMatch, types.DefaultNoRereoutePriority, egressIPExcludedDestinationPolicyName, types.OVNClusterRouter),
+						fmt.Sprintf("ovn-nbctl --timeout=15 remove logical_router %s policies policy2", types.OVNClusterRouter),
+					},
+				)
+
+				err := syncExcludedDestinationNoReroutePolicies(v4ClusterSubnet, v6ClusterSubnet)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(fakeOvn.fakeExec.CalledMatchesExpected()).To(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				return nil
+			}
+
+			err := app.Run([]string{
+				app.Name,
+				"-egressip-excluded-destinations=10.0.0.0/8, 172.16.10.5/32,fd00::/64",
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("Secondary host interfaces", func() {
+
+		ginkgo.It("should assign egress IPs to a secondary interface and reroute their pods to the management port", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP := "10.10.0.100"
+				nodeMgmtPortIP := "10.128.0.2"
+
+				egressPod := *newPodWithLabels(namespace, podName, node1Name, podV4IP, egressPodLabel)
+				egressNamespace := newNamespace(namespace)
+
+				node := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node1Name,
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr":    "{\"ipv4\": \"192.168.126.12/24\"}",
+							"k8s.ovn.org/node-egress-interfaces": "[{\"interface\": \"eth1\", \"ipv4\": \"10.10.0.5/24\"}]",
+							"k8s.ovn.org/node-subnets":           "{\"default\": \"10.128.0.0/24\"}",
+						},
+						Labels: map[string]string{
+							"k8s.ovn.org/egress-assignable": "",
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP},
+						PodSelector: metav1.LabelSelector{
+							MatchLabels: egressPodLabel,
+						},
+						NamespaceSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"name": egressNamespace.Name,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{node},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{*egressNamespace},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{egressPod},
+					})
+
+				// the egress node SNATs the traffic itself: no NAT rule is created on its gateway router
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						"ovn-nbctl --timeout=15 lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow",
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=101 external_ids:name=%s", egressIPExcludedDestinationPolicyName),
+						"ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router",
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=%s external_ids:name=%s nexthop=%s", podV4IP, types.EgressIPReroutePriority, eIP.Name, nodeMgmtPortIP),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"ip4.src == %s\" priority=%s nexthop=%s external_ids:name=%s options:pkt_mark=%d -- add logical_router %s policies @lr-policy", podV4IP, types.EgressIPReroutePriority, nodeMgmtPortIP, eIP.Name, egressIPMarkMin, types.OVNClusterRouter),
+					},
+				)
+
+				fakeOvn.controller.WatchEgressNodes()
+				fakeOvn.controller.WatchEgressIP()
+
+				gomega.Eventually(getEgressIPStatusLen(egressIPName)).Should(gomega.Equal(1))
+				statuses := getEgressIPStatus(egressIPName)
+				gomega.Expect(statuses[0]).To(gomega.Equal(egressipv1.EgressIPStatusItem{
+					EgressIP:  egressIP,
+					Node:      node.Name,
+					Interface: "eth1",
+				}))
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				updatedEIP, err := fakeOvn.fakeClient.EgressIPClient.K8sV1().EgressIPs().Get(context.TODO(), eIP.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(updatedEIP.Annotations).To(gomega.HaveKeyWithValue(types.EgressIPMarkAnnotation, strconv.Itoa(egressIPMarkMin)))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should not be able to allocate the IP of a secondary interface", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				node := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{})
+				_, eth1Subnet, _ := net.ParseCIDR("10.10.0.5/24")
+				node.egressInterfaces = []egressInterface{
+					{
+						name:     "eth1",
+						v4IP:     net.ParseIP("10.10.0.5"),
+						v4Subnet: eth1Subnet,
+					},
+				}
+				fakeOvn.controller.eIPC.allocator[node.name] = &node
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{"10.10.0.5"},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.HaveLen(0))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	})
+
+	ginkgo.Context("Rebalancing of EgressIPs", func() {
+
+		ginkgo.It("should move an egress IP to a less loaded node in a single transaction", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP1 := "192.168.126.101"
+				egressIP2 := "192.168.126.102"
+				node2LogicalRouterIPv4 := "100.64.0.3"
+
+				egressPod := *newPodWithLabels(namespace, podName, node1Name, podV4IP, egressPodLabel)
+				egressNamespace := newNamespace(namespace)
+
+				node1 := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{egressIP1, egressIP2})
+				node2 := setupNode(node2Name, []string{"192.168.126.51/24"}, []string{})
+
+				eIP1 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1},
+						PodSelector: metav1.LabelSelector{
+							MatchLabels: egressPodLabel,
+						},
+						NamespaceSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"name": egressNamespace.Name,
+							},
+						},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								EgressIP: egressIP1,
+								Node:     node1.name,
+							},
+						},
+					},
+				}
+				eIP2 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName + "2"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP2},
+						NamespaceSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"name": "does-not-exist",
+							},
+						},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								EgressIP: egressIP2,
+								Node:     node1.name,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP1, eIP2},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{*egressNamespace},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{egressPod},
+					})
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				// no move happens before the rebalance interval has elapsed
+				fakeOvn.controller.eIPC.lastRebalance = time.Now()
+				fakeOvn.controller.rebalanceEgressIPs()
+				gomega.Expect(getEgressIPStatus(eIP1.Name)[0].Node).To(gomega.Equal(node1.name))
+
+				// the reroute policy and NAT rule towards node1 are looked up
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --if-exist get logical_router_port rtoj-GR_%s networks", node1.name),
+						Output: nodeLogicalRouterIfAddrV4,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=%s external_ids:name=%s nexthop=%s", podV4IP, types.EgressIPReroutePriority, eIP1.Name, nodeLogicalRouterIPv4),
+						Output: reroutePolicyID,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP1.Name, podV4IP, egressIP1, node1.name),
+						Output: natID,
+					},
+				)
+				// and so are the ones towards node2
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --if-exist get logical_router_port rtoj-GR_%s networks", node2.name),
+						Output: node2LogicalRouterIPv4 + "/29",
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=%s external_ids:name=%s nexthop=%s", podV4IP, types.EgressIPReroutePriority, eIP1.Name, node2LogicalRouterIPv4),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP1.Name, podV4IP, egressIP1, node2.name),
+					},
+				)
+				// the ones towards node1 are replaced by the ones towards node2 in a single transaction
+				moveCmd := fmt.Sprintf("ovn-nbctl --timeout=15 -- remove logical_router %s policies %s -- remove logical_router GR_%s nat %s "+
+					"-- --id=@nat1 create nat type=snat logical_port=k8s-%s external_ip=%s logical_ip=%s external_ids:name=%s -- add logical_router GR_%s nat @nat1 "+
+					"-- --id=@lr-policy1 create logical_router_policy action=reroute match=\"ip4.src == %s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy1",
+					types.OVNClusterRouter, reroutePolicyID, node1.name, natID,
+					node2.name, egressIP1, podV4IP, eIP1.Name, node2.name,
+					podV4IP, types.EgressIPReroutePriority, node2LogicalRouterIPv4, eIP1.Name, types.OVNClusterRouter)
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						moveCmd,
+					},
+				)
+				// the pod handler which is re-created finds them
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=%s external_ids:name=%s nexthop=%s", podV4IP, types.EgressIPReroutePriority, eIP1.Name, node2LogicalRouterIPv4),
+						Output: reroutePolicyID,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP1.Name, podV4IP, egressIP1, node2.name),
+						Output: natID,
+					},
+				)
+
+				fakeOvn.controller.eIPC.lastRebalance = time.Time{}
+				fakeOvn.controller.rebalanceEgressIPs()
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				gomega.Expect(getEgressIPStatus(eIP1.Name)[0].Node).To(gomega.Equal(node2.name))
+				gomega.Expect(getEgressIPStatus(eIP2.Name)[0].Node).To(gomega.Equal(node1.name))
+				gomega.Expect(node1.allocations).To(gomega.Equal(map[string]bool{egressIP2: true}))
+				gomega.Expect(node2.allocations).To(gomega.Equal(map[string]bool{egressIP1: true}))
+
+				// the egress IPs are now evenly spread
+				fakeOvn.controller.eIPC.lastRebalance = time.Time{}
+				fakeOvn.controller.rebalanceEgressIPs()
+				gomega.Expect(getEgressIPStatus(eIP2.Name)[0].Node).To(gomega.Equal(node1.name))
+				gomega.Expect(fakeOvn.controller.eIPC.lastRebalance.IsZero()).To(gomega.BeTrue())
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should leave the egress IP on its node when it cannot be moved", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP1 := "192.168.126.101"
+				egressIP2 := "192.168.126.102"
+				node2LogicalRouterIPv4 := "100.64.0.3"
+
+				egressPod := *newPodWithLabels(namespace, podName, node1Name, podV4IP, egressPodLabel)
+				egressNamespace := newNamespace(namespace)
+
+				node1 := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{egressIP1, egressIP2})
+				node2 := setupNode(node2Name, []string{"192.168.126.51/24"}, []string{})
+
+				eIP1 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1},
+						PodSelector: metav1.LabelSelector{
+							MatchLabels: egressPodLabel,
+						},
+						NamespaceSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"name": egressNamespace.Name,
+							},
+						},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								EgressIP: egressIP1,
+								Node:     node1.name,
+							},
+						},
+					},
+				}
+				eIP2 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName + "2"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP2},
+						NamespaceSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"name": "does-not-exist",
+							},
+						},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								EgressIP: egressIP2,
+								Node:     node1.name,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP1, eIP2},
+					},
+					&v1.NamespaceList{
+						Items: []v1.Namespace{*egressNamespace},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{egressPod},
+					})
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				// the reroute policy and NAT rule towards node1 are looked up
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --if-exist get logical_router_port rtoj-GR_%s networks", node1.name),
+						Output: nodeLogicalRouterIfAddrV4,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=%s external_ids:name=%s nexthop=%s", podV4IP, types.EgressIPReroutePriority, eIP1.Name, nodeLogicalRouterIPv4),
+						Output: reroutePolicyID,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP1.Name, podV4IP, egressIP1, node1.name),
+						Output: natID,
+					},
+				)
+				// and so are the ones towards node2
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --if-exist get logical_router_port rtoj-GR_%s networks", node2.name),
+						Output: node2LogicalRouterIPv4 + "/29",
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=%s external_ids:name=%s nexthop=%s", podV4IP, types.EgressIPReroutePriority, eIP1.Name, node2LogicalRouterIPv4),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=%s external_ip=%s logical_port=k8s-%s", eIP1.Name, podV4IP, egressIP1, node2.name),
+					},
+				)
+				// the ones towards node1 are replaced by the ones towards node2 in a single transaction
+				moveCmd := fmt.Sprintf("ovn-nbctl --timeout=15 -- remove logical_router %s policies %s -- remove logical_router GR_%s nat %s "+
+					"-- --id=@nat1 create nat type=snat logical_port=k8s-%s external_ip=%s logical_ip=%s external_ids:name=%s -- add logical_router GR_%s nat @nat1 "+
+					"-- --id=@lr-policy1 create logical_router_policy action=reroute match=\"ip4.src == %s\" priority=%s nexthop=%s external_ids:name=%s -- add logical_router %s policies @lr-policy1",
+					types.OVNClusterRouter, reroutePolicyID, node1.name, natID,
+					node2.name, egressIP1, podV4IP, eIP1.Name, node2.name,
+					podV4IP, types.EgressIPReroutePriority, node2LogicalRouterIPv4, eIP1.Name, types.OVNClusterRouter)
+				// the transaction fails, so nothing was changed and nothing else is run
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd: moveCmd,
+						Err: fmt.Errorf("transaction error"),
+					},
+				)
+
+				fakeOvn.controller.rebalanceEgressIPs()
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				gomega.Expect(getEgressIPStatus(eIP1.Name)[0].Node).To(gomega.Equal(node1.name))
+				gomega.Expect(node1.allocations).To(gomega.Equal(map[string]bool{egressIP1: true, egressIP2: true}))
+				gomega.Expect(node2.allocations).To(gomega.BeEmpty())
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should not move an egress IP to a node without capacity", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP1 := "192.168.126.101"
+				egressIP2 := "192.168.126.102"
+
+				node1 := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{egressIP1, egressIP2})
+				node2 := setupNode(node2Name, []string{"192.168.126.51/24"}, []string{})
+				node2.capacity = 0
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1, egressIP2},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								EgressIP: egressIP1,
+								Node:     node1.name,
+							},
+							{
+								EgressIP: egressIP2,
+								Node:     node1.name,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP},
+					})
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				fakeOvn.controller.rebalanceEgressIPs()
+				gomega.Expect(getEgressIPStatus(eIP.Name)).To(gomega.Equal(eIP.Status.Items))
+				gomega.Expect(fakeOvn.controller.eIPC.lastRebalance.IsZero()).To(gomega.BeTrue())
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	})
+})
+
+var _ = ginkgo.Describe("OVN master EgressIP health check", func() {
+	var healthy bool
+
+	ginkgo.BeforeEach(func() {
+		config.PrepareTestConfig()
+		healthy = true
+	})
+
+	ginkgo.It("probes the health endpoint of the nodes", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gomega.Expect(r.URL.Path).To(gomega.Equal(types.EgressIPNodeHealthCheckPath))
+			if !healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort, err = strconv.Atoi(port)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		healthCheck, err := newEgressIPHealthCheck()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(healthCheck.dial(net.ParseIP(host))).To(gomega.BeTrue())
+		healthy = false
+		gomega.Expect(healthCheck.dial(net.ParseIP(host))).To(gomega.BeFalse())
+		server.Close()
+		gomega.Expect(healthCheck.dial(net.ParseIP(host))).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("probes the health endpoint of the nodes over TLS and updates their reachability", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !healthy {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort, err = strconv.Atoi(port)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		// a master without the CA cannot probe the node serving TLS
+		healthCheck, err := newEgressIPHealthCheck()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(healthCheck.dial(net.ParseIP(host))).To(gomega.BeFalse())
+
+		dir, err := ioutil.TempDir("", "egressip-health")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		defer os.RemoveAll(dir)
+		caCert := filepath.Join(dir, "ca.crt")
+		err = ioutil.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		config.OVNKubernetesFeature.EgressIPNodeHealthCheckCACert = caCert
+		healthCheck, err = newEgressIPHealthCheck()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		savedDialer := dialer
+		dialer = healthCheck
+		defer func() { dialer = savedDialer }()
+		oc := &Controller{}
+		eNode := &egressNode{name: "node1", v4IP: net.ParseIP(host)}
+		gomega.Expect(oc.isReachable(eNode)).To(gomega.BeTrue())
+		healthy = false
+		gomega.Expect(oc.isReachable(eNode)).To(gomega.BeFalse())
+	})
 })
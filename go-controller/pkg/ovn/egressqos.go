@@ -0,0 +1,358 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	egressQoSAppliedCorrectly = "EgressQoS Rules applied"
+	egressQoSAddError         = "EgressQoS Rules not correctly added"
+	egressQoSUpdateError      = "EgressQoS Rules not correctly updated"
+)
+
+type egressQoS struct {
+	name      string
+	namespace string
+	rules     []*egressQoSRule
+}
+
+type egressQoSRule struct {
+	priority    int
+	dscp        int
+	destination string
+	podSelector labels.Selector
+	// addrSet holds the IPs of the pods of the namespace matching podSelector
+	// and is kept up to date by podHandler
+	addrSet    addressset.AddressSet
+	podHandler *factory.Handler
+}
+
+func newEgressQoS(egressQoSPolicy *egressqosapi.EgressQoS) *egressQoS {
+	return &egressQoS{
+		name:      egressQoSPolicy.Name,
+		namespace: egressQoSPolicy.Namespace,
+	}
+}
+
+func newEgressQoSRule(raw egressqosapi.EgressQoSRule, priority int) (*egressQoSRule, error) {
+	if raw.DSCP < 0 || raw.DSCP > 63 {
+		return nil, fmt.Errorf("invalid dscp value %d, it must be between 0 and 63", raw.DSCP)
+	}
+	if raw.DstCIDR != "" {
+		if _, _, err := net.ParseCIDR(raw.DstCIDR); err != nil {
+			return nil, err
+		}
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&raw.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+	return &egressQoSRule{
+		priority:    priority,
+		dscp:        raw.DSCP,
+		destination: raw.DstCIDR,
+		podSelector: selector,
+	}, nil
+}
+
+func getEgressQoSAddrSetName(namespace string, priority int) string {
+	return fmt.Sprintf("egress-qos-pods-%s-%d", namespace, priority)
+}
+
+// getEgressQoSRuleMatch returns the match of the QoS row of the rule, the
+// traffic of the pods in the rule address set heading to its destination
+func getEgressQoSRuleMatch(rule *egressQoSRule) string {
+	ipv4ASHashName, ipv6ASHashName := rule.addrSet.GetASHashNames()
+	var matches []string
+	if config.IPv4Mode && (rule.destination == "" || !utilnet.IsIPv6CIDRString(rule.destination)) {
+		match := fmt.Sprintf("ip4.src == $%s", ipv4ASHashName)
+		if rule.destination != "" {
+			match += fmt.Sprintf(" && ip4.dst == %s", rule.destination)
+		}
+		matches = append(matches, match)
+	}
+	if config.IPv6Mode && (rule.destination == "" || utilnet.IsIPv6CIDRString(rule.destination)) {
+		match := fmt.Sprintf("ip6.src == $%s", ipv6ASHashName)
+		if rule.destination != "" {
+			match += fmt.Sprintf(" && ip6.dst == %s", rule.destination)
+		}
+		matches = append(matches, match)
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return "(" + strings.Join(matches, ") || (") + ")"
+}
+
+func (oc *Controller) addEgressQoS(egressQoS *egressqosapi.EgressQoS) error {
+	klog.Infof("Adding egressQoS %s in namespace %s", egressQoS.Name, egressQoS.Namespace)
+	oc.egressQoSMutex.Lock()
+	defer oc.egressQoSMutex.Unlock()
+
+	if _, ok := oc.egressQoSes[egressQoS.Namespace]; ok {
+		return fmt.Errorf("error attempting to add egressQoS %s to namespace %s when it already has an egressQoS",
+			egressQoS.Name, egressQoS.Namespace)
+	}
+	// remove the QoS rows left over by a previous run of the controller
+	if err := deleteEgressQoSRows(egressQoS.Namespace); err != nil {
+		return err
+	}
+
+	eq := newEgressQoS(egressQoS)
+	// track it right away so that deleting it cleans up the rules added before an error
+	oc.egressQoSes[egressQoS.Namespace] = eq
+	switches := oc.lsManager.GetSwitchNames()
+	for i, rawRule := range egressQoS.Spec.Egress {
+		rule, err := newEgressQoSRule(rawRule, types.EgressQoSRulePriority-i)
+		if err != nil {
+			return fmt.Errorf("cannot create EgressQoS rule to destination %s for namespace %s: %v",
+				rawRule.DstCIDR, egressQoS.Namespace, err)
+		}
+		rule.addrSet, err = oc.addressSetFactory.NewAddressSet(getEgressQoSAddrSetName(egressQoS.Namespace, rule.priority), nil)
+		if err != nil {
+			return fmt.Errorf("cannot create address set for EgressQoS rule %d of namespace %s: %v",
+				i, egressQoS.Namespace, err)
+		}
+		eq.rules = append(eq.rules, rule)
+		oc.handleEgressQoSRulePods(egressQoS.Namespace, rule)
+		// a QoS row not referenced by any logical switch is garbage collected,
+		// it is created with the logical switch of the first node instead
+		if len(switches) == 0 {
+			continue
+		}
+		if err := createEgressQoSRow(egressQoS.Namespace, rule, switches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oc *Controller) updateEgressQoS(oldEgressQoS, newEgressQoS *egressqosapi.EgressQoS) error {
+	oc.egressQoSMutex.Lock()
+	eq, ok := oc.egressQoSes[newEgressQoS.Namespace]
+	oc.egressQoSMutex.Unlock()
+	if ok && eq.name != newEgressQoS.Name {
+		// the egressQoS was rejected by addEgressQoS, leave the applied one alone
+		return fmt.Errorf("error attempting to update egressQoS %s in namespace %s which has egressQoS %s",
+			newEgressQoS.Name, newEgressQoS.Namespace, eq.name)
+	}
+	var errs []error
+	if err := oc.deleteEgressQoS(oldEgressQoS); err != nil {
+		errs = append(errs, err)
+	}
+	if err := oc.addEgressQoS(newEgressQoS); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (oc *Controller) deleteEgressQoS(egressQoS *egressqosapi.EgressQoS) error {
+	klog.Infof("Deleting egressQoS %s in namespace %s", egressQoS.Name, egressQoS.Namespace)
+	oc.egressQoSMutex.Lock()
+	defer oc.egressQoSMutex.Unlock()
+
+	var errs []error
+	if eq, ok := oc.egressQoSes[egressQoS.Namespace]; ok {
+		if eq.name != egressQoS.Name {
+			// the egressQoS was rejected by addEgressQoS, nothing was applied
+			return nil
+		}
+		delete(oc.egressQoSes, egressQoS.Namespace)
+		for _, rule := range eq.rules {
+			oc.watchFactory.RemovePodHandler(rule.podHandler)
+			if err := rule.addrSet.Destroy(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to destroy address set %s: %v",
+					rule.addrSet.GetName(), err))
+			}
+		}
+	}
+	if err := deleteEgressQoSRows(egressQoS.Namespace); err != nil {
+		errs = append(errs, err)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// handleEgressQoSRulePods keeps the address set of the rule up to date with
+// the IPs of the pods of the namespace it selects
+func (oc *Controller) handleEgressQoSRulePods(namespace string, rule *egressQoSRule) {
+	rule.podHandler = oc.watchFactory.AddFilteredPodHandler(namespace, rule.podSelector,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				oc.handleEgressQoSPodAddUpdate(rule, obj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				if !util.PodWantsNetwork(pod) || pod.Spec.NodeName == "" {
+					return
+				}
+				ips, err := util.GetAllPodIPs(pod)
+				if err != nil {
+					klog.Errorf(err.Error())
+					return
+				}
+				if err := rule.addrSet.DeleteIPs(ips); err != nil {
+					klog.Errorf(err.Error())
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oc.handleEgressQoSPodAddUpdate(rule, newObj)
+			},
+		}, nil)
+}
+
+func (oc *Controller) handleEgressQoSPodAddUpdate(rule *egressQoSRule, obj interface{}) {
+	pod := obj.(*kapi.Pod)
+	if !util.PodWantsNetwork(pod) || pod.Spec.NodeName == "" {
+		return
+	}
+	ips, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		// the pod is not set up yet, it is handled by its next update
+		klog.V(5).Infof("Pod %s/%s has no IPs yet: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	if err := rule.addrSet.AddIPs(ips); err != nil {
+		klog.Errorf(err.Error())
+	}
+}
+
+// createEgressQoSRow creates the QoS row of the rule and attaches it to the given
+// node logical switches
+func createEgressQoSRow(namespace string, rule *egressQoSRule, switches []string) error {
+	args := []string{"--id=@qos", "create", "qos",
+		"direction=" + fromLport,
+		fmt.Sprintf("match=\"%s\"", getEgressQoSRuleMatch(rule)),
+		fmt.Sprintf("priority=%d", rule.priority),
+		fmt.Sprintf("action:dscp=%d", rule.dscp),
+		fmt.Sprintf("external-ids:egressQoS=%s", namespace),
+	}
+	for _, sw := range switches {
+		args = append(args, "--", "add", "logical_switch", sw, "qos_rules", "@qos")
+	}
+	_, stderr, err := util.RunOVNNbctl(args...)
+	if err != nil {
+		return fmt.Errorf("failed to create QoS for EgressQoS rule with priority %d of namespace %s, "+
+			"stderr: %q, error: %v", rule.priority, namespace, stderr, err)
+	}
+	return nil
+}
+
+// deleteEgressQoSRows detaches the QoS rows created for the EgressQoS of the
+// namespace from the node logical switches, which deletes them
+func deleteEgressQoSRows(namespace string) error {
+	stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find", "qos",
+		fmt.Sprintf("external-ids:egressQoS=%s", namespace))
+	if err != nil {
+		return fmt.Errorf("error deleting egressQoS of namespace %s, cannot get QoS rows, stderr: %q, error: %v",
+			namespace, stderr, err)
+	}
+
+	var errs []error
+	for _, uuid := range strings.Fields(stdout) {
+		stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find",
+			"logical_switch", fmt.Sprintf("qos_rules{>=}%s", uuid))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot get the logical switches of QoS %s, "+
+				"stderr: %q (%v)", uuid, stderr, err))
+			continue
+		}
+		var args []string
+		for _, sw := range strings.Fields(stdout) {
+			args = append(args, "--", "remove", "logical_switch", sw, "qos_rules", uuid)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		_, stderr, err = util.RunOVNNbctl(args[1:]...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove QoS %s of egressQoS of namespace %s "+
+				"from the logical switches, stderr: %q (%v)", uuid, namespace, stderr, err))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// findEgressQoSRow returns the uuid of the QoS row of the rule, or an empty string
+// if there is none: OVN garbage collects it once no logical switch references it
+func findEgressQoSRow(namespace string, rule *egressQoSRule) (string, error) {
+	uuid, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "find", "qos",
+		fmt.Sprintf("external-ids:egressQoS=%s", namespace), fmt.Sprintf("priority=%d", rule.priority))
+	if err != nil {
+		return "", fmt.Errorf("cannot find the QoS of EgressQoS rule with priority %d of namespace %s, "+
+			"stderr: %q, error: %v", rule.priority, namespace, stderr, err)
+	}
+	return uuid, nil
+}
+
+// addEgressQoSToNodeSwitch attaches the QoS rows of all the EgressQoSes to the
+// logical switch of a new node
+func (oc *Controller) addEgressQoSToNodeSwitch(nodeName string) error {
+	oc.egressQoSMutex.Lock()
+	defer oc.egressQoSMutex.Unlock()
+
+	var uuids []string
+	for _, eq := range oc.egressQoSes {
+		for _, rule := range eq.rules {
+			uuid, err := findEgressQoSRow(eq.namespace, rule)
+			if err != nil {
+				return err
+			}
+			if uuid != "" {
+				uuids = append(uuids, uuid)
+				continue
+			}
+			if err := createEgressQoSRow(eq.namespace, rule, []string{nodeName}); err != nil {
+				return err
+			}
+		}
+	}
+	if len(uuids) == 0 {
+		return nil
+	}
+	args := append([]string{"add", "logical_switch", nodeName, "qos_rules"}, uuids...)
+	_, stderr, err := util.RunOVNNbctl(args...)
+	if err != nil {
+		return fmt.Errorf("failed to add the EgressQoS QoS rows to logical switch %s, stderr: %q, error: %v",
+			nodeName, stderr, err)
+	}
+	return nil
+}
+
+func (oc *Controller) updateEgressQoSWithRetry(egressQoS *egressqosapi.EgressQoS) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return oc.kube.UpdateEgressQoS(egressQoS)
+	})
+	if retryErr != nil {
+		return fmt.Errorf("error in updating status on EgressQoS %s/%s: %v",
+			egressQoS.Namespace, egressQoS.Name, retryErr)
+	}
+	return nil
+}
+
+// setEgressQoSStatus fills in the status of the egressQoS from the result of
+// adding it, err. errStatus is the summary used on failure.
+func setEgressQoSStatus(egressQoS *egressqosapi.EgressQoS, err error, errStatus string) {
+	if err != nil {
+		egressQoS.Status.Status = errStatus
+	} else {
+		egressQoS.Status.Status = egressQoSAppliedCorrectly
+	}
+}
@@ -0,0 +1,356 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/urfave/cli/v2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEgressQoSObject(name, namespace string, egressRules []egressqosapi.EgressQoSRule) *egressqosapi.EgressQoS {
+	return &egressqosapi.EgressQoS{
+		ObjectMeta: newObjectMeta(name, namespace),
+		Spec: egressqosapi.EgressQoSSpec{
+			Egress: egressRules,
+		},
+	}
+}
+
+var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
+	const (
+		node1Name  string = "node1"
+		node2Name  string = "node2"
+		qosUUID1   string = "6e5d6bb6-3a8a-4f47-9cdb-e73f0bd4a04f"
+		qosUUID2   string = "3d4bc3b5-0cd0-4cfb-9a4c-1ed63ca5d3b0"
+		switchUUID string = "ab4a8e1f-2ecb-4b68-9f48-1a9b9c4b8a1d"
+	)
+	var (
+		app     *cli.App
+		fakeOVN *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOVN = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	ginkgo.It("marks the egress traffic of the selected pods on the node logical switches", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespace1 := *newNamespace("namespace1")
+			voicePod := *newPodWithLabels(namespace1.Name, "voice", node1Name, "10.128.1.3", map[string]string{"app": "voice"})
+			otherPod := *newPod(namespace1.Name, "other", node1Name, "10.128.1.4")
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP:    46,
+					DstCIDR: "1.2.3.0/24",
+					PodSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "voice"},
+					},
+				},
+				{
+					DSCP: 10,
+				},
+			})
+
+			v4Rule0AS, _ := addressset.MakeAddressSetHashNames("egress-qos-pods-namespace1-1000")
+			v4Rule1AS, _ := addressset.MakeAddressSetHashNames("egress-qos-pods-namespace1-999")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1",
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd: fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos create qos direction=from-lport match=\"ip4.src == $%s && ip4.dst == 1.2.3.0/24\" "+
+					"priority=1000 action:dscp=46 external-ids:egressQoS=namespace1 -- add logical_switch node1 qos_rules @qos", v4Rule0AS),
+				Output: qosUUID1,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd: fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos create qos direction=from-lport match=\"ip4.src == $%s\" "+
+					"priority=999 action:dscp=10 external-ids:egressQoS=namespace1 -- add logical_switch node1 qos_rules @qos", v4Rule1AS),
+				Output: qosUUID2,
+			})
+
+			fakeOVN.start(ctx,
+				&egressqosapi.EgressQoSList{
+					Items: []egressqosapi.EgressQoS{*egressQoS},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{namespace1},
+				},
+				&v1.PodList{
+					Items: []v1.Pod{voicePod, otherPod},
+				})
+			err := fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			fakeOVN.controller.WatchEgressQoS()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.EventuallyExpectAddressSetWithIPs("egress-qos-pods-namespace1-1000", []string{"10.128.1.3"})
+			fakeOVN.asf.EventuallyExpectAddressSetWithIPs("egress-qos-pods-namespace1-999", []string{"10.128.1.3", "10.128.1.4"})
+			gomega.Eventually(func() string {
+				eq, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), egressQoS.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return eq.Status.Status
+			}).Should(gomega.Equal(egressQoSAppliedCorrectly))
+
+			// the logical switch of a new node gets the existing QoS rows
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1 priority=1000",
+				Output: qosUUID1,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1 priority=999",
+				Output: qosUUID2,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 add logical_switch node2 qos_rules " + qosUUID1 + " " + qosUUID2,
+			})
+			err = fakeOVN.controller.addEgressQoSToNodeSwitch(node2Name)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+
+			// the QoS rows garbage collected by OVN after the logical switches of all the
+			// nodes were deleted are re-created with the logical switch of a new node
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1 priority=1000",
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1 priority=999",
+				fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos create qos direction=from-lport match=\"ip4.src == $%s && ip4.dst == 1.2.3.0/24\" "+
+					"priority=1000 action:dscp=46 external-ids:egressQoS=namespace1 -- add logical_switch node3 qos_rules @qos", v4Rule0AS),
+				fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos create qos direction=from-lport match=\"ip4.src == $%s\" "+
+					"priority=999 action:dscp=10 external-ids:egressQoS=namespace1 -- add logical_switch node3 qos_rules @qos", v4Rule1AS),
+			})
+			err = fakeOVN.controller.addEgressQoSToNodeSwitch("node3")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("creates the QoS rows with the logical switch of the first node", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespace1 := *newNamespace("namespace1")
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP:    46,
+					DstCIDR: "1.2.3.0/24",
+				},
+			})
+
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1",
+			})
+			fakeOVN.start(ctx,
+				&egressqosapi.EgressQoSList{
+					Items: []egressqosapi.EgressQoS{*egressQoS},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{namespace1},
+				})
+			fakeOVN.controller.WatchEgressQoS()
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			v4AS, _ := addressset.MakeAddressSetHashNames("egress-qos-pods-namespace1-1000")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1 priority=1000",
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd: fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos create qos direction=from-lport match=\"ip4.src == $%s && ip4.dst == 1.2.3.0/24\" "+
+					"priority=1000 action:dscp=46 external-ids:egressQoS=namespace1 -- add logical_switch node1 qos_rules @qos", v4AS),
+				Output: qosUUID1,
+			})
+			err := fakeOVN.controller.addEgressQoSToNodeSwitch(node1Name)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+
+			// the row now exists and is only attached to the following nodes
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1 priority=1000",
+				Output: qosUUID1,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 add logical_switch node2 qos_rules " + qosUUID1,
+			})
+			err = fakeOVN.controller.addEgressQoSToNodeSwitch(node2Name)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("removes the QoS rows and the address sets of a deleted egressQoS", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespace1 := *newNamespace("namespace1")
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP: 10,
+				},
+			})
+
+			v4AS, _ := addressset.MakeAddressSetHashNames("egress-qos-pods-namespace1-1000")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1",
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd: fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos create qos direction=from-lport match=\"ip4.src == $%s\" "+
+					"priority=1000 action:dscp=10 external-ids:egressQoS=namespace1 -- add logical_switch node1 qos_rules @qos", v4AS),
+				Output: qosUUID1,
+			})
+			fakeOVN.start(ctx,
+				&egressqosapi.EgressQoSList{
+					Items: []egressqosapi.EgressQoS{*egressQoS},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{namespace1},
+				})
+			err := fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			fakeOVN.controller.WatchEgressQoS()
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.ExpectEmptyAddressSet("egress-qos-pods-namespace1-1000")
+
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1",
+				Output: qosUUID1,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find logical_switch qos_rules{>=}" + qosUUID1,
+				Output: switchUUID,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 remove logical_switch " + switchUUID + " qos_rules " + qosUUID1,
+			})
+			err = fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Delete(context.TODO(), egressQoS.Name, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.EventuallyExpectNoAddressSet("egress-qos-pods-namespace1-1000")
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("leaves the applied egressQoS alone when a second egressQoS of the namespace is updated or deleted", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespace1 := *newNamespace("namespace1")
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP: 10,
+				},
+			})
+
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1",
+			})
+			fakeOVN.start(ctx,
+				&egressqosapi.EgressQoSList{
+					Items: []egressqosapi.EgressQoS{*egressQoS},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{namespace1},
+				})
+			fakeOVN.controller.WatchEgressQoS()
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.ExpectEmptyAddressSet("egress-qos-pods-namespace1-1000")
+
+			egressQoS2 := newEgressQoSObject("other", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP: 46,
+				},
+			})
+			egressQoS2, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Create(context.TODO(), egressQoS2, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(func() string {
+				eq, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), egressQoS2.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return eq.Status.Status
+			}).Should(gomega.Equal(egressQoSAddError))
+
+			egressQoS2.Spec.Egress[0].DSCP = 47
+			_, err = fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Update(context.TODO(), egressQoS2, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(func() int {
+				eq, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), egressQoS2.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return eq.Spec.Egress[0].DSCP
+			}).Should(gomega.Equal(47))
+			err = fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Delete(context.TODO(), egressQoS2.Name, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// no QoS row nor address set of the applied egressQoS is removed
+			gomega.Consistently(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.ExpectEmptyAddressSet("egress-qos-pods-namespace1-1000")
+			fakeOVN.controller.egressQoSMutex.Lock()
+			defer fakeOVN.controller.egressQoSMutex.Unlock()
+			gomega.Expect(fakeOVN.controller.egressQoSes[namespace1.Name].name).To(gomega.Equal(egressQoS.Name))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("reports the egressQoS as not applied when a rule is invalid", func() {
+		app.Action = func(ctx *cli.Context) error {
+			namespace1 := *newNamespace("namespace1")
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP:    46,
+					DstCIDR: "1.2.3.4",
+				},
+			})
+
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find qos external-ids:egressQoS=namespace1",
+			})
+			fakeOVN.start(ctx,
+				&egressqosapi.EgressQoSList{
+					Items: []egressqosapi.EgressQoS{*egressQoS},
+				},
+				&v1.NamespaceList{
+					Items: []v1.Namespace{namespace1},
+				})
+			fakeOVN.controller.WatchEgressQoS()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(func() string {
+				eq, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), egressQoS.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return eq.Status.Status
+			}).Should(gomega.Equal(egressQoSAddError))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
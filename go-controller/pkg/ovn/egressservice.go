@@ -0,0 +1,497 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+// egressService is the state programmed for an egress service: the traffic of
+// its endpoints is rerouted to the gateway router of the host node, which SNATs
+// it to the ingress IP of the service of the same IP family
+type egressService struct {
+	host string
+	// first ingress IP of each IP family of the service
+	ingressIPs []string
+	// IPs of the endpoints whose traffic is rerouted and SNATed
+	endpoints sets.String
+}
+
+func getEgressServiceKey(namespace, name string) string {
+	return fmt.Sprintf("%s_%s", namespace, name)
+}
+
+// getEgressServiceIngressIPs returns the first ingress IP of each IP family of the service
+func getEgressServiceIngressIPs(svc *kapi.Service) []string {
+	var v4IP, v6IP string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		ip := net.ParseIP(ingress.IP)
+		if ip == nil {
+			continue
+		}
+		if utilnet.IsIPv6(ip) && v6IP == "" {
+			v6IP = ip.String()
+		} else if !utilnet.IsIPv6(ip) && v4IP == "" {
+			v4IP = ip.String()
+		}
+	}
+	var ips []string
+	for _, ip := range []string{v4IP, v6IP} {
+		if ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// getEgressServiceEndpoints returns the IPs of the ready endpoints of the service
+// which are pods on the cluster network
+func getEgressServiceEndpoints(ep *kapi.Endpoints) sets.String {
+	ips := sets.NewString()
+	if ep == nil {
+		return ips
+	}
+	for _, subset := range ep.Subsets {
+		for _, address := range subset.Addresses {
+			ip := net.ParseIP(address.IP)
+			if ip == nil {
+				continue
+			}
+			for _, clusterSubnet := range config.Default.ClusterSubnets {
+				if clusterSubnet.CIDR.Contains(ip) {
+					ips.Insert(ip.String())
+					break
+				}
+			}
+		}
+	}
+	return ips
+}
+
+// selectEgressServiceHost returns the node hosting the egress service: its current
+// host while it can, otherwise the ready node matching the node selector of the
+// service which hosts the fewest egress services
+func (oc *Controller) selectEgressServiceHost(key string, cfg *util.EgressServiceConfig, current string) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&cfg.NodeSelector)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := oc.watchFactory.GetNodes()
+	if err != nil {
+		return "", err
+	}
+	var candidates []string
+	for _, node := range nodes {
+		if noHostSubnet(node) || !oc.isEgressNodeReady(node) || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if node.Name == current {
+			return current, nil
+		}
+		candidates = append(candidates, node.Name)
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	hosted := make(map[string]int)
+	for svcKey, es := range oc.egressServices {
+		if svcKey != key && es.host != "" {
+			hosted[es.host]++
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if hosted[candidates[i]] != hosted[candidates[j]] {
+			return hosted[candidates[i]] < hosted[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates[0], nil
+}
+
+// syncEgressService programs the egress service with the given name from the
+// current state of the service, its endpoints and the nodes, moving it to
+// another host node when its host can no longer host it
+func (oc *Controller) syncEgressService(namespace, name string) error {
+	oc.egressServicesMutex.Lock()
+	defer oc.egressServicesMutex.Unlock()
+
+	key := getEgressServiceKey(namespace, name)
+	svc, err := oc.watchFactory.GetService(namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	es := oc.egressServices[key]
+
+	var host string
+	var ingressIPs []string
+	endpoints := sets.NewString()
+	if svc != nil && svc.Spec.Type == kapi.ServiceTypeLoadBalancer && util.HasEgressServiceAnnotation(svc) {
+		cfg, err := util.ParseEgressServiceAnnotation(svc)
+		if err != nil {
+			return err
+		}
+		current := ""
+		if es != nil {
+			current = es.host
+		}
+		host, err = oc.selectEgressServiceHost(key, cfg, current)
+		if err != nil {
+			return err
+		}
+		ingressIPs = getEgressServiceIngressIPs(svc)
+		ep, err := oc.watchFactory.GetEndpoint(namespace, name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		endpoints = getEgressServiceEndpoints(ep)
+	}
+
+	if es != nil && (es.host != host || !sets.NewString(es.ingressIPs...).Equal(sets.NewString(ingressIPs...))) {
+		if es.host != host {
+			klog.Infof("Moving egress service %s/%s from node %q to node %q", namespace, name, es.host, host)
+		}
+		if err := deleteEgressServiceRules(key, es, es.endpoints.UnsortedList()); err != nil {
+			return err
+		}
+		delete(oc.egressServices, key)
+		es = nil
+	}
+	if host != "" && len(ingressIPs) > 0 {
+		if es == nil {
+			es = &egressService{
+				host:       host,
+				ingressIPs: ingressIPs,
+				endpoints:  sets.NewString(),
+			}
+			oc.egressServices[key] = es
+		}
+		var errs []error
+		if err := deleteEgressServiceRules(key, es, es.endpoints.Difference(endpoints).UnsortedList()); err != nil {
+			errs = append(errs, err)
+		}
+		if err := oc.createEgressServiceRules(key, es, endpoints.Difference(es.endpoints).UnsortedList()); err != nil {
+			errs = append(errs, err)
+		}
+		if len(errs) > 0 {
+			return kerrors.NewAggregate(errs)
+		}
+	} else if es != nil {
+		if err := deleteEgressServiceRules(key, es, es.endpoints.UnsortedList()); err != nil {
+			return err
+		}
+		delete(oc.egressServices, key)
+	}
+
+	if svc == nil || svc.Annotations[util.EgressServiceHostAnnotation] == host {
+		return nil
+	}
+	var hostAnnotation interface{}
+	if host != "" {
+		hostAnnotation = host
+	}
+	return oc.kube.SetAnnotationsOnService(namespace, name, map[string]interface{}{
+		util.EgressServiceHostAnnotation: hostAnnotation,
+	})
+}
+
+// syncEgressServices syncs all the egress services, e.g. when the nodes which
+// can host them change
+func (oc *Controller) syncEgressServices() {
+	services, err := oc.watchFactory.GetServices()
+	if err != nil {
+		klog.Errorf("Unable to list the services to sync the egress services: %v", err)
+		return
+	}
+	keys := sets.NewString()
+	for _, svc := range services {
+		if util.HasEgressServiceAnnotation(svc) {
+			keys.Insert(getEgressServiceKey(svc.Namespace, svc.Name))
+			if err := oc.syncEgressService(svc.Namespace, svc.Name); err != nil {
+				klog.Errorf("Unable to sync egress service %s/%s: %v", svc.Namespace, svc.Name, err)
+			}
+		}
+	}
+	// services deleted while their deletion could not be processed
+	oc.egressServicesMutex.Lock()
+	var stale []string
+	for key := range oc.egressServices {
+		if !keys.Has(key) {
+			stale = append(stale, key)
+		}
+	}
+	oc.egressServicesMutex.Unlock()
+	for _, key := range stale {
+		parts := strings.SplitN(key, "_", 2)
+		if err := oc.syncEgressService(parts[0], parts[1]); err != nil {
+			klog.Errorf("Unable to sync egress service %s/%s: %v", parts[0], parts[1], err)
+		}
+	}
+}
+
+// isEgressService returns true if the service is an egress service, or was one
+// whose rules are not cleaned up yet
+func (oc *Controller) isEgressService(namespace, name string) bool {
+	oc.egressServicesMutex.Lock()
+	_, exists := oc.egressServices[getEgressServiceKey(namespace, name)]
+	oc.egressServicesMutex.Unlock()
+	if exists {
+		return true
+	}
+	svc, err := oc.watchFactory.GetService(namespace, name)
+	return err == nil && util.HasEgressServiceAnnotation(svc)
+}
+
+// syncEgressServiceRules removes the logical router policies and NAT rules of the
+// egress services deleted, or no longer egress services, while ovnkube-master was down
+func (oc *Controller) syncEgressServiceRules(services []interface{}) {
+	expected := sets.NewString()
+	for _, obj := range services {
+		svc, ok := obj.(*kapi.Service)
+		if !ok {
+			klog.Errorf("Spurious object in syncEgressServiceRules: %v", obj)
+			continue
+		}
+		if util.HasEgressServiceAnnotation(svc) {
+			expected.Insert(getEgressServiceKey(svc.Namespace, svc.Name))
+		}
+	}
+
+	policyIDs, err := findStaleEgressServiceRows("logical_router_policy", expected,
+		fmt.Sprintf("priority=%v", types.EgressSVCReroutePriority))
+	if err != nil {
+		klog.Errorf("Unable to sync the logical router policies of the egress services: %v", err)
+	} else if len(policyIDs) > 0 {
+		args := append([]string{"remove", "logical_router", types.OVNClusterRouter, "policies"}, policyIDs...)
+		if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+			klog.Errorf("Unable to remove the stale logical router policies of the egress services, stderr: %s, err: %v",
+				stderr, err)
+		}
+	}
+
+	natIDs, err := findStaleEgressServiceRows("nat", expected)
+	if err != nil {
+		klog.Errorf("Unable to sync the nat rules of the egress services: %v", err)
+		return
+	}
+	for _, natID := range natIDs {
+		routers, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=name", "find",
+			"logical_router", fmt.Sprintf("nat{>=}%s", natID))
+		if err != nil {
+			klog.Errorf("Unable to find the gateway router of the stale egress service nat rule %s, stderr: %s, err: %v",
+				natID, stderr, err)
+			continue
+		}
+		for _, router := range strings.Fields(routers) {
+			if _, stderr, err := util.RunOVNNbctl("remove", "logical_router", router, "nat", natID); err != nil {
+				klog.Errorf("Unable to remove the stale egress service nat rule %s, stderr: %s, err: %v",
+					natID, stderr, err)
+			}
+		}
+	}
+}
+
+// findStaleEgressServiceRows returns the rows of the table tagged with the
+// egress-service external ID of a service which is not an expected egress service
+func findStaleEgressServiceRows(table string, expected sets.String, conditions ...string) ([]string, error) {
+	args := append([]string{"--format=csv", "--data=bare", "--no-heading", "--columns=_uuid,external_ids",
+		"find", table}, conditions...)
+	output, stderr, err := util.RunOVNNbctl(args...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the %s rows, stderr: %s, err: %v", table, stderr, err)
+	}
+	var stale []string
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, ",")
+		if len(parts) != 2 {
+			continue
+		}
+		for _, externalID := range strings.Fields(parts[1]) {
+			externalID = strings.Trim(externalID, "\"")
+			if strings.HasPrefix(externalID, "egress-service=") &&
+				!expected.Has(strings.TrimPrefix(externalID, "egress-service=")) {
+				stale = append(stale, parts[0])
+			}
+		}
+	}
+	return stale, nil
+}
+
+// getEgressServiceIngressIP returns the ingress IP of the egress service the
+// traffic of the endpoint is SNATed to, if any
+func getEgressServiceIngressIP(es *egressService, endpoint string) string {
+	isIPv6 := utilnet.IsIPv6String(endpoint)
+	for _, ip := range es.ingressIPs {
+		if utilnet.IsIPv6String(ip) == isIPv6 {
+			return ip
+		}
+	}
+	return ""
+}
+
+// createEgressServiceRules reroutes the traffic of the endpoints to the gateway
+// router of the host node and SNATs it there to the ingress IP of the service,
+// adding the endpoints programmed successfully to the egress service
+func (oc *Controller) createEgressServiceRules(key string, es *egressService, endpoints []string) error {
+	for _, endpoint := range endpoints {
+		ingressIP := getEgressServiceIngressIP(es, endpoint)
+		if ingressIP == "" {
+			continue
+		}
+		isIPv6 := utilnet.IsIPv6String(endpoint)
+		gatewayRouterIP, err := oc.eIPC.getGatewayRouterJoinIP(es.host, isIPv6)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve the gateway router IP of node %s for egress service %s: %v",
+				es.host, key, err)
+		}
+		match := getEgressServiceMatch(endpoint)
+		policyIDs, err := findEgressServicePolicyIDs(key, match)
+		if err != nil {
+			return err
+		}
+		if policyIDs == nil {
+			_, stderr, err := util.RunOVNNbctl(
+				"--id=@lr-policy",
+				"create",
+				"logical_router_policy",
+				"action=reroute",
+				fmt.Sprintf("match=\"%s\"", match),
+				fmt.Sprintf("priority=%v", types.EgressSVCReroutePriority),
+				fmt.Sprintf("nexthop=%s", gatewayRouterIP),
+				fmt.Sprintf("external_ids:egress-service=%s", key),
+				"--",
+				"add",
+				"logical_router",
+				types.OVNClusterRouter,
+				"policies",
+				"@lr-policy",
+			)
+			if err != nil {
+				return fmt.Errorf("unable to create logical router policy for egress service %s endpoint %s, stderr: %s, err: %v",
+					key, endpoint, stderr, err)
+			}
+		}
+		natIDs, err := findEgressServiceNatIDs(key, endpoint)
+		if err != nil {
+			return err
+		}
+		if natIDs == nil {
+			_, stderr, err := util.RunOVNNbctl(
+				"--id=@nat",
+				"create",
+				"nat",
+				"type=snat",
+				fmt.Sprintf("external_ip=%s", ingressIP),
+				fmt.Sprintf("logical_ip=%s", endpoint),
+				fmt.Sprintf("external_ids:egress-service=%s", key),
+				"--",
+				"add",
+				"logical_router",
+				types.GWRouterPrefix+es.host,
+				"nat",
+				"@nat",
+			)
+			if err != nil {
+				return fmt.Errorf("unable to create nat rule for egress service %s endpoint %s, stderr: %s, err: %v",
+					key, endpoint, stderr, err)
+			}
+		}
+		es.endpoints.Insert(endpoint)
+	}
+	return nil
+}
+
+// deleteEgressServiceRules removes the rerouting and SNAT of the traffic of the
+// endpoints, removing the endpoints cleaned up successfully from the egress service
+func deleteEgressServiceRules(key string, es *egressService, endpoints []string) error {
+	for _, endpoint := range endpoints {
+		policyIDs, err := findEgressServicePolicyIDs(key, getEgressServiceMatch(endpoint))
+		if err != nil {
+			return err
+		}
+		for _, policyID := range policyIDs {
+			_, stderr, err := util.RunOVNNbctl("remove", "logical_router", types.OVNClusterRouter, "policies", policyID)
+			if err != nil {
+				return fmt.Errorf("unable to remove logical router policy of egress service %s endpoint %s, stderr: %s, err: %v",
+					key, endpoint, stderr, err)
+			}
+		}
+		// the NAT rules are gone with the gateway router of a deleted node
+		natIDs, err := findEgressServiceNatIDs(key, endpoint)
+		if err != nil {
+			return err
+		}
+		for _, natID := range natIDs {
+			_, stderr, err := util.RunOVNNbctl("remove", "logical_router", types.GWRouterPrefix+es.host, "nat", natID)
+			if err != nil {
+				return fmt.Errorf("unable to remove nat rule of egress service %s endpoint %s, stderr: %s, err: %v",
+					key, endpoint, stderr, err)
+			}
+		}
+		es.endpoints.Delete(endpoint)
+	}
+	return nil
+}
+
+func getEgressServiceMatch(endpoint string) string {
+	if utilnet.IsIPv6String(endpoint) {
+		return fmt.Sprintf("ip6.src == %s", endpoint)
+	}
+	return fmt.Sprintf("ip4.src == %s", endpoint)
+}
+
+func findEgressServicePolicyIDs(key, match string) ([]string, error) {
+	policyIDs, stderr, err := util.RunOVNNbctl(
+		"--format=csv",
+		"--data=bare",
+		"--no-heading",
+		"--columns=_uuid",
+		"find",
+		"logical_router_policy",
+		fmt.Sprintf("match=\"%s\"", match),
+		fmt.Sprintf("priority=%v", types.EgressSVCReroutePriority),
+		fmt.Sprintf("external_ids:egress-service=%s", key),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find logical router policy for egress service %s, stderr: %s, err: %v",
+			key, stderr, err)
+	}
+	if policyIDs == "" {
+		return nil, nil
+	}
+	return strings.Split(policyIDs, "\n"), nil
+}
+
+func findEgressServiceNatIDs(key, endpoint string) ([]string, error) {
+	natIDs, stderr, err := util.RunOVNNbctl(
+		"--format=csv",
+		"--data=bare",
+		"--no-heading",
+		"--columns=_uuid",
+		"find",
+		"nat",
+		fmt.Sprintf("external_ids:egress-service=%s", key),
+		fmt.Sprintf("logical_ip=%s", endpoint),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find nat ID for egress service %s, stderr: %s, err: %v", key, stderr, err)
+	}
+	if natIDs == "" {
+		return nil, nil
+	}
+	return strings.Split(natIDs, "\n"), nil
+}
@@ -0,0 +1,288 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+	"github.com/urfave/cli/v2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("OVN Egress Service Operations", func() {
+	const (
+		node1Name      string = "node1"
+		node2Name      string = "node2"
+		svcName        string = "voice"
+		svcNamespace   string = "namespace1"
+		svcKey         string = "namespace1_voice"
+		ingressIP      string = "5.5.5.5"
+		endpointIP     string = "10.128.1.3"
+		hostNetworkIP  string = "192.168.126.10"
+		policyUUID     string = "2f2c4d79-b4ab-4b6f-8e0a-3f9d6c4ab1a0"
+		natUUID        string = "9a7e5d4c-7f4c-4a3c-9bbf-6f8b8a7c4d21"
+		node2RouterIP  string = "100.64.0.3"
+		egressNodeName string = "egress"
+	)
+	var (
+		app     *cli.App
+		fakeOVN *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	newEgressServiceNode := func(name string, ready v1.ConditionStatus) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{egressNodeName: ""},
+			},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{
+					{
+						Type:   v1.NodeReady,
+						Status: ready,
+					},
+				},
+			},
+		}
+	}
+
+	getServiceHost := func() string {
+		svc, err := fakeOVN.fakeClient.KubeClient.CoreV1().Services(svcNamespace).Get(context.TODO(), svcName, metav1.GetOptions{})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return svc.Annotations[util.EgressServiceHostAnnotation]
+	}
+
+	addCreateCmds := func(nodeName, routerIP string) {
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --if-exist get logical_router_port rtoj-GR_%s networks", nodeName),
+			Output: routerIP + "/29",
+		})
+		fExec.AddFakeCmdsNoOutputNoError([]string{
+			fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=99 external_ids:egress-service=%s", endpointIP, svcKey),
+			fmt.Sprintf("ovn-nbctl --timeout=15 --id=@lr-policy create logical_router_policy action=reroute match=\"ip4.src == %s\" priority=99 nexthop=%s external_ids:egress-service=%s -- add logical_router ovn_cluster_router policies @lr-policy", endpointIP, routerIP, svcKey),
+			fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:egress-service=%s logical_ip=%s", svcKey, endpointIP),
+			fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat external_ip=%s logical_ip=%s external_ids:egress-service=%s -- add logical_router GR_%s nat @nat", ingressIP, endpointIP, svcKey, nodeName),
+		})
+	}
+
+	// the rules of the egress services deleted while ovnkube-master was down are
+	// removed on startup
+	addSyncCmds := func(policies, nats string) {
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids find logical_router_policy priority=99",
+			Output: policies,
+		})
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids find nat",
+			Output: nats,
+		})
+	}
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOVN = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	ginkgo.It("SNATs the endpoints traffic to the ingress IP on the host node and fails over when it is not ready", func() {
+		app.Action = func(ctx *cli.Context) error {
+			node1 := newEgressServiceNode(node1Name, v1.ConditionTrue)
+			node2 := newEgressServiceNode(node2Name, v1.ConditionTrue)
+			service := v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      svcName,
+					Namespace: svcNamespace,
+					Annotations: map[string]string{
+						util.EgressServiceAnnotation: fmt.Sprintf("{\"nodeSelector\": {\"matchLabels\": {\"%s\": \"\"}}}", egressNodeName),
+					},
+				},
+				Spec: v1.ServiceSpec{
+					Type: v1.ServiceTypeLoadBalancer,
+				},
+				Status: v1.ServiceStatus{
+					LoadBalancer: v1.LoadBalancerStatus{
+						Ingress: []v1.LoadBalancerIngress{{IP: ingressIP}},
+					},
+				},
+			}
+			endpoints := v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      svcName,
+					Namespace: svcNamespace,
+				},
+				Subsets: []v1.EndpointSubset{
+					{
+						Addresses: []v1.EndpointAddress{
+							{IP: endpointIP},
+							// host network endpoints do not egress through the gateway routers
+							{IP: hostNetworkIP},
+						},
+					},
+				},
+			}
+
+			addSyncCmds("", "")
+			addCreateCmds(node1Name, nodeLogicalRouterIPv4)
+			fakeOVN.start(ctx,
+				&v1.NodeList{Items: []v1.Node{node1, node2}},
+				&v1.ServiceList{Items: []v1.Service{service}},
+				&v1.EndpointsList{Items: []v1.Endpoints{endpoints}},
+			)
+			fakeOVN.controller.WatchEgressServices()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getServiceHost).Should(gomega.Equal(node1Name))
+
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"ip4.src == %s\" priority=99 external_ids:egress-service=%s", endpointIP, svcKey),
+				Output: policyUUID,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:egress-service=%s logical_ip=%s", svcKey, endpointIP),
+				Output: natUUID,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 remove logical_router ovn_cluster_router policies " + policyUUID,
+				fmt.Sprintf("ovn-nbctl --timeout=15 remove logical_router GR_%s nat %s", node1Name, natUUID),
+			})
+			addCreateCmds(node2Name, node2RouterIP)
+
+			node1.Status.Conditions[0].Status = v1.ConditionFalse
+			_, err := fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node1, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getServiceHost).Should(gomega.Equal(node2Name))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("does not host the service when no node matches its node selector", func() {
+		app.Action = func(ctx *cli.Context) error {
+			node1 := newEgressServiceNode(node1Name, v1.ConditionTrue)
+			node1.Labels = nil
+			service := v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      svcName,
+					Namespace: svcNamespace,
+					Annotations: map[string]string{
+						util.EgressServiceAnnotation: fmt.Sprintf("{\"nodeSelector\": {\"matchLabels\": {\"%s\": \"\"}}}", egressNodeName),
+					},
+				},
+				Spec: v1.ServiceSpec{
+					Type: v1.ServiceTypeLoadBalancer,
+				},
+				Status: v1.ServiceStatus{
+					LoadBalancer: v1.LoadBalancerStatus{
+						Ingress: []v1.LoadBalancerIngress{{IP: ingressIP}},
+					},
+				},
+			}
+
+			addSyncCmds("", "")
+			fakeOVN.start(ctx,
+				&v1.NodeList{Items: []v1.Node{node1}},
+				&v1.ServiceList{Items: []v1.Service{service}},
+			)
+			fakeOVN.controller.WatchEgressServices()
+
+			gomega.Consistently(getServiceHost).Should(gomega.BeEmpty())
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+
+			// labeling the node lets it host the service
+			addCreateCmds(node1Name, nodeLogicalRouterIPv4)
+			endpoints := v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      svcName,
+					Namespace: svcNamespace,
+				},
+				Subsets: []v1.EndpointSubset{
+					{
+						Addresses: []v1.EndpointAddress{{IP: endpointIP}},
+					},
+				},
+			}
+			_, err := fakeOVN.fakeClient.KubeClient.CoreV1().Endpoints(svcNamespace).Create(context.TODO(), &endpoints, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			node1.Labels = map[string]string{egressNodeName: ""}
+			_, err = fakeOVN.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node1, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getServiceHost).Should(gomega.Equal(node1Name))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("removes the rules of the egress services deleted while the master was down on startup", func() {
+		app.Action = func(ctx *cli.Context) error {
+			const (
+				stalePolicyUUID string = "5b0c9f1e-6d8a-4a4e-8f3b-2c7d9e1a6b54"
+				staleNatUUID    string = "c3e8a1d2-9b7f-4e6a-a5c4-1d2e3f4a5b6c"
+			)
+			// endpoints of a service which is not an egress service are ignored
+			endpoints := v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other",
+					Namespace: svcNamespace,
+				},
+				Subsets: []v1.EndpointSubset{
+					{
+						Addresses: []v1.EndpointAddress{{IP: endpointIP}},
+					},
+				},
+			}
+
+			addSyncCmds(
+				fmt.Sprintf("%s,egress-service=%s\n%s,name=egressip", stalePolicyUUID, svcKey, policyUUID),
+				fmt.Sprintf("%s,egress-service=%s\n%s,name=egressip", staleNatUUID, svcKey, natUUID),
+			)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 remove logical_router ovn_cluster_router policies " + stalePolicyUUID,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=name find logical_router nat{>=}" + staleNatUUID,
+				Output: "GR_" + node1Name,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				fmt.Sprintf("ovn-nbctl --timeout=15 remove logical_router GR_%s nat %s", node1Name, staleNatUUID),
+			})
+			fakeOVN.start(ctx,
+				&v1.NodeList{Items: []v1.Node{newEgressServiceNode(node1Name, v1.ConditionTrue)}},
+				&v1.EndpointsList{Items: []v1.Endpoints{endpoints}},
+			)
+			fakeOVN.controller.WatchEgressServices()
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Consistently(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
@@ -2,15 +2,18 @@ package ovn
 
 import (
 	"fmt"
+	"net"
+	"sort"
 	"strings"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
-	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	v1 "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
@@ -23,8 +26,8 @@ type gressPolicy struct {
 	idx             int
 
 	// peerAddressSet points to the addressSet that holds all peer pod
-	// IP addresess.
-	peerAddressSet addressset.AddressSet
+	// IP addresess, shared with the rules with the same peers.
+	peerAddressSet *netpolAddressSet
 
 	// peerV4AddressSets has Address sets for all namespaces and pod selectors for IPv4
 	peerV4AddressSets sets.String
@@ -36,33 +39,83 @@ type gressPolicy struct {
 	portPolicies []*portPolicy
 
 	ipBlock []*knet.IPBlock
+
+	// namedPortIPs holds, for every named port referenced by the rule, the
+	// IPs of the destination pods that resolve it, keyed by the container
+	// port number they resolve it to.
+	namedPortIPs map[string]map[int32]sets.String
+
+	// portGroupName, portGroupUUID, aclLogging and aclLoggingMeter record the
+	// port group and logging settings the rule's ACLs were created with, so
+	// that named port ACLs can be regenerated as destination pods come and go.
+	portGroupName   string
+	portGroupUUID   string
+	aclLogging      string
+	aclLoggingMeter string
+
+	// stateless makes the rule's ACLs allow-stateless, each of them with a
+	// reply ACL allowing the traffic in the reverse direction
+	stateless bool
 }
 
 type portPolicy struct {
 	protocol string
 	port     int32
+	// endPort, if set, makes the policy apply to the range of ports from
+	// port to endPort, inclusive.
+	endPort int32
+	// portName is set instead of port when the rule references a named
+	// container port of the destination pods.
+	portName string
 }
 
 func (pp *portPolicy) getL4Match() (string, error) {
 	if pp.protocol == TCP {
-		if pp.port != 0 {
-			return fmt.Sprintf("tcp && tcp.dst==%d", pp.port), nil
-		}
-		return "tcp", nil
+		return getL4PortMatch("tcp", pp.port, pp.endPort), nil
 	} else if pp.protocol == UDP {
-		if pp.port != 0 {
-			return fmt.Sprintf("udp && udp.dst==%d", pp.port), nil
-		}
-		return "udp", nil
+		return getL4PortMatch("udp", pp.port, pp.endPort), nil
 	} else if pp.protocol == SCTP {
-		if pp.port != 0 {
-			return fmt.Sprintf("sctp && sctp.dst==%d", pp.port), nil
-		}
-		return "sctp", nil
+		return getL4PortMatch("sctp", pp.port, pp.endPort), nil
 	}
 	return "", fmt.Errorf("unknown port protocol %v", pp.protocol)
 }
 
+// getL4PortMatch returns the match on the destination port of an OVN L4
+// protocol ("tcp", "udp" or "sctp"). It matches any port if port is 0, and
+// the range of ports from port to endPort if endPort is greater than port.
+func getL4PortMatch(protocol string, port, endPort int32) string {
+	if port == 0 {
+		return protocol
+	}
+	if endPort > port {
+		return fmt.Sprintf("%s && %s.dst >= %d && %s.dst <= %d", protocol, protocol, port, protocol, endPort)
+	}
+	return fmt.Sprintf("%s && %s.dst==%d", protocol, protocol, port)
+}
+
+// namedPortID returns the key identifying a named port policy, which is also
+// stored in the l4Match external ID of the ACL implementing it
+func (pp *portPolicy) namedPortID() string {
+	return pp.protocol + ":" + pp.portName
+}
+
+// getPodNamedPort returns the container port number that the pod exposes
+// under the given name and protocol, or 0 if it doesn't expose one
+func getPodNamedPort(pod *v1.Pod, protocol, name string) int32 {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			portProtocol := port.Protocol
+			if portProtocol == "" {
+				portProtocol = v1.ProtocolTCP
+			}
+			if port.Name == name && string(portProtocol) == protocol {
+				return port.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
 func newGressPolicy(policyType knet.PolicyType, idx int, namespace, name string) *gressPolicy {
 	return &gressPolicy{
 		policyNamespace:   namespace,
@@ -72,89 +125,69 @@ func newGressPolicy(policyType knet.PolicyType, idx int, namespace, name string)
 		peerV4AddressSets: sets.String{},
 		peerV6AddressSets: sets.String{},
 		portPolicies:      make([]*portPolicy, 0),
+		namedPortIPs:      make(map[string]map[int32]sets.String),
 	}
 }
 
-func (gp *gressPolicy) ensurePeerAddressSet(factory addressset.AddressSetFactory) error {
-	if gp.peerAddressSet != nil {
-		return nil
-	}
-
-	direction := strings.ToLower(string(gp.policyType))
-	asName := fmt.Sprintf("%s.%s.%s.%d", gp.policyNamespace, gp.policyName, direction, gp.idx)
-	as, err := factory.NewAddressSet(asName, nil)
-	if err != nil {
-		return err
-	}
-
+// setPeerAddressSet sets the address set that holds all the peer pod IP
+// addresses of the rule, shared with the rules with the same peers
+func (gp *gressPolicy) setPeerAddressSet(as *netpolAddressSet) {
 	gp.peerAddressSet = as
-	ipv4HashedAS, ipv6HashedAS := as.GetASHashNames()
+	ipv4HashedAS, ipv6HashedAS := as.addressSet.GetASHashNames()
 	if ipv4HashedAS != "" {
 		gp.peerV4AddressSets.Insert("$" + ipv4HashedAS)
 	}
 	if ipv6HashedAS != "" {
 		gp.peerV6AddressSets.Insert("$" + ipv6HashedAS)
 	}
-	return nil
 }
 
-func (gp *gressPolicy) addPeerSvcVip(service *v1.Service) error {
-	if gp.peerAddressSet == nil {
-		return fmt.Errorf("peer AddressSet is nil, cannot add peer Service: %s for gressPolicy: %s",
-			service.ObjectMeta.Name, gp.policyName)
-	}
-
-	klog.V(5).Infof("Service %s is applied to same namespace as network Policy, finding Service VIPs", service.Name)
-	ips := getSvcVips(service)
-
-	klog.V(5).Infof("Adding SVC clusterIP to gressPolicy's Address Set: %v", ips)
-	return gp.peerAddressSet.AddIPs(ips)
-}
-
-func (gp *gressPolicy) deletePeerSvcVip(service *v1.Service) error {
-	if gp.peerAddressSet == nil {
-		return fmt.Errorf("peer AddressSet is nil, cannot add peer Service: %s for gressPolicy: %s",
-			service.ObjectMeta.Name, gp.policyName)
+// validatePortPolicy checks that a NetworkPolicy port can be translated to
+// an ACL match
+func validatePortPolicy(portJSON *knet.NetworkPolicyPort) error {
+	endPort := portJSON.EndPort
+	if endPort == nil {
+		return nil
 	}
-
-	klog.V(5).Infof("Service %s is applied to same namespace as network Policy, finding cluster IPs", service.Name)
-	ips := getSvcVips(service)
-
-	klog.Infof("Deleting service %s, possible VIPs: %v from gressPolicy's %s Address Set", service.Name, ips, gp.policyName)
-	return gp.peerAddressSet.DeleteIPs(ips)
-}
-
-func (gp *gressPolicy) addPeerPod(pod *v1.Pod) error {
-	if gp.peerAddressSet == nil {
-		return fmt.Errorf("peer AddressSet is nil, cannot add peer pod: %s for gressPolicy: %s",
-			pod.ObjectMeta.Name, gp.policyName)
+	if portJSON.Port == nil {
+		return fmt.Errorf("endPort %d requires a port", *endPort)
 	}
-
-	ips, err := util.GetAllPodIPs(pod)
-	if err != nil {
-		return err
+	if portJSON.Port.Type == intstr.String {
+		return fmt.Errorf("endPort %d can't be used with named port %s", *endPort, portJSON.Port.StrVal)
 	}
-
-	return gp.peerAddressSet.AddIPs(ips)
-}
-
-func (gp *gressPolicy) deletePeerPod(pod *v1.Pod) error {
-	ips, err := util.GetAllPodIPs(pod)
-	if err != nil {
-		return err
+	if *endPort < portJSON.Port.IntVal || *endPort > 65535 {
+		return fmt.Errorf("endPort %d must be between port %d and 65535", *endPort, portJSON.Port.IntVal)
 	}
-	return gp.peerAddressSet.DeleteIPs(ips)
+	return nil
 }
 
 // If the port is not specified, it implies all ports for that protocol
-func (gp *gressPolicy) addPortPolicy(portJSON *knet.NetworkPolicyPort) {
+func (gp *gressPolicy) addPortPolicy(portJSON *knet.NetworkPolicyPort) error {
+	if err := validatePortPolicy(portJSON); err != nil {
+		return fmt.Errorf("invalid port in %s rule %d of network policy %s/%s: %v",
+			gp.policyType, gp.idx, gp.policyNamespace, gp.policyName, err)
+	}
 	pp := &portPolicy{protocol: string(*portJSON.Protocol),
 		port: 0,
 	}
 	if portJSON.Port != nil {
-		pp.port = portJSON.Port.IntVal
+		if portJSON.Port.Type == intstr.String {
+			pp.portName = portJSON.Port.StrVal
+			gp.namedPortIPs[pp.namedPortID()] = make(map[int32]sets.String)
+		} else {
+			pp.port = portJSON.Port.IntVal
+		}
+	}
+	if portJSON.EndPort != nil {
+		pp.endPort = *portJSON.EndPort
 	}
 	gp.portPolicies = append(gp.portPolicies, pp)
+	return nil
+}
+
+// hasNamedPorts returns true if any of the rule's ports is a named port
+func (gp *gressPolicy) hasNamedPorts() bool {
+	return len(gp.namedPortIPs) > 0
 }
 
 func (gp *gressPolicy) addIPBlock(ipblockJSON *knet.IPBlock) {
@@ -265,8 +298,28 @@ func (gp *gressPolicy) delNamespaceAddressSet(name, portGroupName string) {
 
 // localPodAddACL adds an ACL that implements the gress policy's rules to the
 // given Port Group (which should contain all pod logical switch ports selected
-// by the parent NetworkPolicy)
-func (gp *gressPolicy) localPodAddACL(portGroupName, portGroupUUID string, aclLogging string) {
+// by the parent NetworkPolicy). It returns the number of ACLs of the gress
+// policy in the Port Group.
+func (gp *gressPolicy) localPodAddACL(portGroupName, portGroupUUID string, aclLogging, aclLoggingMeter string) (int, error) {
+	gp.portGroupName = portGroupName
+	gp.portGroupUUID = portGroupUUID
+	gp.aclLogging = aclLogging
+	gp.aclLoggingMeter = aclLoggingMeter
+
+	var aclCount int
+	var errs []error
+	addACLAllow := func(match, l4Match string, ipBlockCidr bool) {
+		if err := gp.addACLAllow(match, l4Match, portGroupUUID, ipBlockCidr, aclLogging); err != nil {
+			errs = append(errs, err)
+			return
+		}
+		aclCount++
+		if gp.stateless {
+			// the reply ACL
+			aclCount++
+		}
+	}
+
 	l3Match := gp.getL3MatchFromAddressSet()
 	var lportMatch string
 	var cidrMatches []string
@@ -284,20 +337,20 @@ func (gp *gressPolicy) localPodAddACL(portGroupName, portGroupUUID string, aclLo
 			// Add ACL allow rule for IPBlock CIDR
 			cidrMatches = gp.getMatchFromIPBlock(lportMatch, l4Match)
 			for _, cidrMatch := range cidrMatches {
-				if err := gp.addACLAllow(cidrMatch, l4Match, portGroupUUID, true, aclLogging); err != nil {
-					klog.Warningf(err.Error())
-				}
+				addACLAllow(cidrMatch, l4Match, true)
 			}
 		}
 		// if there are pod/namespace selector, then allow packets from/to that address_set or
 		// if the NetworkPolicyPeer is empty, then allow from all sources or to all destinations.
 		if gp.sizeOfAddressSet() > 0 || len(gp.ipBlock) == 0 {
-			if err := gp.addACLAllow(match, l4Match, portGroupUUID, false, aclLogging); err != nil {
-				klog.Warningf(err.Error())
-			}
+			addACLAllow(match, l4Match, false)
 		}
 	}
 	for _, port := range gp.portPolicies {
+		if port.portName != "" {
+			// named ports are resolved as destination pods show up
+			continue
+		}
 		l4Match, err := port.getL4Match()
 		if err != nil {
 			continue
@@ -307,24 +360,56 @@ func (gp *gressPolicy) localPodAddACL(portGroupName, portGroupUUID string, aclLo
 			// Add ACL allow rule for IPBlock CIDR
 			cidrMatches = gp.getMatchFromIPBlock(lportMatch, l4Match)
 			for _, cidrMatch := range cidrMatches {
-				if err := gp.addACLAllow(cidrMatch, l4Match, portGroupUUID, true, aclLogging); err != nil {
-					klog.Warningf(err.Error())
-				}
+				addACLAllow(cidrMatch, l4Match, true)
 			}
 		}
 		if gp.sizeOfAddressSet() > 0 || len(gp.ipBlock) == 0 {
-			if err := gp.addACLAllow(match, l4Match, portGroupUUID, false, aclLogging); err != nil {
-				klog.Warningf(err.Error())
-			}
+			addACLAllow(match, l4Match, false)
 		}
 	}
+	return aclCount, kerrors.NewAggregate(errs)
+}
+
+// statelessReplyReplacer reverses the direction of the matches of the ACLs
+// of a rule to match the replies of the traffic they allow
+var statelessReplyReplacer = strings.NewReplacer(
+	"ip4.src", "ip4.dst", "ip4.dst", "ip4.src",
+	"ip6.src", "ip6.dst", "ip6.dst", "ip6.src",
+	"inport", "outport", "outport", "inport",
+	"tcp.dst", "tcp.src", "udp.dst", "udp.src", "sctp.dst", "sctp.src",
+)
+
+// getStatelessReplyMatch returns the match of the replies of the traffic
+// matched by an ACL of a rule
+func getStatelessReplyMatch(match string) string {
+	return statelessReplyReplacer.Replace(match)
+}
+
+// aclCreateArgs returns the arguments creating an allow ACL of the rule as
+// the given nbctl record id
+func (gp *gressPolicy) aclCreateArgs(id, match, l4Match, action string, ipBlockCidr bool, aclLogging string) []string {
+	return []string{"--id=" + id, "create",
+		"acl", fmt.Sprintf("priority=%s", defaultAllowPriority),
+		fmt.Sprintf("direction=%s", toLport), match,
+		fmt.Sprintf("action=%s", action),
+		fmt.Sprintf("log=%t", aclLogging != ""),
+		fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
+		fmt.Sprintf("meter=%s", gp.aclLoggingMeter),
+		fmt.Sprintf("name=%s", gp.policyNamespace),
+		fmt.Sprintf("external-ids:l4Match=\"%s\"", l4Match),
+		fmt.Sprintf("external-ids:ipblock_cidr=%t", ipBlockCidr),
+		fmt.Sprintf("external-ids:namespace=%s", gp.policyNamespace),
+		fmt.Sprintf("external-ids:policy=%s", gp.policyName),
+		fmt.Sprintf("external-ids:%s_num=%d", gp.policyType, gp.idx),
+		fmt.Sprintf("external-ids:policy_type=%s", gp.policyType)}
 }
 
 // addACLAllow adds an ACL with a given match to the given Port Group
 func (gp *gressPolicy) addACLAllow(match, l4Match, portGroupUUID string, ipBlockCidr bool, aclLogging string) error {
-	var direction, action string
-	direction = toLport
-	action = "allow-related"
+	action := "allow-related"
+	if gp.stateless {
+		action = aclStatelessAction
+	}
 
 	uuid, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading",
 		"--columns=_uuid", "find", "ACL",
@@ -344,21 +429,20 @@ func (gp *gressPolicy) addACLAllow(match, l4Match, portGroupUUID string, ipBlock
 		return nil
 	}
 
-	_, stderr, err = util.RunOVNNbctl("--id=@acl", "create",
-		"acl", fmt.Sprintf("priority=%s", defaultAllowPriority),
-		fmt.Sprintf("direction=%s", direction), match,
-		fmt.Sprintf("action=%s", action),
-		fmt.Sprintf("log=%t", aclLogging != ""),
-		fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
-		fmt.Sprintf("meter=%s", types.OvnACLLoggingMeter),
-		fmt.Sprintf("name=%s", gp.policyNamespace),
-		fmt.Sprintf("external-ids:l4Match=\"%s\"", l4Match),
-		fmt.Sprintf("external-ids:ipblock_cidr=%t", ipBlockCidr),
-		fmt.Sprintf("external-ids:namespace=%s", gp.policyNamespace),
-		fmt.Sprintf("external-ids:policy=%s", gp.policyName),
-		fmt.Sprintf("external-ids:%s_num=%d", gp.policyType, gp.idx),
-		fmt.Sprintf("external-ids:policy_type=%s", gp.policyType),
-		"--", "add", "port_group", portGroupUUID, "acls", "@acl")
+	args := gp.aclCreateArgs("@acl", match, l4Match, action, ipBlockCidr, aclLogging)
+	acls := []string{"@acl"}
+	if gp.stateless {
+		// the replies of stateless traffic are not matched by conntrack,
+		// they are allowed by an ACL with the reverse match
+		args = append(args, "--")
+		args = append(args, gp.aclCreateArgs("@reply", getStatelessReplyMatch(match), l4Match,
+			action, ipBlockCidr, aclLogging)...)
+		args = append(args, "external-ids:stateless_reply=true")
+		acls = append(acls, "@reply")
+	}
+	args = append(args, "--", "add", "port_group", portGroupUUID, "acls")
+	args = append(args, acls...)
+	_, stderr, err = util.RunOVNNbctl(args...)
 	if err != nil {
 		return fmt.Errorf("failed to create the acl allow rule for "+
 			"namespace=%s, policy=%s, stderr: %q (%v)", gp.policyNamespace,
@@ -368,8 +452,21 @@ func (gp *gressPolicy) addACLAllow(match, l4Match, portGroupUUID string, ipBlock
 	return nil
 }
 
-// modifyACLAllow updates an ACL with a new match
+// modifyACLAllow updates an ACL with a new match, and its reply ACL if the
+// rule is stateless
 func (gp *gressPolicy) modifyACLAllow(oldMatch, newMatch string) error {
+	if err := gp.setACLAllowMatch(oldMatch, newMatch); err != nil {
+		return err
+	}
+	if gp.stateless {
+		return gp.setACLAllowMatch(getStatelessReplyMatch(oldMatch), getStatelessReplyMatch(newMatch))
+	}
+	return nil
+}
+
+// setACLAllowMatch updates the match of the ACL of the rule with the given
+// match
+func (gp *gressPolicy) setACLAllowMatch(oldMatch, newMatch string) error {
 	uuid, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading",
 		"--columns=_uuid", "find", "ACL", oldMatch,
 		fmt.Sprintf("external-ids:namespace=%s", gp.policyNamespace),
@@ -395,22 +492,24 @@ func (gp *gressPolicy) modifyACLAllow(oldMatch, newMatch string) error {
 	return nil
 }
 
+// getIPBlockMatch returns the L3 match for an IPBlock peer
+func getIPBlockMatch(direction string, ipBlock *knet.IPBlock) string {
+	ipVersion := "ip4"
+	if utilnet.IsIPv6CIDRString(ipBlock.CIDR) {
+		ipVersion = "ip6"
+	}
+	if len(ipBlock.Except) == 0 {
+		return fmt.Sprintf("%s.%s == %s", ipVersion, direction, ipBlock.CIDR)
+	}
+	return fmt.Sprintf("%s.%s == %s && %s.%s != {%s}", ipVersion, direction, ipBlock.CIDR,
+		ipVersion, direction, strings.Join(ipBlock.Except, ", "))
+}
+
 func constructIPBlockStringsForACL(direction string, ipBlocks []*knet.IPBlock, lportMatch, l4Match string) []string {
 	var matchStrings []string
-	var matchStr, ipVersion string
+	var matchStr string
 	for _, ipBlock := range ipBlocks {
-		if utilnet.IsIPv6CIDRString(ipBlock.CIDR) {
-			ipVersion = "ip6"
-		} else {
-			ipVersion = "ip4"
-		}
-		if len(ipBlock.Except) == 0 {
-			matchStr = fmt.Sprintf("match=\"%s.%s == %s", ipVersion, direction, ipBlock.CIDR)
-
-		} else {
-			matchStr = fmt.Sprintf("match=\"%s.%s == %s && %s.%s != {%s}", ipVersion, direction, ipBlock.CIDR,
-				ipVersion, direction, strings.Join(ipBlock.Except, ", "))
-		}
+		matchStr = fmt.Sprintf("match=\"%s", getIPBlockMatch(direction, ipBlock))
 		if l4Match == noneMatch {
 			matchStr = fmt.Sprintf("%s && %s\"", matchStr, lportMatch)
 		} else {
@@ -437,6 +536,12 @@ func (gp *gressPolicy) localPodUpdateACL(oldl3Match, newl3Match, portGroupName s
 		}
 	}
 	for _, port := range gp.portPolicies {
+		if port.portName != "" {
+			if err := gp.syncNamedPortACL(port); err != nil {
+				klog.Warningf(err.Error())
+			}
+			continue
+		}
 		l4Match, err := port.getL4Match()
 		if err != nil {
 			continue
@@ -449,12 +554,180 @@ func (gp *gressPolicy) localPodUpdateACL(oldl3Match, newl3Match, portGroupName s
 	}
 }
 
-func (gp *gressPolicy) destroy() error {
-	if gp.peerAddressSet != nil {
-		if err := gp.peerAddressSet.Destroy(); err != nil {
+// addNamedPortPod resolves the rule's named ports against the container
+// ports of a destination pod and adds the pod to the named port ACLs
+func (gp *gressPolicy) addNamedPortPod(pod *v1.Pod) error {
+	return gp.updateNamedPortPod(pod, true)
+}
+
+// deleteNamedPortPod removes a destination pod from the named port ACLs
+func (gp *gressPolicy) deleteNamedPortPod(pod *v1.Pod) error {
+	return gp.updateNamedPortPod(pod, false)
+}
+
+func (gp *gressPolicy) updateNamedPortPod(pod *v1.Pod, add bool) error {
+	var ips []net.IP
+	for _, pp := range gp.portPolicies {
+		if pp.portName == "" {
+			continue
+		}
+		port := getPodNamedPort(pod, pp.protocol, pp.portName)
+		if port == 0 {
+			continue
+		}
+		if ips == nil {
+			var err error
+			if ips, err = util.GetAllPodIPs(pod); err != nil {
+				return err
+			}
+		}
+		resolved := gp.namedPortIPs[pp.namedPortID()]
+		podIPs := resolved[port]
+		changed := false
+		for _, ip := range ips {
+			ipStr := ip.String()
+			if add && !podIPs.Has(ipStr) {
+				if podIPs == nil {
+					podIPs = sets.NewString()
+					resolved[port] = podIPs
+				}
+				podIPs.Insert(ipStr)
+				changed = true
+			} else if !add && podIPs.Has(ipStr) {
+				podIPs.Delete(ipStr)
+				changed = true
+			}
+		}
+		if podIPs != nil && podIPs.Len() == 0 {
+			delete(resolved, port)
+		}
+		if !changed {
+			continue
+		}
+		if err := gp.syncNamedPortACL(pp); err != nil {
 			return err
 		}
-		gp.peerAddressSet = nil
+	}
+	return nil
+}
+
+// getNamedPortMatch returns the match on the destination pods that resolve
+// a named port and the container port each of them resolves it to, or ""
+// if no pod resolves it
+func (gp *gressPolicy) getNamedPortMatch(pp *portPolicy) string {
+	resolved := gp.namedPortIPs[pp.namedPortID()]
+	ports := make([]int, 0, len(resolved))
+	for port := range resolved {
+		ports = append(ports, int(port))
+	}
+	sort.Ints(ports)
+
+	var portMatches []string
+	for _, port := range ports {
+		l4Match, err := (&portPolicy{protocol: pp.protocol, port: int32(port)}).getL4Match()
+		if err != nil {
+			return ""
+		}
+		var v4IPs, v6IPs []string
+		for _, ip := range resolved[int32(port)].List() {
+			if utilnet.IsIPv6String(ip) {
+				v6IPs = append(v6IPs, ip)
+			} else {
+				v4IPs = append(v4IPs, ip)
+			}
+		}
+		var l3Matches []string
+		if len(v4IPs) > 0 {
+			l3Matches = append(l3Matches, fmt.Sprintf("ip4.dst == {%s}", strings.Join(v4IPs, ", ")))
+		}
+		if len(v6IPs) > 0 {
+			l3Matches = append(l3Matches, fmt.Sprintf("ip6.dst == {%s}", strings.Join(v6IPs, ", ")))
+		}
+		l3Match := l3Matches[0]
+		if len(l3Matches) > 1 {
+			l3Match = "(" + strings.Join(l3Matches, " || ") + ")"
+		}
+		portMatches = append(portMatches, fmt.Sprintf("(%s && %s)", l3Match, l4Match))
+	}
+	if len(portMatches) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(portMatches, " || ") + ")"
+}
+
+// getNamedPortPeerMatch returns the match on the rule's peers for named port
+// ACLs. IPBlock peers only apply to ingress rules, as named ports can't be
+// resolved for destinations that are not pods.
+func (gp *gressPolicy) getNamedPortPeerMatch() string {
+	var peerMatches []string
+	if gp.sizeOfAddressSet() > 0 || len(gp.ipBlock) == 0 {
+		peerMatches = append(peerMatches, gp.getL3MatchFromAddressSet())
+	}
+	if gp.policyType == knet.PolicyTypeIngress {
+		for _, ipBlock := range gp.ipBlock {
+			peerMatches = append(peerMatches, "("+getIPBlockMatch("src", ipBlock)+")")
+		}
+	}
+	switch len(peerMatches) {
+	case 0:
+		return ""
+	case 1:
+		return peerMatches[0]
+	default:
+		return "(" + strings.Join(peerMatches, " || ") + ")"
+	}
+}
+
+// syncNamedPortACL creates, updates or removes the ACL that implements a
+// named port so that it matches the destination pods currently resolving it
+func (gp *gressPolicy) syncNamedPortACL(pp *portPolicy) error {
+	if gp.portGroupUUID == "" {
+		return nil
+	}
+	l4Match := pp.namedPortID()
+	uuid, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading",
+		"--columns=_uuid", "find", "ACL",
+		fmt.Sprintf("external-ids:l4Match=\"%s\"", l4Match),
+		"external-ids:ipblock_cidr=false",
+		fmt.Sprintf("external-ids:namespace=%s", gp.policyNamespace),
+		fmt.Sprintf("external-ids:policy=%s", gp.policyName),
+		fmt.Sprintf("external-ids:%s_num=%d", gp.policyType, gp.idx),
+		fmt.Sprintf("external-ids:policy_type=%s", gp.policyType))
+	if err != nil {
+		return fmt.Errorf("find failed to get the named port %s rule for "+
+			"namespace=%s, policy=%s, stderr: %q (%v)", pp.portName,
+			gp.policyNamespace, gp.policyName, stderr, err)
+	}
+
+	namedPortMatch := gp.getNamedPortMatch(pp)
+	peerMatch := gp.getNamedPortPeerMatch()
+	if namedPortMatch == "" || peerMatch == "" {
+		if uuid == "" {
+			return nil
+		}
+		_, stderr, err = util.RunOVNNbctl("remove", "port_group", gp.portGroupUUID, "acls", uuid)
+		if err != nil {
+			return fmt.Errorf("failed to remove the named port %s rule for "+
+				"namespace=%s, policy=%s, stderr: %q (%v)", pp.portName,
+				gp.policyNamespace, gp.policyName, stderr, err)
+		}
+		return nil
+	}
+
+	var lportMatch string
+	if gp.policyType == knet.PolicyTypeIngress {
+		lportMatch = fmt.Sprintf("outport == @%s", gp.portGroupName)
+	} else {
+		lportMatch = fmt.Sprintf("inport == @%s", gp.portGroupName)
+	}
+	match := fmt.Sprintf("match=\"%s && %s && %s\"", peerMatch, namedPortMatch, lportMatch)
+	if uuid == "" {
+		return gp.addACLAllow(match, l4Match, gp.portGroupUUID, false, gp.aclLogging)
+	}
+	if _, stderr, err = util.RunOVNNbctl("set", "acl", uuid, match); err != nil {
+		return fmt.Errorf("failed to modify the named port %s rule for "+
+			"namespace=%s, policy=%s, stderr: %q (%v)", pp.portName,
+			gp.policyNamespace, gp.policyName, stderr, err)
 	}
 	return nil
 }
@@ -1,9 +1,13 @@
 package ovn
 
 import (
+	"testing"
+
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
-	"testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestGetMatchFromIPBlock(t *testing.T) {
@@ -117,3 +121,292 @@ func TestGetMatchFromIPBlock(t *testing.T) {
 		assert.Equal(t, tc.expected, output)
 	}
 }
+
+func newNamedPortPod(name, ip string, ports ...v1.ContainerPort) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testing",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "containerName",
+					Ports: ports,
+				},
+			},
+			NodeName: "node1",
+		},
+		Status: v1.PodStatus{
+			PodIP: ip,
+		},
+	}
+}
+
+func TestGetNamedPortMatch(t *testing.T) {
+	tcp := v1.ProtocolTCP
+	udp := v1.ProtocolUDP
+	httpPort := intstr.FromString("http")
+	dnsPort := intstr.FromString("dns")
+
+	testcases := []struct {
+		desc     string
+		port     knet.NetworkPolicyPort
+		pods     []*v1.Pod
+		deleted  []*v1.Pod
+		expected string
+	}{
+		{
+			desc:     "no pod resolves the named port",
+			port:     knet.NetworkPolicyPort{Protocol: &tcp, Port: &httpPort},
+			pods:     []*v1.Pod{newNamedPortPod("pod1", "10.128.1.3", v1.ContainerPort{Name: "metrics", ContainerPort: 9090})},
+			expected: "",
+		},
+		{
+			desc: "pods resolving the named port to the same port number",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &httpPort},
+			pods: []*v1.Pod{
+				newNamedPortPod("pod1", "10.128.1.3", v1.ContainerPort{Name: "http", ContainerPort: 8080}),
+				newNamedPortPod("pod2", "10.128.1.4", v1.ContainerPort{Name: "http", ContainerPort: 8080, Protocol: tcp}),
+			},
+			expected: "((ip4.dst == {10.128.1.3, 10.128.1.4} && tcp && tcp.dst==8080))",
+		},
+		{
+			desc: "pods resolving the named port to different port numbers",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &httpPort},
+			pods: []*v1.Pod{
+				newNamedPortPod("pod1", "10.128.1.3", v1.ContainerPort{Name: "http", ContainerPort: 8080}),
+				newNamedPortPod("pod2", "fd00:10:244::3", v1.ContainerPort{Name: "http", ContainerPort: 80}),
+			},
+			expected: "((ip6.dst == {fd00:10:244::3} && tcp && tcp.dst==80) || (ip4.dst == {10.128.1.3} && tcp && tcp.dst==8080))",
+		},
+		{
+			desc: "named port with a different protocol is not resolved",
+			port: knet.NetworkPolicyPort{Protocol: &udp, Port: &dnsPort},
+			pods: []*v1.Pod{
+				newNamedPortPod("pod1", "10.128.1.3", v1.ContainerPort{Name: "dns", ContainerPort: 53, Protocol: tcp}),
+				newNamedPortPod("pod2", "10.128.1.4", v1.ContainerPort{Name: "dns", ContainerPort: 53, Protocol: udp}),
+			},
+			expected: "((ip4.dst == {10.128.1.4} && udp && udp.dst==53))",
+		},
+		{
+			desc: "deleted pods are removed from the match",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &httpPort},
+			pods: []*v1.Pod{
+				newNamedPortPod("pod1", "10.128.1.3", v1.ContainerPort{Name: "http", ContainerPort: 8080}),
+				newNamedPortPod("pod2", "10.128.1.4", v1.ContainerPort{Name: "http", ContainerPort: 80}),
+			},
+			deleted: []*v1.Pod{
+				newNamedPortPod("pod2", "10.128.1.4", v1.ContainerPort{Name: "http", ContainerPort: 80}),
+			},
+			expected: "((ip4.dst == {10.128.1.3} && tcp && tcp.dst==8080))",
+		},
+	}
+
+	for _, tc := range testcases {
+		gressPolicy := newGressPolicy(knet.PolicyTypeIngress, 0, "testing", "test")
+		assert.NoError(t, gressPolicy.addPortPolicy(&tc.port), tc.desc)
+		assert.True(t, gressPolicy.hasNamedPorts(), tc.desc)
+		for _, pod := range tc.pods {
+			assert.NoError(t, gressPolicy.addNamedPortPod(pod), tc.desc)
+		}
+		for _, pod := range tc.deleted {
+			assert.NoError(t, gressPolicy.deleteNamedPortPod(pod), tc.desc)
+		}
+		output := gressPolicy.getNamedPortMatch(gressPolicy.portPolicies[0])
+		assert.Equal(t, tc.expected, output, tc.desc)
+	}
+}
+
+func TestGetL4Match(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		pp       portPolicy
+		expected string
+		err      bool
+	}{
+		{
+			desc:     "all TCP ports",
+			pp:       portPolicy{protocol: TCP},
+			expected: "tcp",
+		},
+		{
+			desc:     "single UDP port",
+			pp:       portPolicy{protocol: UDP, port: 53},
+			expected: "udp && udp.dst==53",
+		},
+		{
+			desc:     "TCP port range",
+			pp:       portPolicy{protocol: TCP, port: 30000, endPort: 32767},
+			expected: "tcp && tcp.dst >= 30000 && tcp.dst <= 32767",
+		},
+		{
+			desc:     "UDP port range",
+			pp:       portPolicy{protocol: UDP, port: 10000, endPort: 20000},
+			expected: "udp && udp.dst >= 10000 && udp.dst <= 20000",
+		},
+		{
+			desc:     "SCTP port range",
+			pp:       portPolicy{protocol: SCTP, port: 38412, endPort: 38472},
+			expected: "sctp && sctp.dst >= 38412 && sctp.dst <= 38472",
+		},
+		{
+			desc:     "port range of a single port",
+			pp:       portPolicy{protocol: TCP, port: 8080, endPort: 8080},
+			expected: "tcp && tcp.dst==8080",
+		},
+		{
+			desc: "unknown protocol",
+			pp:   portPolicy{protocol: "ICMP"},
+			err:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		output, err := tc.pp.getL4Match()
+		if tc.err {
+			assert.Error(t, err, tc.desc)
+		} else {
+			assert.NoError(t, err, tc.desc)
+			assert.Equal(t, tc.expected, output, tc.desc)
+		}
+	}
+}
+
+func TestValidatePortPolicy(t *testing.T) {
+	tcp := v1.ProtocolTCP
+	port := intstr.FromInt(30000)
+	namedPort := intstr.FromString("http")
+	endPort := func(port int32) *int32 { return &port }
+
+	testcases := []struct {
+		desc string
+		port knet.NetworkPolicyPort
+		err  bool
+	}{
+		{
+			desc: "single port",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &port},
+		},
+		{
+			desc: "port range",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &port, EndPort: endPort(32767)},
+		},
+		{
+			desc: "port range without a port",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, EndPort: endPort(32767)},
+			err:  true,
+		},
+		{
+			desc: "port range with a named port",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &namedPort, EndPort: endPort(32767)},
+			err:  true,
+		},
+		{
+			desc: "port range ending before its start",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &port, EndPort: endPort(20000)},
+			err:  true,
+		},
+		{
+			desc: "port range ending after the last port",
+			port: knet.NetworkPolicyPort{Protocol: &tcp, Port: &port, EndPort: endPort(70000)},
+			err:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		err := validatePortPolicy(&tc.port)
+		if tc.err {
+			assert.Error(t, err, tc.desc)
+		} else {
+			assert.NoError(t, err, tc.desc)
+		}
+	}
+}
+
+func TestGetStatelessReplyMatch(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		match    string
+		expected string
+	}{
+		{
+			desc:     "ingress rule with a port",
+			match:    "match=\"ip4.src == {$a123} && tcp && tcp.dst==80 && outport == @a456\"",
+			expected: "match=\"ip4.dst == {$a123} && tcp && tcp.src==80 && inport == @a456\"",
+		},
+		{
+			desc:     "egress dual-stack rule with a port range",
+			match:    "match=\"(ip4.dst == {$a123} || ip6.dst == {$a789}) && 1000<=udp.dst<=2000 && inport == @a456\"",
+			expected: "match=\"(ip4.src == {$a123} || ip6.src == {$a789}) && 1000<=udp.src<=2000 && outport == @a456\"",
+		},
+		{
+			desc:     "ingress ipBlock rule with exceptions",
+			match:    "match=\"ip4.src == 10.0.0.0/8 && ip4.src != {10.1.0.0/16} && sctp && sctp.dst==9 && outport == @a456\"",
+			expected: "match=\"ip4.dst == 10.0.0.0/8 && ip4.dst != {10.1.0.0/16} && sctp && sctp.src==9 && inport == @a456\"",
+		},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, getStatelessReplyMatch(tc.match), tc.desc)
+	}
+}
+
+func TestValidateStatelessNetworkPolicy(t *testing.T) {
+	udp := v1.ProtocolUDP
+	port := intstr.FromInt(5000)
+	namedPort := intstr.FromString("data")
+	peers := []knet.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+
+	testcases := []struct {
+		desc    string
+		ingress []knet.NetworkPolicyIngressRule
+		egress  []knet.NetworkPolicyEgressRule
+		err     bool
+	}{
+		{
+			desc: "rules with ports and peers",
+			ingress: []knet.NetworkPolicyIngressRule{{
+				Ports: []knet.NetworkPolicyPort{{Protocol: &udp, Port: &port}},
+				From:  peers,
+			}},
+			egress: []knet.NetworkPolicyEgressRule{{
+				Ports: []knet.NetworkPolicyPort{{Protocol: &udp, Port: &port}},
+				To:    peers,
+			}},
+		},
+		{
+			desc:    "rule with peers and no ports",
+			ingress: []knet.NetworkPolicyIngressRule{{From: peers}},
+		},
+		{
+			desc: "rule with ports and no peers",
+			egress: []knet.NetworkPolicyEgressRule{{
+				Ports: []knet.NetworkPolicyPort{{Protocol: &udp, Port: &port}},
+			}},
+		},
+		{
+			desc: "rule with a named port",
+			ingress: []knet.NetworkPolicyIngressRule{{
+				Ports: []knet.NetworkPolicyPort{{Protocol: &udp, Port: &namedPort}},
+				From:  peers,
+			}},
+			err: true,
+		},
+		{
+			desc:   "rule allowing all the traffic",
+			egress: []knet.NetworkPolicyEgressRule{{}},
+			err:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		policy := newNetworkPolicy("policy", "namespace", metav1.LabelSelector{}, tc.ingress, tc.egress)
+		err := validateStatelessNetworkPolicy(policy)
+		if tc.err {
+			assert.Error(t, err, tc.desc)
+		} else {
+			assert.NoError(t, err, tc.desc)
+		}
+	}
+}
@@ -139,6 +139,19 @@ func (manager *logicalSwitchManager) IsNonHostSubnetSwitch(nodeName string) bool
 	return ok && lsi.noHostSubnet
 }
 
+// GetSwitchNames returns the names of all the switches that have host-subnets
+func (manager *logicalSwitchManager) GetSwitchNames() []string {
+	manager.RLock()
+	defer manager.RUnlock()
+	names := make([]string, 0, len(manager.cache))
+	for name, lsi := range manager.cache {
+		if !lsi.noHostSubnet {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // Given a switch name, get all its host-subnets
 func (manager *logicalSwitchManager) GetSwitchSubnets(nodeName string) []*net.IPNet {
 	manager.RLock()
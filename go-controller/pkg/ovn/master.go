@@ -353,6 +353,9 @@ func (oc *Controller) StartClusterMaster(masterNodeName string) error {
 			oc.aclLoggingEnabled = false
 		}
 	}
+	if oc.aclLoggingEnabled {
+		oc.syncACLLoggingMeters()
+	}
 
 	if err := oc.SetupMaster(masterNodeName); err != nil {
 		klog.Errorf("Failed to setup master (%v)", err)
@@ -22,8 +22,19 @@ const (
 	routingNamespaceAnnotation   = "k8s.ovn.org/routing-namespaces"
 	routingNetworkAnnotation     = "k8s.ovn.org/routing-network"
 	bfdAnnotation                = "k8s.ovn.org/bfd-enabled"
+	// Annotations setting the BFD parameters of the routes to the gateways
+	// of a namespace or a gateway pod
+	bfdMinTxAnnotation      = "k8s.ovn.org/bfd-min-tx"
+	bfdMinRxAnnotation      = "k8s.ovn.org/bfd-min-rx"
+	bfdDetectMultAnnotation = "k8s.ovn.org/bfd-detect-mult"
 	// Annotation for enabling ACL logging to controller's log file
 	aclLoggingAnnotation = "k8s.ovn.org/acl-logging"
+	// Annotation rate limiting the ACL logs of a namespace, in packets per second,
+	// with a meter of its own instead of the one shared by all namespaces
+	aclLoggingRateLimitAnnotation = "k8s.ovn.org/acl-logging-rate-limit"
+	// Annotation for putting the deny ACLs of a namespace, EgressFirewall or
+	// NetworkPolicy in audit (log-only) mode
+	aclAuditAnnotation = "k8s.ovn.org/acl-audit"
 )
 
 func (oc *Controller) syncNamespaces(namespaces []interface{}) {
@@ -200,6 +211,10 @@ func (oc *Controller) AddNamespace(ns *kapi.Namespace) {
 		if _, ok := ns.Annotations[bfdAnnotation]; ok {
 			nsInfo.routingExternalGWs.bfdEnabled = true
 		}
+		nsInfo.routingExternalGWs.bfdParams, err = parseBFDParameters(ns.Annotations)
+		if err != nil {
+			klog.Warningf("Using the default BFD parameters for the gateways of namespace %s: %v", ns.Name, err)
+		}
 	}
 
 	annotation := ns.Annotations[aclLoggingAnnotation]
@@ -210,6 +225,11 @@ func (oc *Controller) AddNamespace(ns *kapi.Namespace) {
 			klog.Warningf("Namespace %s: ACL logging is not enabled due to malformed annotation", ns.Name)
 		}
 	}
+	oc.updateNamespaceACLLoggingRateLimit(ns, nsInfo)
+	nsInfo.aclAudit = isACLAuditEnabled(ns.Annotations)
+	if nsInfo.aclAudit {
+		klog.Infof("Namespace %s: ACL audit mode is enabled", ns.Name)
+	}
 	nsInfo.addressSet, err = oc.createNamespaceAddrSetAllPods(ns.Name)
 	if err != nil {
 		klog.Errorf(err.Error())
@@ -238,7 +258,8 @@ func (oc *Controller) updateNamespace(old, newer *kapi.Namespace) {
 	_, newBFDEnabled := newer.Annotations[bfdAnnotation]
 	_, oldBFDEnabled := old.Annotations[bfdAnnotation]
 
-	if gwAnnotation != oldGWAnnotation || newBFDEnabled != oldBFDEnabled {
+	if gwAnnotation != oldGWAnnotation || newBFDEnabled != oldBFDEnabled ||
+		bfdAnnotationsChanged(old.Annotations, newer.Annotations) {
 		// if old gw annotation was empty, new one must not be empty, so we should remove any per pod SNAT
 		if oldGWAnnotation == "" {
 			if config.Gateway.DisableSNATMultipleGWs && (len(nsInfo.routingExternalGWs.gws) != 0 || len(nsInfo.routingExternalPodGWs) != 0) {
@@ -263,7 +284,11 @@ func (oc *Controller) updateNamespace(old, newer *kapi.Namespace) {
 		if err != nil {
 			klog.Error(err.Error())
 		} else {
-			err = oc.addExternalGWsForNamespace(gatewayInfo{gws: exGateways, bfdEnabled: newBFDEnabled}, nsInfo, old.Name)
+			bfdParams, err := parseBFDParameters(newer.Annotations)
+			if err != nil {
+				klog.Warningf("Using the default BFD parameters for the gateways of namespace %s: %v", old.Name, err)
+			}
+			err = oc.addExternalGWsForNamespace(gatewayInfo{gws: exGateways, bfdEnabled: newBFDEnabled, bfdParams: bfdParams}, nsInfo, old.Name)
 			if err != nil {
 				klog.Error(err.Error())
 			}
@@ -290,16 +315,42 @@ func (oc *Controller) updateNamespace(old, newer *kapi.Namespace) {
 	aclAnnotation := newer.Annotations[aclLoggingAnnotation]
 	oldACLAnnotation := old.Annotations[aclLoggingAnnotation]
 	// support for ACL logging update, if new annotation is empty, make sure we propagate new setting
-	if aclAnnotation != oldACLAnnotation && (oc.aclLoggingCanEnable(aclAnnotation, nsInfo) || aclAnnotation == "") &&
-		len(nsInfo.networkPolicies) > 0 {
-		// deny rules are all one per namespace
-		if err := oc.setACLDenyLogging(old.Name, nsInfo, nsInfo.aclLogging.Deny); err != nil {
-			klog.Warningf(err.Error())
-		} else {
-			klog.Infof("Namespace %s: ACL logging setting updated to deny=%s allow=%s",
-				old.Name, nsInfo.aclLogging.Deny, nsInfo.aclLogging.Allow)
+	if aclAnnotation != oldACLAnnotation && (oc.aclLoggingCanEnable(aclAnnotation, nsInfo) || aclAnnotation == "") {
+		if len(nsInfo.networkPolicies) > 0 {
+			// deny rules are all one per namespace
+			if err := oc.setACLDenyLogging(old.Name, nsInfo, nsInfo.aclLogging.Deny); err != nil {
+				klog.Warningf(err.Error())
+			} else {
+				klog.Infof("Namespace %s: ACL logging setting updated to deny=%s allow=%s",
+					old.Name, nsInfo.aclLogging.Deny, nsInfo.aclLogging.Allow)
+			}
+		}
+		if nsInfo.egressFirewallPolicy != nil {
+			if err := setEgressFirewallACLLogging(old.Name, nsInfo.aclLogging, nsInfo.egressFirewallPolicy.audit); err != nil {
+				klog.Warningf(err.Error())
+			} else {
+				klog.Infof("Namespace %s: EgressFirewall ACL logging setting updated to deny=%s allow=%s",
+					old.Name, nsInfo.aclLogging.Deny, nsInfo.aclLogging.Allow)
+			}
 		}
 	}
+	if newer.Annotations[aclLoggingRateLimitAnnotation] != old.Annotations[aclLoggingRateLimitAnnotation] {
+		oc.updateNamespaceACLLoggingRateLimit(newer, nsInfo)
+	}
+	if aclAudit := isACLAuditEnabled(newer.Annotations); aclAudit != nsInfo.aclAudit {
+		nsInfo.aclAudit = aclAudit
+		if len(nsInfo.networkPolicies) > 0 {
+			if err := oc.setDefaultDenyAudit(old.Name, nsInfo, nsInfo.getDefaultDenyAudit()); err != nil {
+				klog.Warningf(err.Error())
+			}
+		}
+		if ef := nsInfo.egressFirewallPolicy; ef != nil {
+			if err := oc.setEgressFirewallAudit(ef, getEgressFirewallAudit(nsInfo.aclAudit, ef.auditAnnotated), nsInfo.aclLogging); err != nil {
+				klog.Warningf(err.Error())
+			}
+		}
+		klog.Infof("Namespace %s: ACL audit mode set to %t", old.Name, aclAudit)
+	}
 	oc.multicastUpdateNamespace(newer, nsInfo)
 }
 
@@ -319,6 +370,11 @@ func (oc *Controller) deleteNamespace(ns *kapi.Namespace) {
 	}
 	oc.deleteGWRoutesForNamespace(nsInfo)
 	oc.multicastDeleteNamespace(ns, nsInfo)
+	if nsInfo.aclLoggingMeter != "" {
+		if err := deleteACLLoggingMeter(nsInfo.aclLoggingMeter); err != nil {
+			klog.Errorf(err.Error())
+		}
+	}
 }
 
 // waitForNamespaceLocked waits up to 10 seconds for a Namespace to be known; use this
@@ -159,5 +159,76 @@ var _ = ginkgo.Describe("OVN Namespace Operations", func() {
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+
+		ginkgo.It("creates, updates and deletes the ACL logging meter of a namespace with a rate limit", func() {
+			app.Action = func(ctx *cli.Context) error {
+				const meter = "acl-logging-" + namespaceName
+				namespaceT := *newNamespace(namespaceName)
+				namespaceT.Annotations[aclLoggingRateLimitAnnotation] = "50"
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=bands find meter name=" + meter,
+					"ovn-nbctl --timeout=15 meter-add " + meter + " drop 50 pktps",
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find acl name=" + namespaceName + " meter=acl-logging",
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find acl name=audit_" + namespaceName + " meter=acl-logging",
+				})
+				fakeOvn.fakeExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find acl external-ids:egressFirewall=" + namespaceName + " meter=acl-logging",
+					Output: fakeUUID,
+				})
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find acl external-ids:egressFirewall=" + namespaceName + "-blockAll meter=acl-logging",
+					"ovn-nbctl --timeout=15 -- set acl " + fakeUUID + " meter=" + meter,
+				})
+				fakeOvn.start(ctx, &v1.NamespaceList{
+					Items: []v1.Namespace{
+						namespaceT,
+					},
+				})
+				fakeOvn.controller.WatchNamespaces()
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				// a new rate updates the band of the meter
+				const bandUUID = "2b2f4f7e-7a9c-4d8e-9c61-3f1b9d5e7a10"
+				fakeOvn.fakeExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=bands find meter name=" + meter,
+					Output: bandUUID,
+				})
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 set meter_band " + bandUUID + " rate=100",
+				})
+				namespaceT.Annotations[aclLoggingRateLimitAnnotation] = "100"
+				_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), &namespaceT, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				// an invalid rate keeps the meter and is reported
+				namespaceT.Annotations[aclLoggingRateLimitAnnotation] = "fast"
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), &namespaceT, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.HaveLen(1))
+				recordedEvent := <-fakeOvn.fakeRecorder.Events
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("InvalidACLLoggingRateLimit"))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring(meter))
+				gomega.Consistently(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				// deleting the namespace moves its remaining ACLs back to the shared meter
+				fakeOvn.fakeExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find acl meter=" + meter,
+					Output: fakeUUID,
+				})
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 -- set acl " + fakeUUID + " meter=acl-logging",
+					"ovn-nbctl --timeout=15 meter-del " + meter,
+				})
+				err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Delete(context.TODO(), namespaceName, *metav1.NewDeleteOptions(1))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 })
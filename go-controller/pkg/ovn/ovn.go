@@ -24,6 +24,10 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressqos "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+
+	adminnetworkpolicyapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	adminpolicybasedrouteapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1"
 
 	apiextension "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	utilnet "k8s.io/utils/net"
@@ -34,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
@@ -46,6 +51,10 @@ import (
 
 const (
 	egressfirewallCRD                string        = "egressfirewalls.k8s.ovn.org"
+	egressqosCRD                     string        = "egressqoses.k8s.ovn.org"
+	apbRouteCRD                      string        = "adminpolicybasedexternalroutes.k8s.ovn.org"
+	anpCRD                           string        = "adminnetworkpolicies.k8s.ovn.org"
+	banpCRD                          string        = "baselineadminnetworkpolicies.k8s.ovn.org"
 	clusterPortGroupName             string        = "clusterPortGroup"
 	clusterRtrPortGroupName          string        = "clusterRtrPortGroup"
 	egressFirewallDNSDefaultDuration time.Duration = 30 * time.Minute
@@ -104,6 +113,17 @@ type namespaceInfo struct {
 
 	// If not empty, then it has to be set to a logging a severity level, e.g. "notice", "alert", etc
 	aclLogging ACLLoggingLevels
+	// aclLoggingMeter is the meter of the namespace's ACL logs when it has the
+	// k8s.ovn.org/acl-logging-rate-limit annotation, empty when it shares the
+	// acl-logging meter
+	aclLoggingMeter string
+
+	// aclAudit is true when the namespace has the k8s.ovn.org/acl-audit annotation
+	// set to "true"; its deny ACLs then only log the traffic they would drop
+	aclAudit bool
+	// defaultDenyAudit tells whether the default deny ACLs of the namespace are
+	// currently in audit mode
+	defaultDenyAudit bool
 
 	// Per-namespace port group default deny UUIDs
 	portGroupIngressDenyUUID string // Port group for ingress deny rule
@@ -117,8 +137,24 @@ type Controller struct {
 	kube                  kube.Interface
 	watchFactory          *factory.WatchFactory
 	egressFirewallHandler *factory.Handler
+	egressQoSHandler      *factory.Handler
 	stopChan              <-chan struct{}
 
+	// handlers of the AdminPolicyBasedExternalRoutes and of the pods and
+	// namespaces they select
+	apbRouteHandler          *factory.Handler
+	apbRoutePodHandler       *factory.Handler
+	apbRouteNamespaceHandler *factory.Handler
+
+	// handlers of the AdminNetworkPolicies and of the
+	// BaselineAdminNetworkPolicies, and of the pods and namespaces they select
+	anpHandler           *factory.Handler
+	anpPodHandler        *factory.Handler
+	anpNamespaceHandler  *factory.Handler
+	banpHandler          *factory.Handler
+	banpPodHandler       *factory.Handler
+	banpNamespaceHandler *factory.Handler
+
 	// FIXME DUAL-STACK -  Make IP Allocators more dual-stack friendly
 	masterSubnetAllocator     *subnetallocator.SubnetAllocator
 	nodeLocalNatIPv4Allocator *ipallocator.Range
@@ -169,6 +205,30 @@ type Controller struct {
 	// A mutex for lspIngressDenyCache and lspEgressDenyCache
 	lspMutex *sync.Mutex
 
+	// Port groups of the pods selected by network policies, shared by the
+	// policies of a namespace with the same pod selector
+	netpolPortGroups map[string]*netpolPortGroup
+
+	// Peer address sets of network policy rules, shared by the rules of the
+	// policies of a namespace with the same peers
+	netpolAddressSets map[string]*netpolAddressSet
+
+	// A mutex for netpolPortGroups and netpolAddressSets
+	netpolSharedMutex sync.Mutex
+
+	// Egress rules with named ports of network policies, resolved against the
+	// pods by a single pod handler started with the first of them
+	namedPortPolicies    map[*gressPolicy]namedPortPolicy
+	namedPortMutex       sync.Mutex
+	namedPortHandlerOnce sync.Once
+
+	// Network policies ("namespace/name") which could not be enforced as
+	// specified
+	failedNetworkPolicies sets.String
+
+	// A mutex for failedNetworkPolicies
+	netpolStatusMutex sync.Mutex
+
 	// Supports multicast?
 	multicastSupport bool
 
@@ -177,6 +237,29 @@ type Controller struct {
 
 	egressFirewallDNS *EgressDNS
 
+	// Egress services programmed, by namespace_name
+	egressServices      map[string]*egressService
+	egressServicesMutex sync.Mutex
+
+	// EgressQoS of each namespace, by namespace. egressQoSMutex also
+	// serializes attaching QoS rows to the node logical switches.
+	egressQoSes    map[string]*egressQoS
+	egressQoSMutex sync.Mutex
+
+	// AdminPolicyBasedExternalRoutes state, by name. apbRoutesMutex also
+	// serializes the updates of the routes of the pods they select.
+	apbRoutes      map[string]*apbRoute
+	apbRoutesMutex sync.Mutex
+
+	// AdminNetworkPolicies and BaselineAdminNetworkPolicy state, by kind
+	// prefixed name
+	adminNetworkPolicies map[string]*adminNetworkPolicy
+	anpMutex             sync.Mutex
+
+	// status of the BFD sessions to the external gateways as last read from
+	// the NB database, only accessed by the periodic BFD status sync
+	bfdSessionStatus map[bfdSession]string
+
 	// Is ACL logging enabled while configuring meters?
 	aclLoggingEnabled bool
 
@@ -250,6 +333,9 @@ func NewOvnController(ovnClient *util.OVNClientset, wf *factory.WatchFactory,
 			KClient:              ovnClient.KubeClient,
 			EIPClient:            ovnClient.EgressIPClient,
 			EgressFirewallClient: ovnClient.EgressFirewallClient,
+			EgressQoSClient:      ovnClient.EgressQoSClient,
+			APBRouteClient:       ovnClient.APBRouteClient,
+			ANPClient:            ovnClient.ANPClient,
 		},
 		watchFactory:              wf,
 		stopChan:                  stopChan,
@@ -264,9 +350,15 @@ func NewOvnController(ovnClient *util.OVNClientset, wf *factory.WatchFactory,
 		lspIngressDenyCache:       make(map[string]int),
 		lspEgressDenyCache:        make(map[string]int),
 		lspMutex:                  &sync.Mutex{},
+		netpolPortGroups:          make(map[string]*netpolPortGroup),
+		netpolAddressSets:         make(map[string]*netpolAddressSet),
+		namedPortPolicies:         make(map[*gressPolicy]namedPortPolicy),
+		failedNetworkPolicies:     sets.NewString(),
 		eIPC: egressIPController{
 			assignmentRetryMutex:  &sync.Mutex{},
 			assignmentRetry:       make(map[string]bool),
+			assignmentCounts:      make(map[string]egressIPAssignmentCount),
+			marks:                 make(map[string]int),
 			namespaceHandlerMutex: &sync.Mutex{},
 			namespaceHandlerCache: make(map[string]factory.Handler),
 			podHandlerMutex:       &sync.Mutex{},
@@ -274,6 +366,11 @@ func NewOvnController(ovnClient *util.OVNClientset, wf *factory.WatchFactory,
 			allocatorMutex:        &sync.Mutex{},
 			allocator:             make(map[string]*egressNode),
 		},
+		egressQoSes:              make(map[string]*egressQoS),
+		apbRoutes:                make(map[string]*apbRoute),
+		adminNetworkPolicies:     make(map[string]*adminNetworkPolicy),
+		bfdSessionStatus:         make(map[bfdSession]string),
+		egressServices:           make(map[string]*egressService),
 		loadbalancerClusterCache: make(map[kapi.Protocol]string),
 		multicastSupport:         config.EnableMulticast,
 		aclLoggingEnabled:        true,
@@ -360,6 +457,10 @@ func (oc *Controller) Run(wg *sync.WaitGroup) error {
 		oc.WatchEgressIP()
 	}
 
+	if config.OVNKubernetesFeature.EnableEgressService {
+		oc.WatchEgressServices()
+	}
+
 	klog.Infof("Completing all the Watchers took %v", time.Since(start))
 
 	if config.Kubernetes.OVNEmptyLbEvents {
@@ -386,17 +487,20 @@ func (oc *Controller) Run(wg *sync.WaitGroup) error {
 	return nil
 }
 
-// syncPeriodic adds a goroutine that periodically does some work
-// right now there is only one ticker registered
-// for syncNodesPeriodic which deletes chassis records from the sbdb
-// every 5 minutes
+// syncPeriodic adds a goroutine that periodically does some work:
+// syncNodesPeriodic deletes chassis records from the sbdb every 5 minutes
+// and syncExternalGWBFDStatus reports the status of the BFD sessions to the
+// external gateways every 30 seconds
 func (oc *Controller) syncPeriodic() {
 	go func() {
 		nodeSyncTicker := time.NewTicker(5 * time.Minute)
+		bfdStatusTicker := time.NewTicker(30 * time.Second)
 		for {
 			select {
 			case <-nodeSyncTicker.C:
 				oc.syncNodesPeriodic()
+			case <-bfdStatusTicker.C:
+				oc.syncExternalGWBFDStatus()
 			case <-oc.stopChan:
 				return
 			}
@@ -464,7 +568,7 @@ func (oc *Controller) addRetryPod(pod *kapi.Pod) {
 func exGatewayAnnotationsChanged(oldPod, newPod *kapi.Pod) bool {
 	return oldPod.Annotations[routingNamespaceAnnotation] != newPod.Annotations[routingNamespaceAnnotation] ||
 		oldPod.Annotations[routingNetworkAnnotation] != newPod.Annotations[routingNetworkAnnotation] ||
-		oldPod.Annotations[bfdAnnotation] != newPod.Annotations[bfdAnnotation]
+		bfdAnnotationsChanged(oldPod.Annotations, newPod.Annotations)
 }
 
 func networkStatusAnnotationsChanged(oldPod, newPod *kapi.Pod) bool {
@@ -648,12 +752,46 @@ func (oc *Controller) WatchCRD() {
 				}
 
 				oc.egressFirewallDNS, err = NewEgressDNS(oc.addressSetFactory, oc.stopChan)
-				oc.egressFirewallDNS.Run(egressFirewallDNSDefaultDuration)
 				if err != nil {
 					klog.Errorf("Error Creating EgressFirewallDNS: %v", err)
+					return
 				}
+				oc.egressFirewallDNS.onUpdate = oc.updateEgressFirewallDNSStatus
+				oc.egressFirewallDNS.Run(egressFirewallDNSDefaultDuration)
 				oc.egressFirewallHandler = oc.WatchEgressFirewall()
 			}
+			if crd.Name == egressqosCRD {
+				err := oc.watchFactory.InitializeEgressQoSWatchFactory()
+				if err != nil {
+					klog.Errorf("Error Creating EgressQoSWatchFactory: %v", err)
+					return
+				}
+				oc.egressQoSHandler = oc.WatchEgressQoS()
+			}
+			if crd.Name == apbRouteCRD {
+				err := oc.watchFactory.InitializeAPBRouteWatchFactory()
+				if err != nil {
+					klog.Errorf("Error Creating APBRouteWatchFactory: %v", err)
+					return
+				}
+				oc.WatchAPBRoutes()
+			}
+			if crd.Name == anpCRD {
+				err := oc.watchFactory.InitializeANPWatchFactory()
+				if err != nil {
+					klog.Errorf("Error Creating ANPWatchFactory: %v", err)
+					return
+				}
+				oc.WatchAdminNetworkPolicies()
+			}
+			if crd.Name == banpCRD {
+				err := oc.watchFactory.InitializeBANPWatchFactory()
+				if err != nil {
+					klog.Errorf("Error Creating BANPWatchFactory: %v", err)
+					return
+				}
+				oc.WatchBaselineAdminNetworkPolicies()
+			}
 		},
 		UpdateFunc: func(old, newer interface{}) {
 		},
@@ -666,6 +804,38 @@ func (oc *Controller) WatchCRD() {
 				oc.egressFirewallHandler = nil
 				oc.watchFactory.ShutdownEgressFirewallWatchFactory()
 			}
+			if crd.Name == egressqosCRD {
+				oc.watchFactory.RemoveEgressQoSHandler(oc.egressQoSHandler)
+				oc.egressQoSHandler = nil
+				oc.watchFactory.ShutdownEgressQoSWatchFactory()
+			}
+			if crd.Name == apbRouteCRD {
+				oc.watchFactory.RemoveAPBRouteHandler(oc.apbRouteHandler)
+				oc.watchFactory.RemovePodHandler(oc.apbRoutePodHandler)
+				oc.watchFactory.RemoveNamespaceHandler(oc.apbRouteNamespaceHandler)
+				oc.apbRouteHandler = nil
+				oc.apbRoutePodHandler = nil
+				oc.apbRouteNamespaceHandler = nil
+				oc.watchFactory.ShutdownAPBRouteWatchFactory()
+			}
+			if crd.Name == anpCRD {
+				oc.watchFactory.RemoveANPHandler(oc.anpHandler)
+				oc.watchFactory.RemovePodHandler(oc.anpPodHandler)
+				oc.watchFactory.RemoveNamespaceHandler(oc.anpNamespaceHandler)
+				oc.anpHandler = nil
+				oc.anpPodHandler = nil
+				oc.anpNamespaceHandler = nil
+				oc.watchFactory.ShutdownANPWatchFactory()
+			}
+			if crd.Name == banpCRD {
+				oc.watchFactory.RemoveBANPHandler(oc.banpHandler)
+				oc.watchFactory.RemovePodHandler(oc.banpPodHandler)
+				oc.watchFactory.RemoveNamespaceHandler(oc.banpNamespaceHandler)
+				oc.banpHandler = nil
+				oc.banpPodHandler = nil
+				oc.banpNamespaceHandler = nil
+				oc.watchFactory.ShutdownBANPWatchFactory()
+			}
 		},
 	}, nil)
 }
@@ -679,11 +849,8 @@ func (oc *Controller) WatchEgressFirewall() *factory.Handler {
 			addErrors := oc.addEgressFirewall(egressFirewall)
 			if addErrors != nil {
 				klog.Error(addErrors)
-				egressFirewall.Status.Status = egressFirewallAddError
-			} else {
-
-				egressFirewall.Status.Status = egressFirewallAppliedCorrectly
 			}
+			oc.setEgressFirewallStatus(egressFirewall, addErrors, egressFirewallAddError)
 
 			err := oc.updateEgressFirewallWithRetry(egressFirewall)
 			if err != nil {
@@ -696,11 +863,19 @@ func (oc *Controller) WatchEgressFirewall() *factory.Handler {
 			if !reflect.DeepEqual(oldEgressFirewall.Spec, newEgressFirewall.Spec) {
 				errList := oc.updateEgressFirewall(oldEgressFirewall, newEgressFirewall)
 				if errList != nil {
-					newEgressFirewall.Status.Status = egressFirewallUpdateError
 					klog.Error(errList)
-				} else {
-					newEgressFirewall.Status.Status = egressFirewallAppliedCorrectly
 				}
+				oc.setEgressFirewallStatus(newEgressFirewall, errList, egressFirewallUpdateError)
+				err := oc.updateEgressFirewallWithRetry(newEgressFirewall)
+				if err != nil {
+					klog.Error(err)
+				}
+			} else if isACLAuditEnabled(oldEgressFirewall.Annotations) != isACLAuditEnabled(newEgressFirewall.Annotations) {
+				auditErr := oc.updateEgressFirewallAudit(newEgressFirewall)
+				if auditErr != nil {
+					klog.Error(auditErr)
+				}
+				oc.setEgressFirewallStatus(newEgressFirewall, auditErr, egressFirewallUpdateError)
 				err := oc.updateEgressFirewallWithRetry(newEgressFirewall)
 				if err != nil {
 					klog.Error(err)
@@ -717,6 +892,229 @@ func (oc *Controller) WatchEgressFirewall() *factory.Handler {
 	}, nil)
 }
 
+// WatchEgressQoS starts the watching of egressqos resource and calls
+// back the appropriate handler logic
+func (oc *Controller) WatchEgressQoS() *factory.Handler {
+	return oc.watchFactory.AddEgressQoSHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			egressQoS := obj.(*egressqos.EgressQoS).DeepCopy()
+			addErrors := oc.addEgressQoS(egressQoS)
+			if addErrors != nil {
+				klog.Error(addErrors)
+			}
+			setEgressQoSStatus(egressQoS, addErrors, egressQoSAddError)
+			err := oc.updateEgressQoSWithRetry(egressQoS)
+			if err != nil {
+				klog.Error(err)
+			}
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			newEgressQoS := newer.(*egressqos.EgressQoS).DeepCopy()
+			oldEgressQoS := old.(*egressqos.EgressQoS)
+			if !reflect.DeepEqual(oldEgressQoS.Spec, newEgressQoS.Spec) {
+				updateErrors := oc.updateEgressQoS(oldEgressQoS, newEgressQoS)
+				if updateErrors != nil {
+					klog.Error(updateErrors)
+				}
+				setEgressQoSStatus(newEgressQoS, updateErrors, egressQoSUpdateError)
+				err := oc.updateEgressQoSWithRetry(newEgressQoS)
+				if err != nil {
+					klog.Error(err)
+				}
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			egressQoS := obj.(*egressqos.EgressQoS)
+			deleteErrors := oc.deleteEgressQoS(egressQoS)
+			if deleteErrors != nil {
+				klog.Error(deleteErrors)
+			}
+		},
+	}, nil)
+}
+
+// WatchAPBRoutes starts the watching of the AdminPolicyBasedExternalRoute
+// resource and of the pods and namespaces they select, and calls back the
+// appropriate handler logic
+func (oc *Controller) WatchAPBRoutes() {
+	syncAPBRoute := func(name string) {
+		if err := oc.syncAPBRoute(name); err != nil {
+			klog.Errorf("Unable to sync AdminPolicyBasedExternalRoute %s: %v", name, err)
+		}
+	}
+	// no policy is known when the pods and namespaces handlers process the
+	// existing pods and namespaces, those are processed by the policies sync
+	oc.apbRoutePodHandler = oc.watchFactory.AddPodHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			pod := obj.(*kapi.Pod)
+			oc.syncAPBRoutesForPod(nil, pod)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldPod := old.(*kapi.Pod)
+			newPod := new.(*kapi.Pod)
+			if !reflect.DeepEqual(oldPod.Labels, newPod.Labels) ||
+				!reflect.DeepEqual(oldPod.Status.PodIPs, newPod.Status.PodIPs) ||
+				networkStatusAnnotationsChanged(oldPod, newPod) ||
+				isGatewayPodReady(oldPod) != isGatewayPodReady(newPod) {
+				oc.syncAPBRoutesForPod(oldPod, newPod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			pod := obj.(*kapi.Pod)
+			oc.syncAPBRoutesForPod(pod, nil)
+		},
+	}, nil)
+	oc.apbRouteNamespaceHandler = oc.watchFactory.AddNamespaceHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ns := obj.(*kapi.Namespace)
+			oc.syncAPBRoutesForNamespace(nil, ns)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldNs := old.(*kapi.Namespace)
+			newNs := new.(*kapi.Namespace)
+			// the namespace handler replaces the routes of the namespace pods
+			// on changes of the gateways the namespace is annotated with
+			if !reflect.DeepEqual(oldNs.Labels, newNs.Labels) ||
+				oldNs.Annotations[routingExternalGWsAnnotation] != newNs.Annotations[routingExternalGWsAnnotation] ||
+				bfdAnnotationsChanged(oldNs.Annotations, newNs.Annotations) {
+				oc.syncAPBRoutesForNamespace(oldNs, newNs)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+		},
+	}, nil)
+	oc.apbRouteHandler = oc.watchFactory.AddAPBRouteHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			policy := obj.(*adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute)
+			syncAPBRoute(policy.Name)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldPolicy := old.(*adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute)
+			newPolicy := new.(*adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute)
+			if !reflect.DeepEqual(oldPolicy.Spec, newPolicy.Spec) {
+				syncAPBRoute(newPolicy.Name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			policy := obj.(*adminpolicybasedrouteapi.AdminPolicyBasedExternalRoute)
+			syncAPBRoute(policy.Name)
+		},
+	}, nil)
+}
+
+// watchAdminNetworkPolicyPodsAndNamespaces starts the watching of the pods
+// and namespaces selected by the admin policies of the given kind, syncing the
+// policies with the given function on namespace label changes
+func (oc *Controller) watchAdminNetworkPolicyPodsAndNamespaces(baseline bool, syncPolicy func(name string)) (*factory.Handler, *factory.Handler) {
+	// no policy is known when the pods and namespaces handlers process the
+	// existing pods and namespaces, those are processed by the policies sync
+	podHandler := oc.watchFactory.AddPodHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			pod := obj.(*kapi.Pod)
+			oc.syncAdminNetworkPoliciesForPod(nil, pod, baseline)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldPod := old.(*kapi.Pod)
+			newPod := new.(*kapi.Pod)
+			if !reflect.DeepEqual(oldPod.Labels, newPod.Labels) ||
+				oldPod.Annotations[util.OvnPodAnnotationName] != newPod.Annotations[util.OvnPodAnnotationName] ||
+				!reflect.DeepEqual(oldPod.Status.PodIPs, newPod.Status.PodIPs) {
+				oc.syncAdminNetworkPoliciesForPod(oldPod, newPod, baseline)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			pod := obj.(*kapi.Pod)
+			oc.syncAdminNetworkPoliciesForPod(pod, nil, baseline)
+		},
+	}, nil)
+	namespaceHandler := oc.watchFactory.AddNamespaceHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldNs := old.(*kapi.Namespace)
+			newNs := new.(*kapi.Namespace)
+			if !reflect.DeepEqual(oldNs.Labels, newNs.Labels) {
+				for _, name := range oc.getAdminNetworkPoliciesForNamespace(oldNs, newNs, baseline) {
+					syncPolicy(name)
+				}
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+		},
+	}, nil)
+	return podHandler, namespaceHandler
+}
+
+// WatchAdminNetworkPolicies starts the watching of the AdminNetworkPolicy
+// resource and of the pods and namespaces they select, and calls back the
+// appropriate handler logic
+func (oc *Controller) WatchAdminNetworkPolicies() {
+	syncANP := func(name string) {
+		if err := oc.syncAdminNetworkPolicy(name); err != nil {
+			klog.Errorf("Unable to sync AdminNetworkPolicy %s: %v", name, err)
+		}
+	}
+	oc.anpPodHandler, oc.anpNamespaceHandler = oc.watchAdminNetworkPolicyPodsAndNamespaces(false, syncANP)
+	oc.anpHandler = oc.watchFactory.AddANPHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			policy := obj.(*adminnetworkpolicyapi.AdminNetworkPolicy)
+			syncANP(policy.Name)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldPolicy := old.(*adminnetworkpolicyapi.AdminNetworkPolicy)
+			newPolicy := new.(*adminnetworkpolicyapi.AdminNetworkPolicy)
+			if !reflect.DeepEqual(oldPolicy.Spec, newPolicy.Spec) {
+				syncANP(newPolicy.Name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			policy := obj.(*adminnetworkpolicyapi.AdminNetworkPolicy)
+			syncANP(policy.Name)
+		},
+	}, func(policies []interface{}) {
+		names := make([]string, 0, len(policies))
+		for _, policy := range policies {
+			names = append(names, policy.(*adminnetworkpolicyapi.AdminNetworkPolicy).Name)
+		}
+		oc.deleteStaleAdminNetworkPolicies(anpKeyPrefix, names)
+	})
+}
+
+// WatchBaselineAdminNetworkPolicies starts the watching of the
+// BaselineAdminNetworkPolicy resource and of the pods and namespaces it
+// selects, and calls back the appropriate handler logic
+func (oc *Controller) WatchBaselineAdminNetworkPolicies() {
+	syncBANP := func(name string) {
+		if err := oc.syncBaselineAdminNetworkPolicy(name); err != nil {
+			klog.Errorf("Unable to sync BaselineAdminNetworkPolicy %s: %v", name, err)
+		}
+	}
+	oc.banpPodHandler, oc.banpNamespaceHandler = oc.watchAdminNetworkPolicyPodsAndNamespaces(true, syncBANP)
+	oc.banpHandler = oc.watchFactory.AddBANPHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			policy := obj.(*adminnetworkpolicyapi.BaselineAdminNetworkPolicy)
+			syncBANP(policy.Name)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldPolicy := old.(*adminnetworkpolicyapi.BaselineAdminNetworkPolicy)
+			newPolicy := new.(*adminnetworkpolicyapi.BaselineAdminNetworkPolicy)
+			if !reflect.DeepEqual(oldPolicy.Spec, newPolicy.Spec) {
+				syncBANP(newPolicy.Name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			policy := obj.(*adminnetworkpolicyapi.BaselineAdminNetworkPolicy)
+			syncBANP(policy.Name)
+		},
+	}, func(policies []interface{}) {
+		names := make([]string, 0, len(policies))
+		for _, policy := range policies {
+			names = append(names, policy.(*adminnetworkpolicyapi.BaselineAdminNetworkPolicy).Name)
+		}
+		oc.deleteStaleAdminNetworkPolicies(banpKeyPrefix, names)
+	})
+}
+
 // WatchEgressNodes starts the watching of egress assignable nodes and calls
 // back the appropriate handler logic.
 func (oc *Controller) WatchEgressNodes() {
@@ -750,6 +1148,11 @@ func (oc *Controller) WatchEgressNodes() {
 			if !oldHadEgressLabel && !newHasEgressLabel {
 				return
 			}
+			if oldHadEgressLabel && newHasEgressLabel && getNodeEgressIPCapacity(oldNode) < getNodeEgressIPCapacity(newNode) &&
+				oc.isEgressNodeReady(newNode) && oc.isEgressNodeReachable(newNode) {
+				klog.Infof("Node: %s can host more egress IPs, retrying the assignment of unassigned egress IPs", newNode.Name)
+				oc.retryEgressIPAssignments(newNode.Name)
+			}
 			if oldHadEgressLabel && !newHasEgressLabel {
 				klog.Infof("Node: %s has been un-labelled, deleting it from egress assignment", newNode.Name)
 				oc.setNodeEgressAssignable(oldNode.Name, false)
@@ -805,6 +1208,74 @@ func (oc *Controller) WatchEgressNodes() {
 	}, oc.initClusterEgressPolicies)
 }
 
+// WatchEgressServices starts the watching of the services, endpoints and nodes
+// egress services depend on and calls back the appropriate handler logic
+func (oc *Controller) WatchEgressServices() {
+	syncService := func(namespace, name string) {
+		if err := oc.syncEgressService(namespace, name); err != nil {
+			klog.Errorf("Unable to sync egress service %s/%s: %v", namespace, name, err)
+		}
+	}
+	oc.watchFactory.AddServiceHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			svc := obj.(*kapi.Service)
+			if util.HasEgressServiceAnnotation(svc) {
+				syncService(svc.Namespace, svc.Name)
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldSvc := old.(*kapi.Service)
+			newSvc := new.(*kapi.Service)
+			if util.HasEgressServiceAnnotation(oldSvc) || util.HasEgressServiceAnnotation(newSvc) {
+				syncService(newSvc.Namespace, newSvc.Name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			svc := obj.(*kapi.Service)
+			if util.HasEgressServiceAnnotation(svc) {
+				syncService(svc.Namespace, svc.Name)
+			}
+		},
+	}, oc.syncEgressServiceRules)
+	oc.watchFactory.AddEndpointsHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ep := obj.(*kapi.Endpoints)
+			if oc.isEgressService(ep.Namespace, ep.Name) {
+				syncService(ep.Namespace, ep.Name)
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			epOld := old.(*kapi.Endpoints)
+			epNew := new.(*kapi.Endpoints)
+			if !reflect.DeepEqual(epNew.Subsets, epOld.Subsets) && oc.isEgressService(epNew.Namespace, epNew.Name) {
+				syncService(epNew.Namespace, epNew.Name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			ep := obj.(*kapi.Endpoints)
+			if oc.isEgressService(ep.Namespace, ep.Name) {
+				syncService(ep.Namespace, ep.Name)
+			}
+		},
+	}, nil)
+	oc.watchFactory.AddNodeHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			oc.syncEgressServices()
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldNode := old.(*kapi.Node)
+			newNode := new.(*kapi.Node)
+			if oc.isEgressNodeReady(oldNode) != oc.isEgressNodeReady(newNode) ||
+				!reflect.DeepEqual(oldNode.Labels, newNode.Labels) {
+				oc.syncEgressServices()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			oc.syncEgressServices()
+		},
+	}, nil)
+}
+
 // WatchEgressIP starts the watching of egressip resource and calls
 // back the appropriate handler logic.
 func (oc *Controller) WatchEgressIP() {
@@ -900,6 +1371,7 @@ func (oc *Controller) WatchNodes() {
 	var gatewaysFailed sync.Map
 	var mgmtPortFailed sync.Map
 	var addNodeFailed sync.Map
+	var egressQoSFailed sync.Map
 
 	start := time.Now()
 	oc.watchFactory.AddNodeHandler(cache.ResourceEventHandlerFuncs{
@@ -920,9 +1392,16 @@ func (oc *Controller) WatchNodes() {
 				addNodeFailed.Store(node.Name, true)
 				mgmtPortFailed.Store(node.Name, true)
 				gatewaysFailed.Store(node.Name, true)
+				egressQoSFailed.Store(node.Name, true)
 				return
 			}
 
+			// Mark the egress traffic of the pods on the node as requested by the EgressQoSes
+			if err := oc.addEgressQoSToNodeSwitch(node.Name); err != nil {
+				klog.Warningf("Error adding the EgressQoSes to node %s: %v", node.Name, err)
+				egressQoSFailed.Store(node.Name, true)
+			}
+
 			err = oc.syncNodeManagementPort(node, hostSubnets)
 			if err != nil {
 				if !util.IsAnnotationNotSetError(err) {
@@ -975,6 +1454,15 @@ func (oc *Controller) WatchNodes() {
 				}
 			}
 
+			_, failed = egressQoSFailed.Load(node.Name)
+			if failed {
+				if err := oc.addEgressQoSToNodeSwitch(node.Name); err != nil {
+					klog.Errorf("Error adding the EgressQoSes to node %s: %v", node.Name, err)
+				} else {
+					egressQoSFailed.Delete(node.Name)
+				}
+			}
+
 			oc.clearInitialNodeNetworkUnavailableCondition(oldNode, node)
 
 			_, failed = gatewaysFailed.Load(node.Name)
@@ -1002,6 +1490,7 @@ func (oc *Controller) WatchNodes() {
 			addNodeFailed.Delete(node.Name)
 			mgmtPortFailed.Delete(node.Name)
 			gatewaysFailed.Delete(node.Name)
+			egressQoSFailed.Delete(node.Name)
 		},
 	}, oc.syncNodes)
 	klog.Infof("Bootstrapping existing nodes and cleaning stale nodes took %v", time.Since(start))
@@ -14,10 +14,16 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
 
+	adminnetworkpolicy "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	adminnetworkpolicyfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned/fake"
+	adminpolicybasedroute "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1"
+	adminpolicybasedroutefake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/clientset/versioned/fake"
 	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
 	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/fake"
 	egressip "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned/fake"
+	egressqos "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/fake"
 	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 )
 
@@ -55,12 +61,23 @@ func NewFakeOVN(fexec *ovntest.FakeExec) *FakeOVN {
 func (o *FakeOVN) start(ctx *cli.Context, objects ...runtime.Object) {
 	egressIPObjects := []runtime.Object{}
 	egressFirewallObjects := []runtime.Object{}
+	egressQoSObjects := []runtime.Object{}
+	apbRouteObjects := []runtime.Object{}
+	anpObjects := []runtime.Object{}
 	v1Objects := []runtime.Object{}
 	for _, object := range objects {
 		if _, isEgressIPObject := object.(*egressip.EgressIPList); isEgressIPObject {
 			egressIPObjects = append(egressIPObjects, object)
 		} else if _, isEgressFirewallObject := object.(*egressfirewall.EgressFirewallList); isEgressFirewallObject {
 			egressFirewallObjects = append(egressFirewallObjects, object)
+		} else if _, isEgressQoSObject := object.(*egressqos.EgressQoSList); isEgressQoSObject {
+			egressQoSObjects = append(egressQoSObjects, object)
+		} else if _, isAPBRouteObject := object.(*adminpolicybasedroute.AdminPolicyBasedExternalRouteList); isAPBRouteObject {
+			apbRouteObjects = append(apbRouteObjects, object)
+		} else if _, isANPObject := object.(*adminnetworkpolicy.AdminNetworkPolicyList); isANPObject {
+			anpObjects = append(anpObjects, object)
+		} else if _, isBANPObject := object.(*adminnetworkpolicy.BaselineAdminNetworkPolicyList); isBANPObject {
+			anpObjects = append(anpObjects, object)
 		} else {
 			v1Objects = append(v1Objects, object)
 		}
@@ -71,6 +88,9 @@ func (o *FakeOVN) start(ctx *cli.Context, objects ...runtime.Object) {
 		KubeClient:           fake.NewSimpleClientset(v1Objects...),
 		EgressIPClient:       egressipfake.NewSimpleClientset(egressIPObjects...),
 		EgressFirewallClient: egressfirewallfake.NewSimpleClientset(egressFirewallObjects...),
+		EgressQoSClient:      egressqosfake.NewSimpleClientset(egressQoSObjects...),
+		APBRouteClient:       adminpolicybasedroutefake.NewSimpleClientset(apbRouteObjects...),
+		ANPClient:            adminnetworkpolicyfake.NewSimpleClientset(anpObjects...),
 		APIExtensionsClient:  apiextensionsfake.NewSimpleClientset(),
 	}
 	o.init()
@@ -84,6 +104,10 @@ func (o *FakeOVN) restart() {
 func (o *FakeOVN) shutdown() {
 	close(o.stopChan)
 	o.watcher.ShutdownEgressFirewallWatchFactory()
+	o.watcher.ShutdownEgressQoSWatchFactory()
+	o.watcher.ShutdownAPBRouteWatchFactory()
+	o.watcher.ShutdownANPWatchFactory()
+	o.watcher.ShutdownBANPWatchFactory()
 	o.watcher.Shutdown()
 	err := o.controller.ovnNBClient.Close()
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
@@ -96,6 +120,10 @@ func (o *FakeOVN) init() {
 	o.stopChan = make(chan struct{})
 	o.watcher, err = factory.NewMasterWatchFactory(o.fakeClient)
 	o.watcher.InitializeEgressFirewallWatchFactory()
+	o.watcher.InitializeEgressQoSWatchFactory()
+	o.watcher.InitializeAPBRouteWatchFactory()
+	o.watcher.InitializeANPWatchFactory()
+	o.watcher.InitializeBANPWatchFactory()
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	o.ovnNBClient = ovntest.NewMockOVNClient(goovn.DBNB)
 	o.ovnSBClient = ovntest.NewMockOVNClient(goovn.DBSB)
@@ -0,0 +1,60 @@
+package ovn
+
+import (
+	"fmt"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podSelector selects pods by the labels of their namespace and their own
+type podSelector struct {
+	namespaceSelector labels.Selector
+	labelSelector     labels.Selector
+}
+
+func newPodSelector(namespaceSelector, labelSelector *metav1.LabelSelector) (podSelector, error) {
+	nsSel, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return podSelector{}, fmt.Errorf("invalid namespace selector: %v", err)
+	}
+	podSel, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return podSelector{}, fmt.Errorf("invalid pod selector: %v", err)
+	}
+	return podSelector{namespaceSelector: nsSel, labelSelector: podSel}, nil
+}
+
+func (s podSelector) matchesNamespace(namespace *kapi.Namespace) bool {
+	return s.namespaceSelector.Matches(labels.Set(namespace.Labels))
+}
+
+func (s podSelector) matches(namespace *kapi.Namespace, pod *kapi.Pod) bool {
+	return s.matchesNamespace(namespace) && s.labelSelector.Matches(labels.Set(pod.Labels))
+}
+
+// listSelectedPods returns the pods selected by the selector, of the namespaces
+// from the informer cache
+func (oc *Controller) listSelectedPods(selector podSelector) ([]*kapi.Pod, error) {
+	namespaces, err := oc.watchFactory.GetNamespaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespaces: %v", err)
+	}
+	var pods []*kapi.Pod
+	for _, namespace := range namespaces {
+		if !selector.matchesNamespace(namespace) {
+			continue
+		}
+		nsPods, err := oc.watchFactory.GetPods(namespace.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pods of namespace %s: %v", namespace.Name, err)
+		}
+		for _, pod := range nsPods {
+			if selector.labelSelector.Matches(labels.Set(pod.Labels)) {
+				pods = append(pods, pod)
+			}
+		}
+	}
+	return pods, nil
+}
@@ -224,11 +224,34 @@ func (oc *Controller) getRoutingExternalGWs(ns string) gatewayInfo {
 	// return a copy of the object so it can be handled without the
 	// namespace locked
 	res.bfdEnabled = nsInfo.routingExternalGWs.bfdEnabled
+	res.bfdParams = nsInfo.routingExternalGWs.bfdParams
 	res.gws = make([]net.IP, len(nsInfo.routingExternalGWs.gws))
 	copy(res.gws, nsInfo.routingExternalGWs.gws)
 	return res
 }
 
+// getRoutingGWs returns the gateways the pods of the namespace are routed
+// through: the ones it is annotated with and the ones of the pods serving it
+func (oc *Controller) getRoutingGWs(ns string) []gatewayInfo {
+	routingExternalGWs := oc.getRoutingExternalGWs(ns)
+	routingPodGWs := oc.getRoutingPodGWs(ns)
+
+	// if we have any external or pod Gateways, add routes
+	gateways := make([]gatewayInfo, 0)
+
+	if len(routingExternalGWs.gws) > 0 {
+		gateways = append(gateways, routingExternalGWs)
+	}
+	for _, gw := range routingPodGWs {
+		if len(gw.gws) > 0 {
+			gateways = append(gateways, gw)
+		} else {
+			klog.Warningf("Found routingPodGW with no gateways ip set for namespace %s", ns)
+		}
+	}
+	return gateways
+}
+
 func (oc *Controller) getRoutingPodGWs(ns string) map[string]gatewayInfo {
 	nsInfo := oc.getNamespaceLocked(ns)
 	if nsInfo == nil {
@@ -241,6 +264,7 @@ func (oc *Controller) getRoutingPodGWs(ns string) map[string]gatewayInfo {
 	for k, v := range nsInfo.routingExternalPodGWs {
 		item := gatewayInfo{
 			bfdEnabled: v.bfdEnabled,
+			bfdParams:  v.bfdParams,
 			gws:        make([]net.IP, len(v.gws)),
 		}
 		copy(item.gws, v.gws)
@@ -456,30 +480,29 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 		return err
 	}
 
-	// add src-ip routes to GR if external gw annotation is set
-	routingExternalGWs := oc.getRoutingExternalGWs(pod.Namespace)
-	routingPodGWs := oc.getRoutingPodGWs(pod.Namespace)
-
-	// if we have any external or pod Gateways, add routes
-	gateways := make([]gatewayInfo, 0)
-
-	if len(routingExternalGWs.gws) > 0 {
-		gateways = append(gateways, routingExternalGWs)
-	}
-	for _, gw := range routingPodGWs {
-		if len(gw.gws) > 0 {
-			gateways = append(gateways, gw)
-		} else {
-			klog.Warningf("Found routingPodGW with no gateways ip set for namespace %s", pod.Namespace)
-		}
+	// add the pod to the admin network policies selecting it
+	if err = oc.addPodToAdminNetworkPolicies(pod, portInfo); err != nil {
+		return err
 	}
 
+	// add src-ip routes to GR if external gw annotation is set
+	gateways := oc.getRoutingGWs(pod.Namespace)
+	// and to the gateways of the AdminPolicyBasedExternalRoutes selecting the pod
+	apbGateways := oc.getAPBRouteGWsForPod(pod)
+
 	if len(gateways) > 0 {
 		err = oc.addGWRoutesForPod(gateways, podIfAddrs, pod.Namespace, pod.Spec.NodeName)
 		if err != nil {
 			return err
 		}
-	} else if config.Gateway.DisableSNATMultipleGWs {
+	}
+	if len(apbGateways) > 0 {
+		err = oc.addAPBRoutesForPod(apbGateways, podIfAddrs, pod.Namespace, pod.Spec.NodeName)
+		if err != nil {
+			return err
+		}
+	}
+	if len(gateways) == 0 && len(apbGateways) == 0 && config.Gateway.DisableSNATMultipleGWs {
 		// Add NAT rules to pods if disable SNAT is set and does not have
 		// namespace annotations to go through external egress router
 		if err = oc.addPerPodGRSNAT(pod, podIfAddrs); err != nil {
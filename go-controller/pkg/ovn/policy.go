@@ -5,16 +5,20 @@ import (
 	"net"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 
 	goovn "github.com/ebay/go-ovn"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 	kapi "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
@@ -31,9 +35,10 @@ type networkPolicy struct {
 	svcHandlerList  []*factory.Handler
 	nsHandlerList   []*factory.Handler
 	localPods       map[string]*lpInfo //pods effected by this policy
-	portGroupUUID   string             //uuid for OVN port_group
-	portGroupName   string
-	deleted         bool //deleted policy
+	portGroup       *netpolPortGroup   //OVN port_group shared by the policies with the same pod selector
+	deleted         bool               //deleted policy
+	audit           bool               //policy has the acl-audit annotation
+	stateless       bool               //policy has the acl-stateless annotation
 }
 
 func NewNetworkPolicy(policy *knet.NetworkPolicy) *networkPolicy {
@@ -47,6 +52,8 @@ func NewNetworkPolicy(policy *knet.NetworkPolicy) *networkPolicy {
 		svcHandlerList:  make([]*factory.Handler, 0),
 		nsHandlerList:   make([]*factory.Handler, 0),
 		localPods:       make(map[string]*lpInfo),
+		audit:           isACLAuditEnabled(policy.Annotations),
+		stateless:       isACLStatelessEnabled(policy.Annotations),
 	}
 	return np
 }
@@ -57,6 +64,15 @@ const (
 	noneMatch = "None"
 	// Default ACL logging severity
 	defaultACLLoggingSeverity = "info"
+	// Action of the deny ACLs in audit mode, the traffic is allowed and logged
+	aclAuditAction = "allow-related"
+	// Prefix of the name of the deny ACLs in audit mode
+	aclAuditNamePrefix = "audit_"
+	// Annotation for implementing the rules of a NetworkPolicy with stateless
+	// ACLs, bypassing conntrack
+	aclStatelessAnnotation = "k8s.ovn.org/acl-stateless"
+	// Action of the allow ACLs of the stateless NetworkPolicies
+	aclStatelessAction = "allow-stateless"
 	// IPv6 multicast traffic destined to dynamic groups must have the "T" bit
 	// set to 1: https://tools.ietf.org/html/rfc3307#section-4.3
 	ipv6DynamicMulticastMatch = "(ip6.dst[120..127] == 0xff && ip6.dst[116] == 1)"
@@ -79,8 +95,67 @@ func getACLLoggingSeverity(aclLogging string) string {
 	return defaultACLLoggingSeverity
 }
 
+// isACLAuditEnabled returns true if the annotations put the deny ACLs in audit mode
+func isACLAuditEnabled(annotations map[string]string) bool {
+	return annotations[aclAuditAnnotation] == "true"
+}
+
+// isACLStatelessEnabled returns true if the annotations make the allow ACLs of
+// a NetworkPolicy stateless
+func isACLStatelessEnabled(annotations map[string]string) bool {
+	return annotations[aclStatelessAnnotation] == "true"
+}
+
+// validateStatelessRule returns an error if the traffic allowed by a rule
+// can't be allowed statelessly, with its replies allowed by the reverse rule
+func validateStatelessRule(ports []knet.NetworkPolicyPort, peers []knet.NetworkPolicyPeer) error {
+	for _, port := range ports {
+		if port.Port != nil && port.Port.Type == intstr.String {
+			return fmt.Errorf("named port %s can't be matched statelessly", port.Port.StrVal)
+		}
+	}
+	if len(ports) == 0 && len(peers) == 0 {
+		return fmt.Errorf("the replies of a rule allowing all the traffic would " +
+			"allow all the traffic in the reverse direction")
+	}
+	return nil
+}
+
+// validateStatelessNetworkPolicy returns an error if the rules of a
+// NetworkPolicy can't be implemented with stateless ACLs
+func validateStatelessNetworkPolicy(policy *knet.NetworkPolicy) error {
+	for i, ingress := range policy.Spec.Ingress {
+		if err := validateStatelessRule(ingress.Ports, ingress.From); err != nil {
+			return fmt.Errorf("ingress rule %d: %v", i, err)
+		}
+	}
+	for i, egress := range policy.Spec.Egress {
+		if err := validateStatelessRule(egress.Ports, egress.To); err != nil {
+			return fmt.Errorf("egress rule %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// getDenyACLAction returns the action of a deny ACL, depending on whether it is in audit mode
+func getDenyACLAction(audit bool) string {
+	if audit {
+		return aclAuditAction
+	}
+	return "drop"
+}
+
+// getDenyACLName returns the name of a deny ACL, depending on whether it is in audit mode
+func getDenyACLName(name string, audit bool) string {
+	if audit {
+		return aclAuditNamePrefix + name
+	}
+	return name
+}
+
 func (oc *Controller) syncNetworkPolicies(networkPolicies []interface{}) {
 	expectedPolicies := make(map[string]map[string]bool)
+	policies := make([]*knet.NetworkPolicy, 0, len(networkPolicies))
 	for _, npInterface := range networkPolicies {
 		policy, ok := npInterface.(*knet.NetworkPolicy)
 		if !ok {
@@ -96,24 +171,34 @@ func (oc *Controller) syncNetworkPolicies(networkPolicies []interface{}) {
 				policy.Name: true,
 			}
 		}
+		policies = append(policies, policy)
 	}
+	expectedPortGroups, expectedAddressSets := getNetpolSharedNames(policies)
 
-	err := oc.addressSetFactory.ForEachAddressSet(func(addrSetName, namespaceName, policyName string) {
-		if policyName != "" && !expectedPolicies[namespaceName][policyName] {
+	err := oc.addressSetFactory.ForEachAddressSet(func(addrSetName, namespaceName, nameSuffix string) {
+		if nameSuffix == "" || expectedAddressSets.Has(addrSetName) {
+			return
+		}
+		if !strings.HasPrefix(nameSuffix, netpolSharedPrefix) && !expectedPolicies[namespaceName][nameSuffix] {
 			// policy doesn't exist on k8s. Delete the port group
-			portGroupName := fmt.Sprintf("%s_%s", namespaceName, policyName)
+			portGroupName := fmt.Sprintf("%s_%s", namespaceName, nameSuffix)
 			hashedLocalPortGroup := hashedPortGroup(portGroupName)
 			deletePortGroup(hashedLocalPortGroup)
+		}
 
-			// delete the address sets for this old policy from OVN
-			if err := oc.addressSetFactory.DestroyAddressSetInBackingStore(addrSetName); err != nil {
-				klog.Errorf(err.Error())
-			}
+		// delete the address sets no longer used from OVN, including the
+		// per rule address sets of earlier versions of the existing policies
+		if err := oc.addressSetFactory.DestroyAddressSetInBackingStore(addrSetName); err != nil {
+			klog.Errorf(err.Error())
 		}
 	})
 	if err != nil {
 		klog.Errorf("Error in syncing network policies: %v", err)
 	}
+
+	if err := deleteStaleNetpolPortGroups(expectedPolicies, expectedPortGroups); err != nil {
+		klog.Errorf("Error in syncing network policy port groups: %v", err)
+	}
 }
 
 func addAllowACLFromNode(logicalSwitch string, mgmtPortIP net.IP, ovnNBClient goovn.Client) error {
@@ -168,7 +253,7 @@ func getACLPortGroupUUID(match, action string, policyType knet.PolicyType) (stri
 	return uuid, nil
 }
 
-func addACLPortGroup(policyNamespace, portGroupUUID, direction, priority, match, action string, policyType knet.PolicyType, aclLogging string) error {
+func addACLPortGroup(policyNamespace, portGroupUUID, direction, priority, match, action string, policyType knet.PolicyType, aclLogging, aclLoggingMeter string) error {
 	uuid, err := getACLPortGroupUUID(match, action, policyType)
 	if err != nil {
 		return err
@@ -184,7 +269,7 @@ func addACLPortGroup(policyNamespace, portGroupUUID, direction, priority, match,
 		fmt.Sprintf("priority=%s", priority),
 		fmt.Sprintf("direction=%s", direction), match, "action="+action,
 		fmt.Sprintf("log=%t", aclLogging != ""), fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
-		fmt.Sprintf("meter=%s", types.OvnACLLoggingMeter),
+		fmt.Sprintf("meter=%s", aclLoggingMeter),
 		fmt.Sprintf("name=%.63s", policyNamespace),
 		fmt.Sprintf("external-ids:default-deny-policy-type=%s", policyType),
 		"--", "add", "port_group", portGroupUUID,
@@ -254,16 +339,20 @@ func (oc *Controller) createDefaultDenyPortGroup(ns string, nsInfo *namespaceInf
 		return fmt.Errorf("failed to create port_group for %s (%v)",
 			portGroupName, err)
 	}
+	// in audit mode the default deny ACL allows the traffic and always logs it
+	if nsInfo.defaultDenyAudit {
+		aclLogging = getACLLoggingSeverity(aclLogging)
+	}
 	match := getACLMatch(portGroupName, "", policyType)
-	err = addACLPortGroup(ns, portGroupUUID, toLport,
-		defaultDenyPriority, match, "drop", policyType, aclLogging)
+	err = addACLPortGroup(getDenyACLName(ns, nsInfo.defaultDenyAudit), portGroupUUID, toLport,
+		defaultDenyPriority, match, getDenyACLAction(nsInfo.defaultDenyAudit), policyType, aclLogging, nsInfo.getACLLoggingMeter())
 	if err != nil {
 		return fmt.Errorf("failed to create default deny ACL for port group %v", err)
 	}
 
 	match = getACLMatch(portGroupName, "arp", policyType)
 	err = addACLPortGroup(ns, portGroupUUID, toLport,
-		defaultAllowPriority, match, "allow", policyType, "")
+		defaultAllowPriority, match, "allow", policyType, "", nsInfo.getACLLoggingMeter())
 	if err != nil {
 		return fmt.Errorf("failed to create default allow ARP ACL for port group %v", err)
 	}
@@ -280,9 +369,12 @@ func (oc *Controller) createDefaultDenyPortGroup(ns string, nsInfo *namespaceInf
 func (oc *Controller) setACLDenyLogging(ns string, nsInfo *namespaceInfo, aclLogging string) error {
 
 	aclLoggingSev := getACLLoggingSeverity(aclLogging)
+	// deny ACLs in audit mode always log
+	aclLog := aclLogging != "" || nsInfo.defaultDenyAudit
+	action := getDenyACLAction(nsInfo.defaultDenyAudit)
 
 	match := getACLMatch(defaultDenyPortGroup(ns, "ingressDefaultDeny"), "", knet.PolicyTypeIngress)
-	uuid, err := getACLPortGroupUUID(match, "drop", knet.PolicyTypeIngress)
+	uuid, err := getACLPortGroupUUID(match, action, knet.PolicyTypeIngress)
 	if err != nil {
 		return err
 	}
@@ -291,12 +383,12 @@ func (oc *Controller) setACLDenyLogging(ns string, nsInfo *namespaceInfo, aclLog
 		return fmt.Errorf("failed to find the ACL for pg=%s: %v", nsInfo.portGroupIngressDenyUUID, err)
 	}
 	if _, stderr, err := util.RunOVNNbctl("set", "acl", uuid,
-		fmt.Sprintf("log=%t", aclLogging != ""), fmt.Sprintf("severity=%s", aclLoggingSev)); err != nil {
+		fmt.Sprintf("log=%t", aclLog), fmt.Sprintf("severity=%s", aclLoggingSev)); err != nil {
 		return fmt.Errorf("failed to modify the pg=%s, stderr: %q (%v)", nsInfo.portGroupIngressDenyUUID, stderr, err)
 	}
 
 	match = getACLMatch(defaultDenyPortGroup(ns, "egressDefaultDeny"), "", knet.PolicyTypeEgress)
-	uuid, err = getACLPortGroupUUID(match, "drop", knet.PolicyTypeEgress)
+	uuid, err = getACLPortGroupUUID(match, action, knet.PolicyTypeEgress)
 	if err != nil {
 		return err
 	}
@@ -305,13 +397,68 @@ func (oc *Controller) setACLDenyLogging(ns string, nsInfo *namespaceInfo, aclLog
 		return fmt.Errorf("failed to find the ACL for pg=%s: %v", nsInfo.portGroupEgressDenyUUID, err)
 	}
 	if _, stderr, err := util.RunOVNNbctl("set", "acl", uuid,
-		fmt.Sprintf("log=%t", aclLogging != ""), fmt.Sprintf("severity=%s", aclLoggingSev)); err != nil {
+		fmt.Sprintf("log=%t", aclLog), fmt.Sprintf("severity=%s", aclLoggingSev)); err != nil {
 		return fmt.Errorf("failed to modify the pg=%s, stderr: %q (%v)", nsInfo.portGroupEgressDenyUUID, stderr, err)
 	}
 
 	return nil
 }
 
+// getDefaultDenyAudit returns whether the default deny ACLs of the namespace should be in
+// audit mode: either the namespace is annotated, or all of its network policies are
+func (nsInfo *namespaceInfo) getDefaultDenyAudit() bool {
+	if nsInfo.aclAudit {
+		return true
+	}
+	if len(nsInfo.networkPolicies) == 0 {
+		return false
+	}
+	for _, np := range nsInfo.networkPolicies {
+		if !np.audit {
+			return false
+		}
+	}
+	return true
+}
+
+// setDefaultDenyAudit switches the default deny ACLs of the namespace in or out of audit mode.
+// In audit mode they allow the traffic instead of dropping it, and log it under a name
+// prefixed with "audit_".
+func (oc *Controller) setDefaultDenyAudit(ns string, nsInfo *namespaceInfo, audit bool) error {
+	if nsInfo.defaultDenyAudit == audit {
+		return nil
+	}
+	aclLogging := nsInfo.aclLogging.Deny
+	if audit {
+		aclLogging = getACLLoggingSeverity(aclLogging)
+	}
+	for _, policyType := range []knet.PolicyType{knet.PolicyTypeIngress, knet.PolicyTypeEgress} {
+		var portGroupName string
+		if policyType == knet.PolicyTypeIngress {
+			portGroupName = defaultDenyPortGroup(ns, "ingressDefaultDeny")
+		} else {
+			portGroupName = defaultDenyPortGroup(ns, "egressDefaultDeny")
+		}
+		match := getACLMatch(portGroupName, "", policyType)
+		uuid, err := getACLPortGroupUUID(match, getDenyACLAction(nsInfo.defaultDenyAudit), policyType)
+		if err != nil {
+			return err
+		}
+		if uuid == "" {
+			// not suppose to happen
+			return fmt.Errorf("failed to find the default deny ACL for pg=%s", portGroupName)
+		}
+		if _, stderr, err := util.RunOVNNbctl("set", "acl", uuid, "action="+getDenyACLAction(audit),
+			fmt.Sprintf("log=%t", aclLogging != ""), fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
+			fmt.Sprintf("name=%.63s", getDenyACLName(ns, audit))); err != nil {
+			return fmt.Errorf("failed to set audit mode to %t for the default deny ACL of pg=%s, stderr: %q (%v)",
+				audit, portGroupName, stderr, err)
+		}
+	}
+	nsInfo.defaultDenyAudit = audit
+	return nil
+}
+
 func getACLMatchAF(ipv4Match, ipv6Match string) string {
 	if config.IPv4Mode && config.IPv6Mode {
 		return "(" + ipv4Match + " || " + ipv6Match + ")"
@@ -383,7 +530,7 @@ func (oc *Controller) createMulticastAllowPolicy(ns string, nsInfo *namespaceInf
 	match := getACLMatch(portGroupName, getMulticastACLEgrMatch(),
 		knet.PolicyTypeEgress)
 	err = addACLPortGroup(ns, nsInfo.portGroupUUID, fromLport,
-		defaultMcastAllowPriority, match, "allow", knet.PolicyTypeEgress, "")
+		defaultMcastAllowPriority, match, "allow", knet.PolicyTypeEgress, "", nsInfo.getACLLoggingMeter())
 	if err != nil {
 		return fmt.Errorf("failed to create allow egress multicast ACL for %s (%v)",
 			ns, err)
@@ -392,7 +539,7 @@ func (oc *Controller) createMulticastAllowPolicy(ns string, nsInfo *namespaceInf
 	match = getACLMatch(portGroupName, getMulticastACLIgrMatch(nsInfo),
 		knet.PolicyTypeIngress)
 	err = addACLPortGroup(ns, nsInfo.portGroupUUID, toLport,
-		defaultMcastAllowPriority, match, "allow", knet.PolicyTypeIngress, "")
+		defaultMcastAllowPriority, match, "allow", knet.PolicyTypeIngress, "", nsInfo.getACLLoggingMeter())
 	if err != nil {
 		return fmt.Errorf("failed to create allow ingress multicast ACL for %s (%v)",
 			ns, err)
@@ -459,14 +606,14 @@ func (oc *Controller) createDefaultDenyMulticastPolicy() error {
 	// to be forwarded to pods.
 	match := "match=\"" + getMulticastACLMatch() + "\""
 	err := addACLPortGroup("", oc.clusterPortGroupUUID, fromLport,
-		defaultMcastDenyPriority, match, "drop", knet.PolicyTypeEgress, "")
+		defaultMcastDenyPriority, match, "drop", knet.PolicyTypeEgress, "", types.OvnACLLoggingMeter)
 	if err != nil {
 		return fmt.Errorf("failed to create default deny multicast egress ACL: %v", err)
 	}
 
 	// By default deny any ingress multicast traffic to any pod.
 	err = addACLPortGroup("", oc.clusterPortGroupUUID, toLport,
-		defaultMcastDenyPriority, match, "drop", knet.PolicyTypeIngress, "")
+		defaultMcastDenyPriority, match, "drop", knet.PolicyTypeIngress, "", types.OvnACLLoggingMeter)
 	if err != nil {
 		return fmt.Errorf("failed to create default deny multicast ingress ACL: %v", err)
 	}
@@ -485,14 +632,14 @@ func (oc *Controller) createDefaultAllowMulticastPolicy() error {
 	mcastMatch := getMulticastACLMatch()
 	match := getACLMatch(clusterRtrPortGroupName, mcastMatch, knet.PolicyTypeEgress)
 	err := addACLPortGroup("", oc.clusterRtrPortGroupUUID, fromLport,
-		defaultRoutedMcastAllowPriority, match, "allow", knet.PolicyTypeEgress, "")
+		defaultRoutedMcastAllowPriority, match, "allow", knet.PolicyTypeEgress, "", types.OvnACLLoggingMeter)
 	if err != nil {
 		return fmt.Errorf("failed to create default allow multicast egress ACL: %v", err)
 	}
 
 	match = getACLMatch(clusterRtrPortGroupName, mcastMatch, knet.PolicyTypeIngress)
 	err = addACLPortGroup("", oc.clusterRtrPortGroupUUID, toLport,
-		defaultRoutedMcastAllowPriority, match, "allow", knet.PolicyTypeIngress, "")
+		defaultRoutedMcastAllowPriority, match, "allow", knet.PolicyTypeIngress, "", types.OvnACLLoggingMeter)
 	if err != nil {
 		return fmt.Errorf("failed to create default allow multicast ingress ACL: %v", err)
 	}
@@ -613,19 +760,23 @@ func (oc *Controller) handleLocalPodSelectorAddFunc(
 
 	oc.localPodAddDefaultDeny(nsInfo, policy, portInfo)
 
-	if np.portGroupUUID == "" {
+	if np.portGroup == nil {
 		return
 	}
 
-	_, stderr, err := util.RunOVNNbctl("--if-exists", "remove",
-		"port_group", np.portGroupUUID, "ports", portInfo.uuid, "--",
-		"add", "port_group", np.portGroupUUID, "ports", portInfo.uuid)
-	if err != nil {
-		klog.Errorf("Failed to add logicalPort %s to portGroup %s "+
-			"stderr: %q (%v)", logicalPort, np.portGroupUUID, stderr, err)
+	if err := oc.addToNetpolPortGroup(np.portGroup, portInfo); err != nil {
+		klog.Errorf(err.Error())
+		oc.localPodDelDefaultDeny(np, nsInfo, portInfo)
+		return
 	}
-
 	np.localPods[logicalPort] = portInfo
+
+	// Named ports of ingress rules are resolved against the selected pods
+	for _, gp := range np.ingressPolicies {
+		if err := gp.addNamedPortPod(pod); err != nil {
+			klog.Errorf(err.Error())
+		}
+	}
 }
 
 func (oc *Controller) handleLocalPodSelectorDelFunc(
@@ -658,21 +809,22 @@ func (oc *Controller) handleLocalPodSelectorDelFunc(
 	delete(np.localPods, logicalPort)
 	oc.localPodDelDefaultDeny(np, nsInfo, portInfo)
 
+	for _, gp := range np.ingressPolicies {
+		if err := gp.deleteNamedPortPod(pod); err != nil {
+			klog.Errorf(err.Error())
+		}
+	}
+
 	oc.lspMutex.Lock()
 	delete(oc.lspIngressDenyCache, logicalPort)
 	delete(oc.lspEgressDenyCache, logicalPort)
 	oc.lspMutex.Unlock()
 
-	if np.portGroupUUID == "" {
+	if np.portGroup == nil {
 		return
 	}
 
-	_, stderr, err := util.RunOVNNbctl("--if-exists", "remove",
-		"port_group", np.portGroupUUID, "ports", portInfo.uuid)
-	if err != nil {
-		klog.Errorf("Failed to delete logicalPort %s from portGroup %s "+
-			"stderr: %q (%v)", portInfo.uuid, np.portGroupUUID, stderr, err)
-	}
+	oc.deleteFromNetpolPortGroup(np.portGroup, portInfo)
 }
 
 func (oc *Controller) handleLocalPodSelector(
@@ -715,8 +867,8 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 
 	nsInfo, err := oc.waitForNamespaceLocked(policy.Namespace)
 	if err != nil {
-		klog.Errorf("Failed to wait for namespace %s event (%v)",
-			policy.Namespace, err)
+		oc.recordNetworkPolicyStatus(policy, 0, []error{fmt.Errorf("failed to wait for namespace %s event (%v)",
+			policy.Namespace, err)})
 		return
 	}
 	_, alreadyExists := nsInfo.networkPolicies[policy.Name]
@@ -725,36 +877,47 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 		return
 	}
 
+	// The errors which prevent enforcing the policy as specified, and the
+	// number of ACLs implementing its rules, are recorded once it is added
+	var aclCount int
+	var errs []error
+	defer func() {
+		oc.recordNetworkPolicyStatus(policy, aclCount, errs)
+	}()
+
 	np := NewNetworkPolicy(policy)
 
 	if len(nsInfo.networkPolicies) == 0 {
+		nsInfo.defaultDenyAudit = nsInfo.aclAudit || np.audit
 		err := oc.createDefaultDenyPortGroup(policy.Namespace, nsInfo, knet.PolicyTypeIngress, nsInfo.aclLogging.Deny)
 		if err != nil {
 			nsInfo.Unlock()
-			klog.Errorf(err.Error())
+			errs = append(errs, err)
 			return
 		}
 		err = oc.createDefaultDenyPortGroup(policy.Namespace, nsInfo, knet.PolicyTypeEgress, nsInfo.aclLogging.Deny)
 		if err != nil {
 			nsInfo.Unlock()
-			klog.Errorf(err.Error())
+			errs = append(errs, err)
 			return
 		}
 	}
 	nsInfo.networkPolicies[policy.Name] = np
+	if err := oc.setDefaultDenyAudit(policy.Namespace, nsInfo, nsInfo.getDefaultDenyAudit()); err != nil {
+		errs = append(errs, err)
+	}
 
 	nsInfo.Unlock()
 	np.Lock()
 
-	// Create a port group for the policy. All the pods that this policy
+	// Get the port group of the policy, shared with the policies of the
+	// namespace with the same pod selector. All the pods that this policy
 	// selects will be eventually added to this port group.
-	readableGroupName := fmt.Sprintf("%s_%s", policy.Namespace, policy.Name)
-	np.portGroupName = hashedPortGroup(readableGroupName)
-
-	np.portGroupUUID, err = createPortGroup(readableGroupName, np.portGroupName)
+	np.portGroup, err = oc.acquireNetpolPortGroup(policy.Namespace, &policy.Spec.PodSelector)
 	if err != nil {
-		klog.Errorf("Failed to create port_group for network policy %s in "+
-			"namespace %s", policy.Name, policy.Namespace)
+		np.Unlock()
+		errs = append(errs, fmt.Errorf("failed to create port_group for network policy %s in "+
+			"namespace %s: %v", policy.Name, policy.Namespace, err))
 		return
 	}
 
@@ -769,26 +932,62 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 		podSelector       *metav1.LabelSelector
 	}
 	var policyHandlers []policyHandler
+	// peer address sets created for this policy, whose handlers must be
+	// started
+	var addressSets []*netpolAddressSet
+	// namedPortHandler holds an egress rule with named ports and the
+	// namespace its destination pods are in ("" for any namespace)
+	type namedPortHandler struct {
+		gress     *gressPolicy
+		namespace string
+	}
+	var namedPortHandlers []namedPortHandler
+
+	ingressRules, egressRules := policy.Spec.Ingress, policy.Spec.Egress
+	if np.stateless {
+		if err := validateStatelessNetworkPolicy(policy); err != nil {
+			// Implementing the policy with stateful ACLs instead would not
+			// be what was asked for: the selected pods are isolated, but
+			// none of the policy's rules are applied.
+			errs = append(errs, fmt.Errorf("rejecting stateless network policy %s in namespace %s: %v",
+				policy.Name, policy.Namespace, err))
+			ingressRules, egressRules = nil, nil
+		}
+	}
+
 	// Go through each ingress rule.  For each ingress rule, create an
 	// addressSet for the peer pods.
-	for i, ingressJSON := range policy.Spec.Ingress {
+	for i, ingressJSON := range ingressRules {
 		klog.V(5).Infof("Network policy ingress is %+v", ingressJSON)
 
 		ingress := newGressPolicy(knet.PolicyTypeIngress, i, policy.Namespace, policy.Name)
+		ingress.stateless = np.stateless
 
 		// Each ingress rule can have multiple ports to which we allow traffic.
+		// A rule with a port that can't be implemented isn't applied, as
+		// ignoring the port would allow more traffic than intended.
+		validPorts := true
 		for _, portJSON := range ingressJSON.Ports {
-			ingress.addPortPolicy(&portJSON)
+			if err := ingress.addPortPolicy(&portJSON); err != nil {
+				errs = append(errs, err)
+				validPorts = false
+			}
+		}
+		if !validPorts {
+			continue
 		}
 
 		if hasAnyLabelSelector(ingressJSON.From) {
 			klog.V(5).Infof("Network policy %s with ingress rule %s has a selector", policy.Name, ingress.policyName)
-			if err := ingress.ensurePeerAddressSet(oc.addressSetFactory); err != nil {
-				klog.Errorf(err.Error())
+			as, created, err := oc.acquireNetpolAddressSet(policy.Namespace, knet.PolicyTypeIngress, ingressJSON.From)
+			if err != nil {
+				errs = append(errs, err)
 				continue
 			}
-			// Start service handlers ONLY if there's an ingress Address Set
-			oc.handlePeerService(policy, ingress, np)
+			ingress.setPeerAddressSet(as)
+			if created {
+				addressSets = append(addressSets, as)
+			}
 		}
 
 		for _, fromJSON := range ingressJSON.From {
@@ -803,28 +1002,47 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 				podSelector:       fromJSON.PodSelector,
 			})
 		}
-		ingress.localPodAddACL(np.portGroupName, np.portGroupUUID, nsInfo.aclLogging.Allow)
+		count, err := ingress.localPodAddACL(np.portGroup.name, np.portGroup.uuid, nsInfo.aclLogging.Allow, nsInfo.getACLLoggingMeter())
+		if err != nil {
+			errs = append(errs, err)
+		}
+		aclCount += count
 		np.ingressPolicies = append(np.ingressPolicies, ingress)
 	}
 
 	// Go through each egress rule.  For each egress rule, create an
 	// addressSet for the peer pods.
-	for i, egressJSON := range policy.Spec.Egress {
+	for i, egressJSON := range egressRules {
 		klog.V(5).Infof("Network policy egress is %+v", egressJSON)
 
 		egress := newGressPolicy(knet.PolicyTypeEgress, i, policy.Namespace, policy.Name)
+		egress.stateless = np.stateless
 
 		// Each egress rule can have multiple ports to which we allow traffic.
+		// A rule with a port that can't be implemented isn't applied, as
+		// ignoring the port would allow more traffic than intended.
+		validPorts := true
 		for _, portJSON := range egressJSON.Ports {
-			egress.addPortPolicy(&portJSON)
+			if err := egress.addPortPolicy(&portJSON); err != nil {
+				errs = append(errs, err)
+				validPorts = false
+			}
+		}
+		if !validPorts {
+			continue
 		}
 
 		if hasAnyLabelSelector(egressJSON.To) {
 			klog.V(5).Infof("Network policy %s with egress rule %s has a selector", policy.Name, egress.policyName)
-			if err := egress.ensurePeerAddressSet(oc.addressSetFactory); err != nil {
-				klog.Errorf(err.Error())
+			as, created, err := oc.acquireNetpolAddressSet(policy.Namespace, knet.PolicyTypeEgress, egressJSON.To)
+			if err != nil {
+				errs = append(errs, err)
 				continue
 			}
+			egress.setPeerAddressSet(as)
+			if created {
+				addressSets = append(addressSets, as)
+			}
 		}
 
 		for _, toJSON := range egressJSON.To {
@@ -839,8 +1057,36 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 				podSelector:       toJSON.PodSelector,
 			})
 		}
-		egress.localPodAddACL(np.portGroupName, np.portGroupUUID, nsInfo.aclLogging.Allow)
+		count, err := egress.localPodAddACL(np.portGroup.name, np.portGroup.uuid, nsInfo.aclLogging.Allow, nsInfo.getACLLoggingMeter())
+		if err != nil {
+			errs = append(errs, err)
+		}
+		aclCount += count
 		np.egressPolicies = append(np.egressPolicies, egress)
+
+		// Named ports of egress rules are resolved against the peer pods.
+		// Destination pods are watched irrespective of their labels as the
+		// peer match of the ACL restricts them to the selected ones.
+		if egress.hasNamedPorts() && len(egressJSON.To) > 0 && !hasAnyLabelSelector(egressJSON.To) {
+			errs = append(errs, fmt.Errorf("named ports of egress rule %d of network policy %s/%s are not "+
+				"applied: they are resolved against the peer pods and the rule only has ipBlock peers",
+				i, policy.Namespace, policy.Name))
+		}
+		if egress.hasNamedPorts() && (len(egressJSON.To) == 0 || hasAnyLabelSelector(egressJSON.To)) {
+			namespace := policy.Namespace
+			if len(egressJSON.To) == 0 {
+				namespace = ""
+			}
+			for _, toJSON := range egressJSON.To {
+				if toJSON.NamespaceSelector != nil {
+					namespace = ""
+				}
+			}
+			namedPortHandlers = append(namedPortHandlers, namedPortHandler{
+				gress:     egress,
+				namespace: namespace,
+			})
+		}
 	}
 	np.Unlock()
 
@@ -848,26 +1094,66 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 	// effects, add them to the port group.
 	oc.handleLocalPodSelector(policy, np, nsInfo)
 
+	// The peer pods are added to the address sets shared with the rules of
+	// the other policies of the namespace with the same peers by the handlers
+	// of the address sets, started by the first policy using them
+	for _, as := range addressSets {
+		oc.startNetpolAddressSetHandlers(as)
+	}
+
 	for _, handler := range policyHandlers {
-		if handler.namespaceSelector != nil && handler.podSelector != nil {
-			// For each rule that contains both peer namespace selector and
-			// peer pod selector, we create a watcher for each matching namespace
-			// that populates the addressSet
-			oc.handlePeerNamespaceAndPodSelector(policy,
-				handler.namespaceSelector, handler.podSelector,
-				handler.gress, np)
-		} else if handler.namespaceSelector != nil {
+		if handler.namespaceSelector != nil && handler.podSelector == nil {
 			// For each peer namespace selector, we create a watcher that
 			// populates ingress.peerAddressSets
 			oc.handlePeerNamespaceSelector(policy,
 				handler.namespaceSelector, handler.gress, np)
-		} else if handler.podSelector != nil {
-			// For each peer pod selector, we create a watcher that
-			// populates the addressSet
-			oc.handlePeerPodSelector(policy, handler.podSelector,
-				handler.gress, np)
 		}
 	}
+
+	for _, handler := range namedPortHandlers {
+		oc.handleNamedPortPods(handler.namespace, handler.gress, np)
+	}
+}
+
+// recordNetworkPolicyStatus records the number of ACLs implementing the rules
+// of a network policy, and whether it is fully enforced. The errors which
+// prevent enforcing it as specified are logged and posted as an event.
+func (oc *Controller) recordNetworkPolicyStatus(policy *knet.NetworkPolicy, aclCount int, errs []error) {
+	key := policy.Namespace + "/" + policy.Name
+
+	oc.netpolStatusMutex.Lock()
+	defer oc.netpolStatusMutex.Unlock()
+
+	metrics.RecordNetworkPolicyACLCount(policy.Namespace, policy.Name, aclCount)
+	if len(errs) == 0 {
+		oc.failedNetworkPolicies.Delete(key)
+		metrics.RecordNetworkPolicyFailedCount(oc.failedNetworkPolicies.Len())
+		return
+	}
+	oc.failedNetworkPolicies.Insert(key)
+	metrics.RecordNetworkPolicyFailedCount(oc.failedNetworkPolicies.Len())
+
+	err := kerrors.NewAggregate(errs)
+	klog.Errorf("Failed to add network policy %s: %v", key, err)
+	policyRef := kapi.ObjectReference{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "NetworkPolicy",
+		Namespace:  policy.Namespace,
+		Name:       policy.Name,
+		UID:        policy.UID,
+	}
+	oc.recorder.Eventf(&policyRef, kapi.EventTypeWarning, "ErrorAddingNetworkPolicy",
+		"The network policy is not enforced as specified: %v", err)
+}
+
+// clearNetworkPolicyStatus removes the status of a deleted network policy
+func (oc *Controller) clearNetworkPolicyStatus(namespace, name string) {
+	oc.netpolStatusMutex.Lock()
+	defer oc.netpolStatusMutex.Unlock()
+
+	metrics.DeleteNetworkPolicyACLCount(namespace, name)
+	oc.failedNetworkPolicies.Delete(namespace + "/" + name)
+	metrics.RecordNetworkPolicyFailedCount(oc.failedNetworkPolicies.Len())
 }
 
 func (oc *Controller) deleteNetworkPolicy(policy *knet.NetworkPolicy) {
@@ -878,16 +1164,24 @@ func (oc *Controller) deleteNetworkPolicy(policy *knet.NetworkPolicy) {
 	if nsInfo == nil {
 		klog.V(5).Infof("Failed to get namespace lock when deleting policy %s in namespace %s",
 			policy.Name, policy.Namespace)
+		oc.clearNetworkPolicyStatus(policy.Namespace, policy.Name)
 		return
 	}
 	defer nsInfo.Unlock()
 
 	np := nsInfo.networkPolicies[policy.Name]
 	if np == nil {
+		// the policy may have failed before it was added
+		oc.clearNetworkPolicyStatus(policy.Namespace, policy.Name)
 		return
 	}
 
 	delete(nsInfo.networkPolicies, policy.Name)
+	if len(nsInfo.networkPolicies) > 0 {
+		if err := oc.setDefaultDenyAudit(policy.Namespace, nsInfo, nsInfo.getDefaultDenyAudit()); err != nil {
+			klog.Errorf(err.Error())
+		}
+	}
 
 	oc.destroyNetworkPolicy(np, nsInfo)
 }
@@ -896,7 +1190,9 @@ func (oc *Controller) destroyNetworkPolicy(np *networkPolicy, nsInfo *namespaceI
 	np.Lock()
 	defer np.Unlock()
 	np.deleted = true
+	oc.clearNetworkPolicyStatus(np.namespace, np.name)
 	oc.shutdownHandlers(np)
+	oc.deleteNamedPortPolicies(np)
 
 	for _, portInfo := range np.localPods {
 		oc.localPodDelDefaultDeny(np, nsInfo, portInfo)
@@ -907,17 +1203,19 @@ func (oc *Controller) destroyNetworkPolicy(np *networkPolicy, nsInfo *namespaceI
 		deletePortGroup(defaultDenyPortGroup(np.namespace, "egressDefaultDeny"))
 	}
 
-	// Delete the port group
-	deletePortGroup(np.portGroupName)
+	// Release the port group, deleting it if no other policy uses it
+	if np.portGroup != nil {
+		oc.releaseNetpolPortGroup(np)
+	}
 
-	// Delete ingress/egress address sets
+	// Release ingress/egress address sets
 	for _, policy := range np.ingressPolicies {
-		if err := policy.destroy(); err != nil {
+		if err := oc.releaseGressPolicyAddressSet(policy); err != nil {
 			klog.Errorf(err.Error())
 		}
 	}
 	for _, policy := range np.egressPolicies {
-		if err := policy.destroy(); err != nil {
+		if err := oc.releaseGressPolicyAddressSet(policy); err != nil {
 			klog.Errorf(err.Error())
 		}
 	}
@@ -926,12 +1224,12 @@ func (oc *Controller) destroyNetworkPolicy(np *networkPolicy, nsInfo *namespaceI
 // handlePeerPodSelectorAddUpdate adds the IP address of a pod that has been
 // selected as a peer by a NetworkPolicy's ingress/egress section to that
 // ingress/egress address set
-func (oc *Controller) handlePeerPodSelectorAddUpdate(gp *gressPolicy, obj interface{}) {
+func (oc *Controller) handlePeerPodSelectorAddUpdate(as *netpolAddressSet, obj interface{}) {
 	pod := obj.(*kapi.Pod)
 	if pod.Spec.NodeName == "" {
 		return
 	}
-	if err := gp.addPeerPod(pod); err != nil {
+	if err := as.addPeerPod(pod); err != nil {
 		klog.Errorf(err.Error())
 	}
 }
@@ -939,49 +1237,47 @@ func (oc *Controller) handlePeerPodSelectorAddUpdate(gp *gressPolicy, obj interf
 // handlePeerPodSelectorDelete removes the IP address of a pod that no longer
 // matches a NetworkPolicy ingress/egress section's selectors from that
 // ingress/egress address set
-func (oc *Controller) handlePeerPodSelectorDelete(gp *gressPolicy, obj interface{}) {
+func (oc *Controller) handlePeerPodSelectorDelete(as *netpolAddressSet, obj interface{}) {
 	pod := obj.(*kapi.Pod)
 	if pod.Spec.NodeName == "" {
 		return
 	}
-	if err := gp.deletePeerPod(pod); err != nil {
+	if err := as.deletePeerPod(pod); err != nil {
 		klog.Errorf(err.Error())
 	}
 }
 
 // handlePeerServiceSelectorAddUpdate adds the VIP of a service that selects
 // pods that are selected by the Network Policy
-func (oc *Controller) handlePeerServiceAdd(gp *gressPolicy, obj interface{}) {
+func (oc *Controller) handlePeerServiceAdd(as *netpolAddressSet, obj interface{}) {
 	service := obj.(*kapi.Service)
-	klog.V(5).Infof("A Service: %s matches the namespace as the gress policy: %s", service.Name, gp.policyName)
-	if err := gp.addPeerSvcVip(service); err != nil {
+	klog.V(5).Infof("A Service: %s matches the namespace of the address set: %s", service.Name, as.name)
+	if err := as.addPeerSvcVip(service); err != nil {
 		klog.Errorf(err.Error())
 	}
 }
 
 // handlePeerServiceDelete removes the VIP of a service that selects
 // pods that are selected by the Network Policy
-func (oc *Controller) handlePeerServiceDelete(gp *gressPolicy, obj interface{}) {
+func (oc *Controller) handlePeerServiceDelete(as *netpolAddressSet, obj interface{}) {
 	service := obj.(*kapi.Service)
-	if err := gp.deletePeerSvcVip(service); err != nil {
+	if err := as.deletePeerSvcVip(service); err != nil {
 		klog.Errorf(err.Error())
 	}
 }
 
 // Watch Services that are in the same Namespace as the NP
 // To account for hairpined traffic
-func (oc *Controller) handlePeerService(
-	policy *knet.NetworkPolicy, gp *gressPolicy, np *networkPolicy) {
-
-	h := oc.watchFactory.AddFilteredServiceHandler(policy.Namespace,
+func (oc *Controller) handlePeerService(as *netpolAddressSet) {
+	h := oc.watchFactory.AddFilteredServiceHandler(as.namespace,
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				// Service is matched so add VIP to addressSet
-				oc.handlePeerServiceAdd(gp, obj)
+				oc.handlePeerServiceAdd(as, obj)
 			},
 			DeleteFunc: func(obj interface{}) {
 				// If Service that has matched pods are deleted remove VIP
-				oc.handlePeerServiceDelete(gp, obj)
+				oc.handlePeerServiceDelete(as, obj)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				// If Service Is updated make sure same pods are still matched
@@ -997,41 +1293,141 @@ func (oc *Controller) handlePeerService(
 					return
 				}
 
-				oc.handlePeerServiceDelete(gp, oldObj)
-				oc.handlePeerServiceAdd(gp, newObj)
+				oc.handlePeerServiceDelete(as, oldObj)
+				oc.handlePeerServiceAdd(as, newObj)
 			},
 		}, nil)
-	np.svcHandlerList = append(np.svcHandlerList, h)
+	oc.addNetpolAddressSetHandler(as, h, &as.svcHandlerList, oc.watchFactory.RemoveServiceHandler)
 }
 
-func (oc *Controller) handlePeerPodSelector(
-	policy *knet.NetworkPolicy, podSelector *metav1.LabelSelector,
-	gp *gressPolicy, np *networkPolicy) {
-
+func (oc *Controller) handlePeerPodSelector(podSelector *metav1.LabelSelector, as *netpolAddressSet) {
 	// NetworkPolicy is validated by the apiserver; this can't fail.
 	sel, _ := metav1.LabelSelectorAsSelector(podSelector)
 
-	h := oc.watchFactory.AddFilteredPodHandler(policy.Namespace, sel,
+	h := oc.watchFactory.AddFilteredPodHandler(as.namespace, sel,
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
-				oc.handlePeerPodSelectorAddUpdate(gp, obj)
+				oc.handlePeerPodSelectorAddUpdate(as, obj)
 			},
 			DeleteFunc: func(obj interface{}) {
-				oc.handlePeerPodSelectorDelete(gp, obj)
+				oc.handlePeerPodSelectorDelete(as, obj)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
-				oc.handlePeerPodSelectorAddUpdate(gp, newObj)
+				oc.handlePeerPodSelectorAddUpdate(as, newObj)
 			},
 		}, nil)
-	np.podHandlerList = append(np.podHandlerList, h)
+	oc.addNetpolAddressSetHandler(as, h, &as.podHandlerList, oc.watchFactory.RemovePodHandler)
+}
+
+// namedPortPolicy is an egress rule with named ports of a network policy, and
+// the namespace its destination pods are in ("" for any namespace)
+type namedPortPolicy struct {
+	np        *networkPolicy
+	namespace string
+}
+
+// handleNamedPortPods resolves the named ports of an egress rule against the
+// pods in the given namespace ("" for all namespaces). The pods are watched by
+// a single handler shared by the rules with named ports of all the policies.
+func (oc *Controller) handleNamedPortPods(namespace string, gp *gressPolicy, np *networkPolicy) {
+	oc.namedPortHandlerOnce.Do(func() {
+		oc.watchFactory.AddPodHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				for gp, np := range oc.getNamedPortPolicies(pod) {
+					oc.handleNamedPortPodAddUpdate(gp, np, pod)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				for gp, np := range oc.getNamedPortPolicies(pod) {
+					oc.handleNamedPortPodDelete(gp, np, pod)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				pod := newObj.(*kapi.Pod)
+				for gp, np := range oc.getNamedPortPolicies(pod) {
+					oc.handleNamedPortPodAddUpdate(gp, np, pod)
+				}
+			},
+		}, nil)
+	})
+
+	oc.namedPortMutex.Lock()
+	oc.namedPortPolicies[gp] = namedPortPolicy{np: np, namespace: namespace}
+	oc.namedPortMutex.Unlock()
+
+	// The shared handler only sees the pods changing from now on
+	pods, err := oc.watchFactory.GetPods(namespace)
+	if err != nil {
+		klog.Errorf("Failed to get the pods resolving the named ports of network policy %s/%s: %v",
+			np.namespace, np.name, err)
+		return
+	}
+	for _, pod := range pods {
+		oc.handleNamedPortPodAddUpdate(gp, np, pod)
+	}
+}
+
+// getNamedPortPolicies returns the egress rules with named ports whose
+// destination pods may include the given pod
+func (oc *Controller) getNamedPortPolicies(pod *kapi.Pod) map[*gressPolicy]*networkPolicy {
+	oc.namedPortMutex.Lock()
+	defer oc.namedPortMutex.Unlock()
+	policies := make(map[*gressPolicy]*networkPolicy)
+	for gp, p := range oc.namedPortPolicies {
+		if p.namespace == "" || p.namespace == pod.Namespace {
+			policies[gp] = p.np
+		}
+	}
+	return policies
+}
+
+// deleteNamedPortPolicies stops resolving the named ports of the egress rules
+// of a network policy
+func (oc *Controller) deleteNamedPortPolicies(np *networkPolicy) {
+	oc.namedPortMutex.Lock()
+	defer oc.namedPortMutex.Unlock()
+	for _, gp := range np.egressPolicies {
+		delete(oc.namedPortPolicies, gp)
+	}
+}
+
+// handleNamedPortPodAddUpdate adds a pod that resolves one of the named ports
+// of an egress rule to the rule's named port ACLs
+func (oc *Controller) handleNamedPortPodAddUpdate(gp *gressPolicy, np *networkPolicy, obj interface{}) {
+	pod := obj.(*kapi.Pod)
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	np.Lock()
+	defer np.Unlock()
+	if np.deleted {
+		return
+	}
+	if err := gp.addNamedPortPod(pod); err != nil {
+		klog.V(5).Infof("Unable to resolve named ports of pod %s/%s for network policy %s: %v",
+			pod.Namespace, pod.Name, np.name, err)
+	}
+}
+
+// handleNamedPortPodDelete removes a deleted pod from the named port ACLs of
+// an egress rule
+func (oc *Controller) handleNamedPortPodDelete(gp *gressPolicy, np *networkPolicy, pod *kapi.Pod) {
+	np.Lock()
+	defer np.Unlock()
+	if np.deleted {
+		return
+	}
+	if err := gp.deleteNamedPortPod(pod); err != nil {
+		klog.Errorf(err.Error())
+	}
 }
 
 func (oc *Controller) handlePeerNamespaceAndPodSelector(
-	policy *knet.NetworkPolicy,
 	namespaceSelector *metav1.LabelSelector,
 	podSelector *metav1.LabelSelector,
-	gp *gressPolicy,
-	np *networkPolicy) {
+	as *netpolAddressSet) {
 
 	// NetworkPolicy is validated by the apiserver; this can't fail.
 	nsSel, _ := metav1.LabelSelectorAsSelector(namespaceSelector)
@@ -1041,9 +1437,9 @@ func (oc *Controller) handlePeerNamespaceAndPodSelector(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				namespace := obj.(*kapi.Namespace)
-				np.Lock()
-				alreadyDeleted := np.deleted
-				np.Unlock()
+				as.Lock()
+				alreadyDeleted := as.deleted
+				as.Unlock()
 				if alreadyDeleted {
 					return
 				}
@@ -1053,22 +1449,16 @@ func (oc *Controller) handlePeerNamespaceAndPodSelector(
 				podHandler := oc.watchFactory.AddFilteredPodHandler(namespace.Name, podSel,
 					cache.ResourceEventHandlerFuncs{
 						AddFunc: func(obj interface{}) {
-							oc.handlePeerPodSelectorAddUpdate(gp, obj)
+							oc.handlePeerPodSelectorAddUpdate(as, obj)
 						},
 						DeleteFunc: func(obj interface{}) {
-							oc.handlePeerPodSelectorDelete(gp, obj)
+							oc.handlePeerPodSelectorDelete(as, obj)
 						},
 						UpdateFunc: func(oldObj, newObj interface{}) {
-							oc.handlePeerPodSelectorAddUpdate(gp, newObj)
+							oc.handlePeerPodSelectorAddUpdate(as, newObj)
 						},
 					}, nil)
-				np.Lock()
-				defer np.Unlock()
-				if np.deleted {
-					oc.watchFactory.RemovePodHandler(podHandler)
-					return
-				}
-				np.podHandlerList = append(np.podHandlerList, podHandler)
+				oc.addNetpolAddressSetHandler(as, podHandler, &as.podHandlerList, oc.watchFactory.RemovePodHandler)
 			},
 			DeleteFunc: func(obj interface{}) {
 				// when the namespace labels no longer apply
@@ -1077,14 +1467,14 @@ func (oc *Controller) handlePeerNamespaceAndPodSelector(
 				pods, _ := oc.watchFactory.GetPods(namespace.Name)
 
 				for _, pod := range pods {
-					oc.handlePeerPodSelectorDelete(gp, pod)
+					oc.handlePeerPodSelectorDelete(as, pod)
 				}
 
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
 			},
 		}, nil)
-	np.nsHandlerList = append(np.nsHandlerList, namespaceHandler)
+	oc.addNetpolAddressSetHandler(as, namespaceHandler, &as.nsHandlerList, oc.watchFactory.RemoveNamespaceHandler)
 }
 
 func (oc *Controller) handlePeerNamespaceSelector(
@@ -1102,7 +1492,7 @@ func (oc *Controller) handlePeerNamespaceSelector(
 				np.Lock()
 				defer np.Unlock()
 				if !np.deleted {
-					gress.addNamespaceAddressSet(namespace.Name, np.portGroupName)
+					gress.addNamespaceAddressSet(namespace.Name, np.portGroup.name)
 				}
 			},
 			DeleteFunc: func(obj interface{}) {
@@ -1110,7 +1500,7 @@ func (oc *Controller) handlePeerNamespaceSelector(
 				np.Lock()
 				defer np.Unlock()
 				if !np.deleted {
-					gress.delNamespaceAddressSet(namespace.Name, np.portGroupName)
+					gress.delNamespaceAddressSet(namespace.Name, np.portGroup.name)
 				}
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
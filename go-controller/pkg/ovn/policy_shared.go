@@ -0,0 +1,481 @@
+package ovn
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// netpolSharedPrefix prefixes the hash of the selectors in the names of the
+// port groups and address sets shared by the network policies of a namespace.
+// Network policy names can't contain "_", which tells the shared names from
+// the per policy names of earlier versions.
+const netpolSharedPrefix = "netpol_"
+
+// netpolPortGroup is the port group of the pods selected by the network
+// policies of a namespace with the same pod selector, holding the ACLs of
+// all these policies
+type netpolPortGroup struct {
+	// readable name of the port group, also its key in netpolPortGroups
+	key  string
+	name string
+	uuid string
+	// number of network policies using the port group
+	refs int
+	// for each logical port, the number of network policies selecting it
+	ports map[string]int
+}
+
+// netpolAddressSet is the address set of the peer pods of the rules of the
+// network policies of a namespace with the same peers
+type netpolAddressSet struct {
+	sync.Mutex
+	// name of the address set, also its key in netpolAddressSets
+	name      string
+	namespace string
+	// peers with a pod selector, whose pods are added to the address set
+	peers []knet.NetworkPolicyPeer
+	// services adds the VIPs of the services of the namespace to the address
+	// set, to account for the hairpinned traffic of ingress rules
+	services   bool
+	addressSet addressset.AddressSet
+	// number of network policy rules using the address set
+	refs           int
+	podHandlerList []*factory.Handler
+	svcHandlerList []*factory.Handler
+	nsHandlerList  []*factory.Handler
+	deleted        bool
+}
+
+// getNetpolPortGroupName returns the readable name of the port group of the
+// pods of a namespace matching a network policy pod selector
+func getNetpolPortGroupName(namespace string, podSelector *metav1.LabelSelector) string {
+	// NetworkPolicy is validated by the apiserver; this can't fail.
+	sel, _ := metav1.LabelSelectorAsSelector(podSelector)
+	return fmt.Sprintf("%s_%s%s", namespace, netpolSharedPrefix, util.HashForOVN(sel.String()))
+}
+
+// getNetpolAddressSetName returns the name of the address set of the peer
+// pods of a network policy rule. It only depends on the normalized selectors
+// of the peers, so that the rules of the policies of a namespace with the
+// same peers get the same address set.
+func getNetpolAddressSetName(namespace string, policyType knet.PolicyType, peers []knet.NetworkPolicyPeer) string {
+	selectors := sets.NewString()
+	for _, peer := range peers {
+		if peer.PodSelector == nil {
+			continue
+		}
+		// NetworkPolicy is validated by the apiserver; this can't fail.
+		podSel, _ := metav1.LabelSelectorAsSelector(peer.PodSelector)
+		if peer.NamespaceSelector == nil {
+			selectors.Insert(fmt.Sprintf("pods(%s)", podSel))
+		} else {
+			nsSel, _ := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+			selectors.Insert(fmt.Sprintf("namespaces(%s)pods(%s)", nsSel, podSel))
+		}
+	}
+	key := strings.Join(selectors.List(), ";")
+	if policyType == knet.PolicyTypeIngress {
+		key = "services;" + key
+	}
+	return fmt.Sprintf("%s.%s%s", namespace, netpolSharedPrefix, util.HashForOVN(key))
+}
+
+// getNetpolSharedNames returns the hashed names of the port groups and the
+// names of the address sets used by the given network policies
+func getNetpolSharedNames(policies []*knet.NetworkPolicy) (sets.String, sets.String) {
+	portGroups := sets.NewString()
+	addressSets := sets.NewString()
+	for _, policy := range policies {
+		portGroups.Insert(hashedPortGroup(getNetpolPortGroupName(policy.Namespace, &policy.Spec.PodSelector)))
+		for _, ingress := range policy.Spec.Ingress {
+			if hasAnyLabelSelector(ingress.From) {
+				addressSets.Insert(getNetpolAddressSetName(policy.Namespace, knet.PolicyTypeIngress, ingress.From))
+			}
+		}
+		for _, egress := range policy.Spec.Egress {
+			if hasAnyLabelSelector(egress.To) {
+				addressSets.Insert(getNetpolAddressSetName(policy.Namespace, knet.PolicyTypeEgress, egress.To))
+			}
+		}
+	}
+	return portGroups, addressSets
+}
+
+// acquireNetpolPortGroup returns the port group of the pods selected by a
+// network policy, creating it for the first policy of the namespace with this
+// pod selector
+func (oc *Controller) acquireNetpolPortGroup(namespace string, podSelector *metav1.LabelSelector) (*netpolPortGroup, error) {
+	readableName := getNetpolPortGroupName(namespace, podSelector)
+
+	oc.netpolSharedMutex.Lock()
+	defer oc.netpolSharedMutex.Unlock()
+
+	pg := oc.netpolPortGroups[readableName]
+	if pg == nil {
+		name := hashedPortGroup(readableName)
+		uuid, err := createPortGroup(readableName, name)
+		if err != nil {
+			return nil, err
+		}
+		pg = &netpolPortGroup{
+			key:   readableName,
+			name:  name,
+			uuid:  uuid,
+			ports: make(map[string]int),
+		}
+		oc.netpolPortGroups[readableName] = pg
+	}
+	pg.refs++
+	return pg, nil
+}
+
+// releaseNetpolPortGroup removes a network policy from its port group. The
+// port group is deleted with its last policy, otherwise the ACLs of the
+// policy and the ports only it selects are removed from the port group.
+// Caller must hold the policy's lock.
+func (oc *Controller) releaseNetpolPortGroup(np *networkPolicy) {
+	pg := np.portGroup
+
+	oc.netpolSharedMutex.Lock()
+	defer oc.netpolSharedMutex.Unlock()
+
+	pg.refs--
+	if pg.refs == 0 {
+		delete(oc.netpolPortGroups, pg.key)
+		deletePortGroup(pg.name)
+		return
+	}
+
+	for _, portInfo := range np.localPods {
+		pg.deletePort(portInfo)
+	}
+
+	uuids, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading",
+		"--columns=_uuid", "find", "ACL",
+		fmt.Sprintf("external-ids:namespace=%s", np.namespace),
+		fmt.Sprintf("external-ids:policy=%s", np.name))
+	if err != nil {
+		klog.Errorf("Find failed to get the ACLs of network policy %s in namespace %s, "+
+			"stderr: %q (%v)", np.name, np.namespace, stderr, err)
+		return
+	}
+	if uuids == "" {
+		return
+	}
+	args := append([]string{"remove", "port_group", pg.uuid, "acls"}, strings.Fields(uuids)...)
+	if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+		klog.Errorf("Failed to remove the ACLs of network policy %s in namespace %s from "+
+			"port group %s, stderr: %q (%v)", np.name, np.namespace, pg.key, stderr, err)
+	}
+}
+
+// addPort adds a logical port to the port group for one more network policy.
+// Caller must hold netpolSharedMutex.
+func (pg *netpolPortGroup) addPort(portInfo *lpInfo) error {
+	if pg.ports[portInfo.name] == 0 {
+		if err := addToPortGroup(pg.uuid, portInfo); err != nil {
+			return err
+		}
+	}
+	pg.ports[portInfo.name]++
+	return nil
+}
+
+// deletePort removes a logical port from the port group for one network
+// policy, the port leaves the port group with the last policy selecting it.
+// Caller must hold netpolSharedMutex.
+func (pg *netpolPortGroup) deletePort(portInfo *lpInfo) {
+	if pg.ports[portInfo.name] == 0 {
+		return
+	}
+	pg.ports[portInfo.name]--
+	if pg.ports[portInfo.name] > 0 {
+		return
+	}
+	delete(pg.ports, portInfo.name)
+	if err := deleteFromPortGroup(pg.uuid, portInfo); err != nil {
+		klog.Errorf(err.Error())
+	}
+}
+
+// addToNetpolPortGroup adds a logical port selected by a network policy to
+// the policy's port group
+func (oc *Controller) addToNetpolPortGroup(pg *netpolPortGroup, portInfo *lpInfo) error {
+	oc.netpolSharedMutex.Lock()
+	defer oc.netpolSharedMutex.Unlock()
+	return pg.addPort(portInfo)
+}
+
+// deleteFromNetpolPortGroup removes a logical port no longer selected by a
+// network policy from the policy's port group
+func (oc *Controller) deleteFromNetpolPortGroup(pg *netpolPortGroup, portInfo *lpInfo) {
+	oc.netpolSharedMutex.Lock()
+	defer oc.netpolSharedMutex.Unlock()
+	pg.deletePort(portInfo)
+}
+
+// acquireNetpolAddressSet returns the address set of the peer pods of a
+// network policy rule, creating it for the first rule of the namespace with
+// these peers. created is true when the address set was created, in which
+// case the caller must start its handlers with startNetpolAddressSetHandlers.
+func (oc *Controller) acquireNetpolAddressSet(namespace string, policyType knet.PolicyType,
+	peers []knet.NetworkPolicyPeer) (as *netpolAddressSet, created bool, err error) {
+	name := getNetpolAddressSetName(namespace, policyType, peers)
+
+	oc.netpolSharedMutex.Lock()
+	defer oc.netpolSharedMutex.Unlock()
+
+	if as = oc.netpolAddressSets[name]; as != nil {
+		as.refs++
+		return as, false, nil
+	}
+
+	addressSet, err := oc.addressSetFactory.NewAddressSet(name, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	as = &netpolAddressSet{
+		name:           name,
+		namespace:      namespace,
+		services:       policyType == knet.PolicyTypeIngress,
+		addressSet:     addressSet,
+		refs:           1,
+		podHandlerList: make([]*factory.Handler, 0),
+		svcHandlerList: make([]*factory.Handler, 0),
+		nsHandlerList:  make([]*factory.Handler, 0),
+	}
+	for _, peer := range peers {
+		if peer.PodSelector != nil {
+			as.peers = append(as.peers, peer)
+		}
+	}
+	oc.netpolAddressSets[name] = as
+	return as, true, nil
+}
+
+// startNetpolAddressSetHandlers starts the handlers adding the peer pods, and
+// the services for ingress rules, to an address set. It must be called
+// without holding any network policy lock, as the handlers of the existing
+// objects are called synchronously.
+func (oc *Controller) startNetpolAddressSetHandlers(as *netpolAddressSet) {
+	if as.services {
+		oc.handlePeerService(as)
+	}
+	for _, peer := range as.peers {
+		if peer.NamespaceSelector != nil {
+			// For each peer that contains both namespace selector and pod
+			// selector, we create a watcher for each matching namespace that
+			// populates the address set
+			oc.handlePeerNamespaceAndPodSelector(peer.NamespaceSelector, peer.PodSelector, as)
+		} else {
+			// For each peer pod selector, we create a watcher that populates
+			// the address set
+			oc.handlePeerPodSelector(peer.PodSelector, as)
+		}
+	}
+}
+
+// releaseNetpolAddressSet releases the address set of a network policy rule,
+// the address set is destroyed with the last rule using it
+func (oc *Controller) releaseNetpolAddressSet(as *netpolAddressSet) error {
+	oc.netpolSharedMutex.Lock()
+	defer oc.netpolSharedMutex.Unlock()
+
+	as.refs--
+	if as.refs > 0 {
+		return nil
+	}
+	delete(oc.netpolAddressSets, as.name)
+
+	as.Lock()
+	defer as.Unlock()
+	as.deleted = true
+	for _, handler := range as.podHandlerList {
+		oc.watchFactory.RemovePodHandler(handler)
+	}
+	for _, handler := range as.nsHandlerList {
+		oc.watchFactory.RemoveNamespaceHandler(handler)
+	}
+	for _, handler := range as.svcHandlerList {
+		oc.watchFactory.RemoveServiceHandler(handler)
+	}
+	return as.addressSet.Destroy()
+}
+
+// releaseGressPolicyAddressSet releases the peer address set of a rule, if
+// it has one
+func (oc *Controller) releaseGressPolicyAddressSet(gp *gressPolicy) error {
+	if gp.peerAddressSet == nil {
+		return nil
+	}
+	as := gp.peerAddressSet
+	gp.peerAddressSet = nil
+	return oc.releaseNetpolAddressSet(as)
+}
+
+// addPeerPod adds the IPs of a peer pod to the address set
+func (as *netpolAddressSet) addPeerPod(pod *kapi.Pod) error {
+	ips, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		return err
+	}
+	as.Lock()
+	defer as.Unlock()
+	if as.deleted {
+		return nil
+	}
+	return as.addressSet.AddIPs(ips)
+}
+
+// deletePeerPod removes the IPs of a peer pod from the address set
+func (as *netpolAddressSet) deletePeerPod(pod *kapi.Pod) error {
+	ips, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		return err
+	}
+	as.Lock()
+	defer as.Unlock()
+	if as.deleted {
+		return nil
+	}
+	return as.addressSet.DeleteIPs(ips)
+}
+
+// addPeerSvcVip adds the VIPs of a service of the namespace to the address set
+func (as *netpolAddressSet) addPeerSvcVip(service *kapi.Service) error {
+	klog.V(5).Infof("Service %s is applied to same namespace as network Policy, finding Service VIPs", service.Name)
+	ips := getSvcVips(service)
+
+	as.Lock()
+	defer as.Unlock()
+	if as.deleted {
+		return nil
+	}
+	klog.V(5).Infof("Adding SVC clusterIP to address set %s: %v", as.name, ips)
+	return as.addressSet.AddIPs(ips)
+}
+
+// deletePeerSvcVip removes the VIPs of a service of the namespace from the
+// address set
+func (as *netpolAddressSet) deletePeerSvcVip(service *kapi.Service) error {
+	klog.V(5).Infof("Service %s is applied to same namespace as network Policy, finding cluster IPs", service.Name)
+	ips := getSvcVips(service)
+
+	as.Lock()
+	defer as.Unlock()
+	if as.deleted {
+		return nil
+	}
+	klog.Infof("Deleting service %s, possible VIPs: %v from address set %s", service.Name, ips, as.name)
+	return as.addressSet.DeleteIPs(ips)
+}
+
+// addNetpolAddressSetHandler records a handler filling the address set, or
+// removes it if the address set was released while the handler was added
+func (oc *Controller) addNetpolAddressSetHandler(as *netpolAddressSet, handler *factory.Handler,
+	handlerList *[]*factory.Handler, remove func(*factory.Handler)) {
+	as.Lock()
+	defer as.Unlock()
+	if as.deleted {
+		remove(handler)
+		return
+	}
+	*handlerList = append(*handlerList, handler)
+}
+
+// deleteStaleNetpolPortGroups deletes the shared port groups which are not
+// expected, and the per policy port groups of earlier versions of the
+// expected network policies. The ACLs of the network policies which are not
+// expected are removed from the shared port groups kept.
+func deleteStaleNetpolPortGroups(expectedPolicies map[string]map[string]bool, expectedPortGroups sets.String) error {
+	output, stderr, err := util.RunOVNNbctl("--format=csv", "--data=bare", "--no-heading",
+		"--columns=_uuid,name,external_ids,acls", "find", "port_group")
+	if err != nil {
+		return fmt.Errorf("error reading port groups, stderr: %q (%v)", stderr, err)
+	}
+
+	// the port group of each ACL of the shared port groups kept
+	aclPortGroups := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, ",")
+		if len(parts) != 4 {
+			continue
+		}
+		uuid, name := parts[0], parts[1]
+		var readableName string
+		for _, externalID := range strings.Fields(parts[2]) {
+			if strings.HasPrefix(externalID, "name=") {
+				readableName = externalID[5:]
+			}
+		}
+		nameParts := strings.SplitN(readableName, "_", 2)
+		if len(nameParts) != 2 {
+			continue
+		}
+		namespace, suffix := nameParts[0], nameParts[1]
+		if strings.HasPrefix(suffix, netpolSharedPrefix) && expectedPortGroups.Has(name) {
+			for _, aclUUID := range strings.Fields(parts[3]) {
+				aclPortGroups[aclUUID] = uuid
+			}
+			continue
+		}
+		if !strings.HasPrefix(suffix, netpolSharedPrefix) && !expectedPolicies[namespace][suffix] {
+			continue
+		}
+		klog.Infof("Deleting stale network policy port group %s", readableName)
+		if _, stderr, err := util.RunOVNNbctl("--if-exists", "destroy", "port_group", uuid); err != nil {
+			klog.Errorf("Failed to destroy port_group %s, stderr: %q (%v)", readableName, stderr, err)
+		}
+	}
+	if len(aclPortGroups) == 0 {
+		return nil
+	}
+
+	output, stderr, err = util.RunOVNNbctl("--format=csv", "--data=bare", "--no-heading",
+		"--columns=_uuid,external_ids", "find", "ACL")
+	if err != nil {
+		return fmt.Errorf("error reading ACLs, stderr: %q (%v)", stderr, err)
+	}
+	staleACLs := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, ",")
+		if len(parts) != 2 {
+			continue
+		}
+		portGroupUUID, ok := aclPortGroups[parts[0]]
+		if !ok {
+			continue
+		}
+		var namespace, policy string
+		for _, externalID := range strings.Fields(parts[1]) {
+			externalID = strings.Trim(externalID, "\"")
+			if strings.HasPrefix(externalID, "namespace=") {
+				namespace = strings.TrimPrefix(externalID, "namespace=")
+			} else if strings.HasPrefix(externalID, "policy=") {
+				policy = strings.TrimPrefix(externalID, "policy=")
+			}
+		}
+		if policy != "" && !expectedPolicies[namespace][policy] {
+			staleACLs[portGroupUUID] = append(staleACLs[portGroupUUID], parts[0])
+		}
+	}
+	for portGroupUUID, aclUUIDs := range staleACLs {
+		args := append([]string{"remove", "port_group", portGroupUUID, "acls"}, aclUUIDs...)
+		if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+			klog.Errorf("Failed to remove stale network policy ACLs from port group %s, "+
+				"stderr: %q (%v)", portGroupUUID, stderr, err)
+		}
+	}
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
@@ -71,10 +70,17 @@ func newNetworkPolicy(name, namespace string, podSelector metav1.LabelSelector,
 	}
 }
 
+// getPolicyPortGroupNames returns the readable and hashed names of the port
+// group of the pods selected by a network policy
+func getPolicyPortGroupNames(networkPolicy *knet.NetworkPolicy) (string, string) {
+	readableGroupName := getNetpolPortGroupName(networkPolicy.Namespace, &networkPolicy.Spec.PodSelector)
+	return readableGroupName, hashedPortGroup(readableGroupName)
+}
+
 func (n kNetworkPolicy) baseCmds(fexec *ovntest.FakeExec, networkPolicy *knet.NetworkPolicy) string {
-	readableGroupName := fmt.Sprintf("%s_%s", networkPolicy.Namespace, networkPolicy.Name)
-	hashedGroupName := hashedPortGroup(readableGroupName)
+	readableGroupName, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
 	fexec.AddFakeCmdsNoOutputNoError([]string{
+		"ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,name,external_ids,acls find port_group",
 		fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find port_group name=%s", hashedGroupName),
 	})
 	fexec.AddFakeCmd(&ovntest.ExpectedCmd{
@@ -127,7 +133,7 @@ func (n kNetworkPolicy) addLocalPodCmds(fexec *ovntest.FakeExec, networkPolicy *
 		"ovn-nbctl --timeout=15 --if-exists remove port_group " + pg_hash + "_" + egressDenyPG + " ports " + fakeUUID + " -- add port_group " + pg_hash + "_" + egressDenyPG + " ports " + fakeUUID,
 	})
 	if networkPolicy != nil {
-		readableGroupName := fmt.Sprintf("%s_%s", networkPolicy.Namespace, networkPolicy.Name)
+		readableGroupName, _ := getPolicyPortGroupNames(networkPolicy)
 		fexec.AddFakeCmdsNoOutputNoError([]string{
 			"ovn-nbctl --timeout=15 --if-exists remove port_group " + readableGroupName + " ports " + fakeUUID + " -- add port_group " + readableGroupName + " ports " + fakeUUID,
 		})
@@ -135,65 +141,85 @@ func (n kNetworkPolicy) addLocalPodCmds(fexec *ovntest.FakeExec, networkPolicy *
 }
 
 func (n kNetworkPolicy) addNamespaceSelectorCmds(fexec *ovntest.FakeExec, networkPolicy *knet.NetworkPolicy, findAgain bool) {
-	readableGroupName := n.baseCmds(fexec, networkPolicy)
+	n.baseCmds(fexec, networkPolicy)
+	n.addACLCmds(fexec, networkPolicy, findAgain)
+}
+
+// addACLCmds adds the commands creating the ACLs of the rules of a network
+// policy with peer selectors in its port group
+func (n kNetworkPolicy) addACLCmds(fexec *ovntest.FakeExec, networkPolicy *knet.NetworkPolicy, findAgain bool) {
+	readableGroupName, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
 	for i := range networkPolicy.Spec.Ingress {
+		asHashName := getAddressSetHashName(networkPolicy, knet.PolicyTypeIngress, i)
 		fexec.AddFakeCmdsNoOutputNoError([]string{
 			fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"None\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=%v external-ids:policy_type=Ingress", networkPolicy.Namespace, networkPolicy.Name, i),
-			"ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4.src == {$a3128014386057836746} && outport == @a14195333570786048679\" action=allow-related log=false severity=info meter=acl-logging name=namespace1 external-ids:l4Match=\"None\" external-ids:ipblock_cidr=false external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Ingress_num=0 external-ids:policy_type=Ingress -- add port_group " + readableGroupName + " acls @acl",
+			"ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4.src == {$" + asHashName + "} && outport == @" + hashedGroupName + "\" action=allow-related log=false severity=info meter=acl-logging name=" + networkPolicy.Namespace + " external-ids:l4Match=\"None\" external-ids:ipblock_cidr=false external-ids:namespace=" + networkPolicy.Namespace + " external-ids:policy=" + networkPolicy.Name + " external-ids:Ingress_num=0 external-ids:policy_type=Ingress -- add port_group " + readableGroupName + " acls @acl",
 		})
 		if findAgain {
 			fexec.AddFakeCmdsNoOutputNoError([]string{
-				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.src == {$a3128014386057836746} && outport == @a14195333570786048679\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.src == {$" + asHashName + "} && outport == @" + hashedGroupName + "\" external-ids:namespace=" + networkPolicy.Namespace + " external-ids:policy=" + networkPolicy.Name + " external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
 			})
 		}
 	}
 	for i := range networkPolicy.Spec.Egress {
+		asHashName := getAddressSetHashName(networkPolicy, knet.PolicyTypeEgress, i)
 		fexec.AddFakeCmdsNoOutputNoError([]string{
 			fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"None\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Egress_num=%v external-ids:policy_type=Egress", networkPolicy.Namespace, networkPolicy.Name, i),
-			"ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4.dst == {$a17928043879887565554} && inport == @a14195333570786048679\" action=allow-related log=false severity=info meter=acl-logging name=namespace1 external-ids:l4Match=\"None\" external-ids:ipblock_cidr=false external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Egress_num=0 external-ids:policy_type=Egress -- add port_group " + readableGroupName + " acls @acl",
+			"ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4.dst == {$" + asHashName + "} && inport == @" + hashedGroupName + "\" action=allow-related log=false severity=info meter=acl-logging name=" + networkPolicy.Namespace + " external-ids:l4Match=\"None\" external-ids:ipblock_cidr=false external-ids:namespace=" + networkPolicy.Namespace + " external-ids:policy=" + networkPolicy.Name + " external-ids:Egress_num=0 external-ids:policy_type=Egress -- add port_group " + readableGroupName + " acls @acl",
 		})
 		if findAgain {
 			fexec.AddFakeCmdsNoOutputNoError([]string{
-				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.dst == {$a17928043879887565554} && inport == @a14195333570786048679\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Egress_num=0 external-ids:policy_type=Egress",
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.dst == {$" + asHashName + "} && inport == @" + hashedGroupName + "\" external-ids:namespace=" + networkPolicy.Namespace + " external-ids:policy=" + networkPolicy.Name + " external-ids:Egress_num=0 external-ids:policy_type=Egress",
 			})
 		}
 	}
 }
 
-func getAddressSetName(namespace, name string, policyType knet.PolicyType, idx int) string {
-	direction := strings.ToLower(string(policyType))
-	return fmt.Sprintf("%s.%s.%s.%d", namespace, name, direction, idx)
+// getAddressSetName returns the name of the peer address set of a network
+// policy rule
+func getAddressSetName(networkPolicy *knet.NetworkPolicy, policyType knet.PolicyType, idx int) string {
+	if policyType == knet.PolicyTypeIngress {
+		return getNetpolAddressSetName(networkPolicy.Namespace, policyType, networkPolicy.Spec.Ingress[idx].From)
+	}
+	return getNetpolAddressSetName(networkPolicy.Namespace, policyType, networkPolicy.Spec.Egress[idx].To)
+}
+
+// getAddressSetHashName returns the hashed name of the IPv4 peer address set
+// of a network policy rule
+func getAddressSetHashName(networkPolicy *knet.NetworkPolicy, policyType knet.PolicyType, idx int) string {
+	ipv4HashedAS, _ := addressset.MakeAddressSetHashNames(getAddressSetName(networkPolicy, policyType, idx))
+	return ipv4HashedAS
 }
 
 func eventuallyExpectNoAddressSets(fakeOvn *FakeOVN, networkPolicy *knet.NetworkPolicy) {
 	for i := range networkPolicy.Spec.Ingress {
-		asName := getAddressSetName(networkPolicy.Namespace, networkPolicy.Name, knet.PolicyTypeIngress, i)
+		asName := getAddressSetName(networkPolicy, knet.PolicyTypeIngress, i)
 		fakeOvn.asf.EventuallyExpectNoAddressSet(asName)
 	}
 	for i := range networkPolicy.Spec.Egress {
-		asName := getAddressSetName(networkPolicy.Namespace, networkPolicy.Name, knet.PolicyTypeEgress, i)
+		asName := getAddressSetName(networkPolicy, knet.PolicyTypeEgress, i)
 		fakeOvn.asf.EventuallyExpectNoAddressSet(asName)
 	}
 }
 
 func expectAddressSetsWithIP(fakeOvn *FakeOVN, networkPolicy *knet.NetworkPolicy, ip string) {
 	for i := range networkPolicy.Spec.Ingress {
-		asName := getAddressSetName(networkPolicy.Namespace, networkPolicy.Name, knet.PolicyTypeIngress, i)
+		asName := getAddressSetName(networkPolicy, knet.PolicyTypeIngress, i)
 		fakeOvn.asf.ExpectAddressSetWithIPs(asName, []string{ip})
 	}
 	for i := range networkPolicy.Spec.Egress {
-		asName := getAddressSetName(networkPolicy.Namespace, networkPolicy.Name, knet.PolicyTypeEgress, i)
+		asName := getAddressSetName(networkPolicy, knet.PolicyTypeEgress, i)
 		fakeOvn.asf.ExpectAddressSetWithIPs(asName, []string{ip})
 	}
 }
 
 func eventuallyExpectEmptyAddressSets(fakeOvn *FakeOVN, networkPolicy *knet.NetworkPolicy) {
 	for i := range networkPolicy.Spec.Ingress {
-		asName := getAddressSetName(networkPolicy.Namespace, networkPolicy.Name, knet.PolicyTypeIngress, i)
+		asName := getAddressSetName(networkPolicy, knet.PolicyTypeIngress, i)
 		fakeOvn.asf.EventuallyExpectEmptyAddressSet(asName)
 	}
 	for i := range networkPolicy.Spec.Egress {
-		asName := getAddressSetName(networkPolicy.Namespace, networkPolicy.Name, knet.PolicyTypeEgress, i)
+		asName := getAddressSetName(networkPolicy, knet.PolicyTypeEgress, i)
 		fakeOvn.asf.EventuallyExpectEmptyAddressSet(asName)
 	}
 }
@@ -208,9 +234,9 @@ func (n kNetworkPolicy) delCmds(fexec *ovntest.FakeExec, pod pod, networkPolicy
 			"ovn-nbctl --timeout=15 --if-exists remove port_group " + pg_hash + "_" + egressDenyPG + " ports " + fakeUUID,
 		})
 	}
-	readableGroupName := fmt.Sprintf("%s_%s", networkPolicy.Namespace, networkPolicy.Name)
+	readableGroupName, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
 	fexec.AddFakeCmd(&ovntest.ExpectedCmd{
-		Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find port_group name=a14195333570786048679",
+		Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find port_group name=" + hashedGroupName,
 		Output: readableGroupName,
 	})
 	fexec.AddFakeCmdsNoOutputNoError([]string{
@@ -232,7 +258,7 @@ func (n kNetworkPolicy) delPodCmds(fexec *ovntest.FakeExec, networkPolicy *knet.
 	fexec.AddFakeCmdsNoOutputNoError([]string{
 		"ovn-nbctl --timeout=15 --if-exists remove port_group " + pg_hash + "_" + egressDenyPG + " ports " + fakeUUID,
 	})
-	readableGroupName := fmt.Sprintf("%s_%s", networkPolicy.Namespace, networkPolicy.Name)
+	readableGroupName, _ := getPolicyPortGroupNames(networkPolicy)
 	fexec.AddFakeCmdsNoOutputNoError([]string{
 		"ovn-nbctl --timeout=15 --if-exists remove port_group " + readableGroupName + " ports " + fakeUUID,
 	})
@@ -921,12 +947,100 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 				npTest.baseCmds(fExec, networkPolicy)
 				npTest.addLocalPodCmds(fExec, networkPolicy)
 
-				readableGroupName := fmt.Sprintf("%s_%s", networkPolicy.Namespace, networkPolicy.Name)
+				readableGroupName, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
 				fExec.AddFakeCmdsNoOutputNoError([]string{
 					fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"tcp && tcp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress", portNum, networkPolicy.Namespace, networkPolicy.Name),
-					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4 && tcp && tcp.dst==%d && outport == @a14195333570786048679\" action=allow-related log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"tcp && tcp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress -- add port_group %s acls @acl", portNum, networkPolicy.Namespace, portNum, networkPolicy.Namespace, networkPolicy.Name, readableGroupName),
+					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4 && tcp && tcp.dst==%d && outport == @%s\" action=allow-related log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"tcp && tcp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress -- add port_group %s acls @acl", portNum, hashedGroupName, networkPolicy.Namespace, portNum, networkPolicy.Namespace, networkPolicy.Name, readableGroupName),
 					fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"tcp && tcp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Egress_num=0 external-ids:policy_type=Egress", portNum, networkPolicy.Namespace, networkPolicy.Name),
-					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4 && tcp && tcp.dst==%d && inport == @a14195333570786048679\" action=allow-related log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"tcp && tcp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Egress_num=0 external-ids:policy_type=Egress -- add port_group %s acls @acl", portNum, networkPolicy.Namespace, portNum, networkPolicy.Namespace, networkPolicy.Name, readableGroupName),
+					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4 && tcp && tcp.dst==%d && inport == @%s\" action=allow-related log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"tcp && tcp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Egress_num=0 external-ids:policy_type=Egress -- add port_group %s acls @acl", portNum, hashedGroupName, networkPolicy.Namespace, portNum, networkPolicy.Namespace, networkPolicy.Name, readableGroupName),
+				})
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{namespace1},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{*nPod},
+					},
+					&knet.NetworkPolicyList{
+						Items: []knet.NetworkPolicy{*networkPolicy},
+					},
+				)
+				nPodTest.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				fakeOvn.controller.WatchNetworkPolicy()
+
+				_, err := fakeOvn.fakeClient.KubeClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Get(context.TODO(), networkPolicy.Name, metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				fakeOvn.asf.ExpectAddressSetWithIPs(namespaceName1, []string{nPodTest.podIP})
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("correctly creates a networkpolicy allowing a port range to a local pod", func() {
+			app.Action = func(ctx *cli.Context) error {
+				npTest := kNetworkPolicy{}
+
+				namespace1 := *newNamespace(namespaceName1)
+				nPodTest := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespace1.Name,
+				)
+				nPod := newPod(nPodTest.namespace, nPodTest.podName, nPodTest.nodeName, nPodTest.podIP)
+
+				const (
+					labelName string = "pod-name"
+					labelVal  string = "server"
+					portNum   int32  = 30000
+				)
+				endPort := int32(32767)
+				nPod.Labels[labelName] = labelVal
+
+				tcpProtocol := v1.Protocol(v1.ProtocolTCP)
+				networkPolicy := newNetworkPolicy("networkpolicy1", namespace1.Name,
+					metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							labelName: labelVal,
+						},
+					},
+					[]knet.NetworkPolicyIngressRule{{
+						Ports: []knet.NetworkPolicyPort{{
+							Port:     &intstr.IntOrString{IntVal: portNum},
+							EndPort:  &endPort,
+							Protocol: &tcpProtocol,
+						}},
+					}},
+					[]knet.NetworkPolicyEgressRule{{
+						Ports: []knet.NetworkPolicyPort{{
+							Port:     &intstr.IntOrString{IntVal: portNum},
+							EndPort:  &endPort,
+							Protocol: &tcpProtocol,
+						}},
+					}},
+				)
+
+				nPodTest.baseCmds(fExec)
+				npTest.baseCmds(fExec, networkPolicy)
+				npTest.addLocalPodCmds(fExec, networkPolicy)
+
+				readableGroupName, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"tcp && tcp.dst >= %d && tcp.dst <= %d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress", portNum, endPort, networkPolicy.Namespace, networkPolicy.Name),
+					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4 && tcp && tcp.dst >= %d && tcp.dst <= %d && outport == @%s\" action=allow-related log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"tcp && tcp.dst >= %d && tcp.dst <= %d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress -- add port_group %s acls @acl", portNum, endPort, hashedGroupName, networkPolicy.Namespace, portNum, endPort, networkPolicy.Namespace, networkPolicy.Name, readableGroupName),
+					fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"tcp && tcp.dst >= %d && tcp.dst <= %d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Egress_num=0 external-ids:policy_type=Egress", portNum, endPort, networkPolicy.Namespace, networkPolicy.Name),
+					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@acl create acl priority=1001 direction=to-lport match=\"ip4 && tcp && tcp.dst >= %d && tcp.dst <= %d && inport == @%s\" action=allow-related log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"tcp && tcp.dst >= %d && tcp.dst <= %d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Egress_num=0 external-ids:policy_type=Egress -- add port_group %s acls @acl", portNum, endPort, hashedGroupName, networkPolicy.Namespace, portNum, endPort, networkPolicy.Namespace, networkPolicy.Name, readableGroupName),
 				})
 
 				fakeOvn.start(ctx,
@@ -957,6 +1071,222 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("creates stateless ACLs and their reply ACLs for a stateless networkpolicy", func() {
+			app.Action = func(ctx *cli.Context) error {
+				npTest := kNetworkPolicy{}
+
+				namespace1 := *newNamespace(namespaceName1)
+				nPodTest := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespace1.Name,
+				)
+				nPod := newPod(nPodTest.namespace, nPodTest.podName, nPodTest.nodeName, nPodTest.podIP)
+
+				const portNum int32 = 5000
+				udpProtocol := v1.Protocol(v1.ProtocolUDP)
+				networkPolicy := newNetworkPolicy("networkpolicy1", namespace1.Name,
+					metav1.LabelSelector{},
+					[]knet.NetworkPolicyIngressRule{{
+						Ports: []knet.NetworkPolicyPort{{
+							Port:     &intstr.IntOrString{IntVal: portNum},
+							Protocol: &udpProtocol,
+						}},
+					}},
+					nil,
+				)
+				networkPolicy.Annotations = map[string]string{aclStatelessAnnotation: "true"}
+				networkPolicy.Spec.PolicyTypes = []knet.PolicyType{knet.PolicyTypeIngress, knet.PolicyTypeEgress}
+
+				nPodTest.baseCmds(fExec)
+				npTest.baseCmds(fExec, networkPolicy)
+				npTest.addLocalPodCmds(fExec, networkPolicy)
+
+				readableGroupName, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
+				aclArgs := func(match string) string {
+					return fmt.Sprintf("priority=1001 direction=to-lport match=\"%s\" action=allow-stateless log=false severity=info meter=acl-logging name=%s external-ids:l4Match=\"udp && udp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
+						match, networkPolicy.Namespace, portNum, networkPolicy.Namespace, networkPolicy.Name)
+				}
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					fmt.Sprintf("ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:l4Match=\"udp && udp.dst==%d\" external-ids:ipblock_cidr=false external-ids:namespace=%s external-ids:policy=%s external-ids:Ingress_num=0 external-ids:policy_type=Ingress", portNum, networkPolicy.Namespace, networkPolicy.Name),
+					"ovn-nbctl --timeout=15 --id=@acl create acl " + aclArgs(fmt.Sprintf("ip4 && udp && udp.dst==%d && outport == @%s", portNum, hashedGroupName)) +
+						" -- --id=@reply create acl " + aclArgs(fmt.Sprintf("ip4 && udp && udp.src==%d && inport == @%s", portNum, hashedGroupName)) +
+						" external-ids:stateless_reply=true -- add port_group " + readableGroupName + " acls @acl @reply",
+				})
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{namespace1},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{*nPod},
+					},
+					&knet.NetworkPolicyList{
+						Items: []knet.NetworkPolicy{*networkPolicy},
+					},
+				)
+				nPodTest.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				fakeOvn.controller.WatchNetworkPolicy()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("isolates the selected pods without applying the rules of an invalid stateless networkpolicy", func() {
+			app.Action = func(ctx *cli.Context) error {
+				npTest := kNetworkPolicy{}
+
+				namespace1 := *newNamespace(namespaceName1)
+				nPodTest := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespace1.Name,
+				)
+				nPod := newPod(nPodTest.namespace, nPodTest.podName, nPodTest.nodeName, nPodTest.podIP)
+
+				udpProtocol := v1.Protocol(v1.ProtocolUDP)
+				networkPolicy := newNetworkPolicy("networkpolicy1", namespace1.Name,
+					metav1.LabelSelector{},
+					[]knet.NetworkPolicyIngressRule{{
+						Ports: []knet.NetworkPolicyPort{{
+							Port:     &intstr.IntOrString{Type: intstr.String, StrVal: "data"},
+							Protocol: &udpProtocol,
+						}},
+					}},
+					nil,
+				)
+				networkPolicy.Annotations = map[string]string{aclStatelessAnnotation: "true"}
+				networkPolicy.Spec.PolicyTypes = []knet.PolicyType{knet.PolicyTypeIngress, knet.PolicyTypeEgress}
+
+				// no ACL is created for the ingress rule
+				nPodTest.baseCmds(fExec)
+				npTest.baseCmds(fExec, networkPolicy)
+				npTest.addLocalPodCmds(fExec, networkPolicy)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{namespace1},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{*nPod},
+					},
+					&knet.NetworkPolicyList{
+						Items: []knet.NetworkPolicy{*networkPolicy},
+					},
+				)
+				nPodTest.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				fakeOvn.controller.WatchNetworkPolicy()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Consistently(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				// the policy is reported as failing
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.HaveLen(1))
+				recordedEvent := <-fakeOvn.fakeRecorder.Events
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("ErrorAddingNetworkPolicy"))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("named port data"))
+				fakeOvn.controller.netpolStatusMutex.Lock()
+				gomega.Expect(fakeOvn.controller.failedNetworkPolicies.List()).To(gomega.Equal([]string{"namespace1/networkpolicy1"}))
+				fakeOvn.controller.netpolStatusMutex.Unlock()
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("reports the named ports of an egress rule with only ipBlock peers as not applied", func() {
+			app.Action = func(ctx *cli.Context) error {
+				npTest := kNetworkPolicy{}
+
+				namespace1 := *newNamespace(namespaceName1)
+				nPodTest := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespace1.Name,
+				)
+				nPod := newPod(nPodTest.namespace, nPodTest.podName, nPodTest.nodeName, nPodTest.podIP)
+
+				tcpProtocol := v1.Protocol(v1.ProtocolTCP)
+				networkPolicy := newNetworkPolicy("networkpolicy1", namespace1.Name,
+					metav1.LabelSelector{},
+					nil,
+					[]knet.NetworkPolicyEgressRule{{
+						Ports: []knet.NetworkPolicyPort{{
+							Port:     &intstr.IntOrString{Type: intstr.String, StrVal: "http"},
+							Protocol: &tcpProtocol,
+						}},
+						To: []knet.NetworkPolicyPeer{{
+							IPBlock: &knet.IPBlock{CIDR: "10.1.0.0/16"},
+						}},
+					}},
+				)
+
+				// no ACL is created for the named port
+				nPodTest.baseCmds(fExec)
+				npTest.baseCmds(fExec, networkPolicy)
+				npTest.addLocalPodCmds(fExec, networkPolicy)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{namespace1},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{*nPod},
+					},
+					&knet.NetworkPolicyList{
+						Items: []knet.NetworkPolicy{*networkPolicy},
+					},
+				)
+				nPodTest.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				fakeOvn.controller.WatchNetworkPolicy()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Consistently(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+				// the policy is reported as failing
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.HaveLen(1))
+				recordedEvent := <-fakeOvn.fakeRecorder.Events
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("ErrorAddingNetworkPolicy"))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("only has ipBlock peers"))
+				fakeOvn.controller.netpolStatusMutex.Lock()
+				gomega.Expect(fakeOvn.controller.failedNetworkPolicies.List()).To(gomega.Equal([]string{"namespace1/networkpolicy1"}))
+				fakeOvn.controller.netpolStatusMutex.Unlock()
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
 		ginkgo.It("reconciles a deleted namespace referenced by a networkpolicy with a local running pod", func() {
 			app.Action = func(ctx *cli.Context) error {
 
@@ -1037,15 +1367,18 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
 				fakeOvn.asf.ExpectAddressSetWithIPs(namespaceName1, []string{nPodTest.podIP})
 
+				_, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
+				ingressASHashName := getAddressSetHashName(networkPolicy, knet.PolicyTypeIngress, 0)
+				egressASHashName := getAddressSetHashName(networkPolicy, knet.PolicyTypeEgress, 0)
 				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
-					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.src == {$a3128014386057836746, $a4615334824109672969} && outport == @a14195333570786048679\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.src == {$" + ingressASHashName + ", $a4615334824109672969} && outport == @" + hashedGroupName + "\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
 					Output: fakeUUID,
 				})
 				fExec.AddFakeCmdsNoOutputNoError([]string{
-					"ovn-nbctl --timeout=15 set acl " + fakeUUID + " match=\"ip4.src == {$a3128014386057836746} && outport == @a14195333570786048679\"",
+					"ovn-nbctl --timeout=15 set acl " + fakeUUID + " match=\"ip4.src == {$" + ingressASHashName + "} && outport == @" + hashedGroupName + "\"",
 				})
 				fExec.AddFakeCmdsNoOutputNoError([]string{
-					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.dst == {$a17928043879887565554, $a4615334824109672969} && inport == @a14195333570786048679\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Egress_num=0 external-ids:policy_type=Egress",
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.dst == {$" + egressASHashName + ", $a4615334824109672969} && inport == @" + hashedGroupName + "\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Egress_num=0 external-ids:policy_type=Egress",
 				})
 
 				err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Delete(context.TODO(), namespace2.Name, *metav1.NewDeleteOptions(0))
@@ -1120,15 +1453,18 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
 
+				_, hashedGroupName := getPolicyPortGroupNames(networkPolicy)
+				ingressASHashName := getAddressSetHashName(networkPolicy, knet.PolicyTypeIngress, 0)
+				egressASHashName := getAddressSetHashName(networkPolicy, knet.PolicyTypeEgress, 0)
 				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
-					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.src == {$a3128014386057836746, $a4615334824109672969} && outport == @a14195333570786048679\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.src == {$" + ingressASHashName + ", $a4615334824109672969} && outport == @" + hashedGroupName + "\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Ingress_num=0 external-ids:policy_type=Ingress",
 					Output: fakeUUID,
 				})
 				fExec.AddFakeCmdsNoOutputNoError([]string{
-					"ovn-nbctl --timeout=15 set acl " + fakeUUID + " match=\"ip4.src == {$a3128014386057836746} && outport == @a14195333570786048679\"",
+					"ovn-nbctl --timeout=15 set acl " + fakeUUID + " match=\"ip4.src == {$" + ingressASHashName + "} && outport == @" + hashedGroupName + "\"",
 				})
 				fExec.AddFakeCmdsNoOutputNoError([]string{
-					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.dst == {$a17928043879887565554, $a4615334824109672969} && inport == @a14195333570786048679\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Egress_num=0 external-ids:policy_type=Egress",
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"ip4.dst == {$" + egressASHashName + ", $a4615334824109672969} && inport == @" + hashedGroupName + "\" external-ids:namespace=namespace1 external-ids:policy=networkpolicy1 external-ids:Egress_num=0 external-ids:policy_type=Egress",
 				})
 
 				err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Delete(context.TODO(), namespace2.Name, *metav1.NewDeleteOptions(0))
@@ -1540,6 +1876,108 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+
+		ginkgo.It("shares the port group and address sets of networkpolicies with identical selectors", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				npTest := kNetworkPolicy{}
+
+				namespace1 := *newNamespace(namespaceName1)
+
+				nPodTest := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespace1.Name,
+				)
+				peers := []knet.NetworkPolicyPeer{
+					{
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"name": nPodTest.podName,
+							},
+						},
+					},
+				}
+				networkPolicy1 := newNetworkPolicy("networkpolicy1", namespace1.Name,
+					metav1.LabelSelector{},
+					[]knet.NetworkPolicyIngressRule{{From: peers}},
+					[]knet.NetworkPolicyEgressRule{{To: peers}})
+				networkPolicy2 := newNetworkPolicy("networkpolicy2", namespace1.Name,
+					metav1.LabelSelector{},
+					[]knet.NetworkPolicyIngressRule{{From: peers}},
+					[]knet.NetworkPolicyEgressRule{{To: peers}})
+
+				// the port group is only created once
+				nPodTest.baseCmds(fExec)
+				npTest.addNamespaceSelectorCmds(fExec, networkPolicy1, false)
+				npTest.addACLCmds(fExec, networkPolicy2, false)
+				npTest.addLocalPodCmds(fExec, networkPolicy1)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespace1,
+						},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{
+							*newPod(nPodTest.namespace, nPodTest.podName, nPodTest.nodeName, nPodTest.podIP),
+						},
+					},
+					&knet.NetworkPolicyList{
+						Items: []knet.NetworkPolicy{
+							*networkPolicy1,
+							*networkPolicy2,
+						},
+					},
+				)
+				nPodTest.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				fakeOvn.controller.WatchNetworkPolicy()
+
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				gomega.Expect(getAddressSetName(networkPolicy2, knet.PolicyTypeIngress, 0)).To(gomega.Equal(getAddressSetName(networkPolicy1, knet.PolicyTypeIngress, 0)))
+				gomega.Expect(getAddressSetName(networkPolicy2, knet.PolicyTypeEgress, 0)).To(gomega.Equal(getAddressSetName(networkPolicy1, knet.PolicyTypeEgress, 0)))
+				expectAddressSetsWithIP(fakeOvn, networkPolicy1, nPodTest.podIP)
+
+				// deleting a policy only removes its ACLs from the port group
+				// shared with the other policy
+				const aclUUID1, aclUUID2 = "6a5d6a4b-0f1c-4f3e-9d6b-2e0b1a7c8d01", "6a5d6a4b-0f1c-4f3e-9d6b-2e0b1a7c8d02"
+				readableGroupName, _ := getPolicyPortGroupNames(networkPolicy2)
+				fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+					Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL external-ids:namespace=namespace1 external-ids:policy=networkpolicy2",
+					Output: aclUUID1 + "\n" + aclUUID2,
+				})
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 remove port_group " + readableGroupName + " acls " + aclUUID1 + " " + aclUUID2,
+				})
+
+				err := fakeOvn.fakeClient.KubeClient.NetworkingV1().NetworkPolicies(networkPolicy2.Namespace).Delete(context.TODO(), networkPolicy2.Name, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				expectAddressSetsWithIP(fakeOvn, networkPolicy1, nPodTest.podIP)
+
+				// deleting the last policy deletes the port group and the
+				// address sets
+				npTest.delCmds(fExec, nPodTest, networkPolicy1, true)
+
+				err = fakeOvn.fakeClient.KubeClient.NetworkingV1().NetworkPolicies(networkPolicy1.Namespace).Delete(context.TODO(), networkPolicy1.Name, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+				eventuallyExpectNoAddressSets(fakeOvn, networkPolicy1)
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 })
 
@@ -1612,10 +2050,10 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Low-Level Operations", func() {
 		}
 
 		gp := newGressPolicy(knet.PolicyTypeIngress, 0, policy.Namespace, policy.Name)
-		err := gp.ensurePeerAddressSet(asFactory)
+		as, err := asFactory.NewAddressSet(getNetpolAddressSetName(policy.Namespace, knet.PolicyTypeIngress, nil), nil)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
-		//asName := getIPv4ASName(gp.peerAddressSet.GetName())
-		asName := gp.peerAddressSet.GetName()
+		gp.setPeerAddressSet(&netpolAddressSet{addressSet: as})
+		asName := gp.peerAddressSet.addressSet.GetName()
 
 		one := fmt.Sprintf("testing.policy.ingress.1")
 		two := fmt.Sprintf("testing.policy.ingress.2")
@@ -1691,4 +2129,59 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Low-Level Operations", func() {
 		gp.delNamespaceAddressSet(four, pgName)
 		gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
 	})
+
+	ginkgo.It("creates the default deny ACLs in audit mode and switches them back", func() {
+		const namespace string = "testing"
+		pgHash := hashedPortGroup(namespace)
+		ingressMatch := "match=\"outport == @" + pgHash + "_" + ingressDenyPG + "\""
+		oc := &Controller{}
+		nsInfo := &namespaceInfo{
+			networkPolicies: map[string]*networkPolicy{"policy": {audit: true}},
+			aclLogging:      ACLLoggingLevels{Deny: "alert"},
+		}
+		nsInfo.defaultDenyAudit = nsInfo.getDefaultDenyAudit()
+		gomega.Expect(nsInfo.defaultDenyAudit).To(gomega.BeTrue())
+
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find port_group name=" + pgHash + "_" + ingressDenyPG,
+			Output: fakeUUID,
+		})
+		fExec.AddFakeCmdsNoOutputNoError([]string{
+			"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + ingressMatch + " action=allow-related external-ids:default-deny-policy-type=Ingress",
+			"ovn-nbctl --timeout=15 --id=@acl create acl priority=" + defaultDenyPriority + " direction=" + toLport + " " + ingressMatch + " action=allow-related log=true severity=alert meter=acl-logging name=audit_" + namespace + " external-ids:default-deny-policy-type=Ingress -- add port_group " + fakeUUID + " acls @acl",
+		})
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"outport == @" + pgHash + "_" + ingressDenyPG + " && arp\" action=allow external-ids:default-deny-policy-type=Ingress",
+			Output: fakeUUID,
+		})
+		err := oc.createDefaultDenyPortGroup(namespace, nsInfo, knet.PolicyTypeIngress, nsInfo.aclLogging.Deny)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+
+		// a policy without the annotation turns the audit mode off
+		nsInfo.networkPolicies["other"] = &networkPolicy{}
+		gomega.Expect(nsInfo.getDefaultDenyAudit()).To(gomega.BeFalse())
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL " + ingressMatch + " action=allow-related external-ids:default-deny-policy-type=Ingress",
+			Output: fakeUUID,
+		})
+		fExec.AddFakeCmdsNoOutputNoError([]string{
+			"ovn-nbctl --timeout=15 set acl " + fakeUUID + " action=drop log=true severity=alert name=" + namespace,
+		})
+		fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+			Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid find ACL match=\"inport == @" + pgHash + "_" + egressDenyPG + "\" action=allow-related external-ids:default-deny-policy-type=Egress",
+			Output: fakeUUID,
+		})
+		fExec.AddFakeCmdsNoOutputNoError([]string{
+			"ovn-nbctl --timeout=15 set acl " + fakeUUID + " action=drop log=true severity=alert name=" + namespace,
+		})
+		err = oc.setDefaultDenyAudit(namespace, nsInfo, nsInfo.getDefaultDenyAudit())
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+		gomega.Expect(nsInfo.defaultDenyAudit).To(gomega.BeFalse())
+
+		// the namespace annotation enables the audit mode whatever the policies
+		nsInfo.aclAudit = true
+		gomega.Expect(nsInfo.getDefaultDenyAudit()).To(gomega.BeTrue())
+	})
 })
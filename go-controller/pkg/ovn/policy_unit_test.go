@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	goovn "github.com/ebay/go-ovn"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
 	goovn_mock "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/mocks/github.com/ebay/go-ovn"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -101,3 +103,59 @@ func TestAddAllowACLFromNode(t *testing.T) {
 		})
 	}
 }
+
+func TestNetpolPortGroupAddPort(t *testing.T) {
+	config.PrepareTestConfig()
+	portInfo := &lpInfo{name: "namespace1_pod1", uuid: "pod1-uuid"}
+	addCmd := "ovn-nbctl --timeout=15 --if-exists remove port_group pg-uuid ports pod1-uuid -- add port_group pg-uuid ports pod1-uuid"
+
+	tests := []struct {
+		desc     string
+		refs     int
+		addErr   error
+		errExp   bool
+		expRefs  int
+		expAdded bool
+	}{
+		{
+			desc:     "adds the first port to the port group",
+			expRefs:  1,
+			expAdded: true,
+		},
+		{
+			desc:    "only counts a port already in the port group",
+			refs:    1,
+			expRefs: 2,
+		},
+		{
+			desc:     "does not count a port that failed to be added",
+			addErr:   fmt.Errorf("transaction failed"),
+			errExp:   true,
+			expAdded: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			fexec := ovntest.NewFakeExec()
+			if tc.expAdded {
+				fexec.AddFakeCmd(&ovntest.ExpectedCmd{Cmd: addCmd, Err: tc.addErr})
+			}
+			err := util.SetExec(fexec)
+			assert.Nil(t, err)
+
+			pg := &netpolPortGroup{uuid: "pg-uuid", ports: map[string]int{}}
+			if tc.refs > 0 {
+				pg.ports[portInfo.name] = tc.refs
+			}
+			err = pg.addPort(portInfo)
+			if tc.errExp {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tc.expRefs, pg.ports[portInfo.name])
+			assert.True(t, fexec.CalledMatchesExpected(), fexec.ErrorDesc())
+		})
+	}
+}
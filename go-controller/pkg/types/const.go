@@ -41,6 +41,11 @@ const (
 	HybridOverlayReroutePriority          = "501"
 	DefaultNoRereoutePriority             = "101"
 	EgressIPReroutePriority               = "100"
+	EgressSVCReroutePriority              = "99"
+
+	// priority of the QoS rule created for the first rule of an EgressQoS on
+	// the node logical switches, each following rule gets the next lower one
+	EgressQoSRulePriority = 1000
 
 	V6NodeLocalNATSubnet           = "fd99::/64"
 	V6NodeLocalNATSubnetPrefix     = 64
@@ -57,4 +62,11 @@ const (
 	NeighborAdvertisementICMPType = 136
 
 	OvnACLLoggingMeter = "acl-logging"
+
+	// Path of the health endpoint ovnkube-node serves for EgressIP reachability checks
+	EgressIPNodeHealthCheckPath = "/egressip/healthz"
+
+	// EgressIPMarkAnnotation is the packet mark set on the traffic of the pods of an EgressIP,
+	// which egress nodes SNAT to the egress IP when it is assigned to a secondary host interface
+	EgressIPMarkAnnotation = "k8s.ovn.org/egressip-mark"
 )
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This handles the annotations used to make the endpoints of a LoadBalancer service
+// egress the cluster with the service ingress IP:
+//
+//   annotations:
+//     k8s.ovn.org/egress-service: |
+//       {
+//         "nodeSelector": {"matchLabels": {"node-role.kubernetes.io/egress": ""}}
+//       }
+//     k8s.ovn.org/egress-service-host: node1
+//
+// The first annotation is set by the user to opt the service in, the nodeSelector
+// restricting the nodes which can host the service. The second one is set by the
+// master to the node the traffic of the endpoints currently egresses through.
+
+const (
+	// EgressServiceAnnotation opts a LoadBalancer service in as an egress service
+	EgressServiceAnnotation = "k8s.ovn.org/egress-service"
+
+	// EgressServiceHostAnnotation is the node hosting an egress service
+	EgressServiceHostAnnotation = "k8s.ovn.org/egress-service-host"
+)
+
+// EgressServiceConfig is the value of the egress service annotation
+type EgressServiceConfig struct {
+	// NodeSelector restricts the nodes which can host the service, all the nodes
+	// can if it is empty
+	NodeSelector metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// HasEgressServiceAnnotation returns true if the service opted in as an egress service
+func HasEgressServiceAnnotation(svc *kapi.Service) bool {
+	_, ok := svc.Annotations[EgressServiceAnnotation]
+	return ok
+}
+
+// ParseEgressServiceAnnotation returns the egress service configuration of the service,
+// an empty annotation selecting all the nodes
+func ParseEgressServiceAnnotation(svc *kapi.Service) (*EgressServiceConfig, error) {
+	annotation, ok := svc.Annotations[EgressServiceAnnotation]
+	if !ok {
+		return nil, newAnnotationNotSetError("%s annotation not found for service %s/%s",
+			EgressServiceAnnotation, svc.Namespace, svc.Name)
+	}
+	cfg := &EgressServiceConfig{}
+	if annotation == "" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal([]byte(annotation), cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s annotation %q of service %s/%s: %v",
+			EgressServiceAnnotation, annotation, svc.Namespace, svc.Name, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseEgressServiceAnnotation(t *testing.T) {
+	tests := []struct {
+		desc        string
+		inpService  v1.Service
+		errExpected bool
+		notSet      bool
+		expOutput   *EgressServiceConfig
+	}{
+		{
+			desc:        "egress service annotation not found for service",
+			inpService:  v1.Service{},
+			errExpected: true,
+			notSet:      true,
+		},
+		{
+			desc: "success: empty annotation selects all the nodes",
+			inpService: v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/egress-service": ""},
+				},
+			},
+			expOutput: &EgressServiceConfig{},
+		},
+		{
+			desc: "success: parse node selector",
+			inpService: v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/egress-service": `{"nodeSelector": {"matchLabels": {"egress": ""}}}`},
+				},
+			},
+			expOutput: &EgressServiceConfig{
+				NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"egress": ""}},
+			},
+		},
+		{
+			desc: "error: annotation is not valid JSON",
+			inpService: v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/egress-service": "egress"},
+				},
+			},
+			errExpected: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			cfg, e := ParseEgressServiceAnnotation(&tc.inpService)
+			if tc.errExpected {
+				t.Log(e)
+				assert.Error(t, e)
+				assert.Equal(t, tc.notSet, IsAnnotationNotSetError(e))
+			} else {
+				assert.NoError(t, e)
+			}
+			assert.Equal(t, tc.expOutput, cfg)
+		})
+	}
+}
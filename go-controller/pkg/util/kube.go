@@ -19,8 +19,11 @@ import (
 	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
+	adminnetworkpolicyclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned"
+	adminpolicybasedrouteclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminpolicybasedroute/v1/apis/clientset/versioned"
 	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
 	egressipclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	discovery "k8s.io/api/discovery/v1beta1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 
@@ -33,6 +36,9 @@ type OVNClientset struct {
 	KubeClient           kubernetes.Interface
 	EgressIPClient       egressipclientset.Interface
 	EgressFirewallClient egressfirewallclientset.Interface
+	EgressQoSClient      egressqosclientset.Interface
+	APBRouteClient       adminpolicybasedrouteclientset.Interface
+	ANPClient            adminnetworkpolicyclientset.Interface
 	APIExtensionsClient  apiextensionsclientset.Interface
 }
 
@@ -133,10 +139,25 @@ func NewOVNClientset(conf *config.KubernetesConfig) (*OVNClientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	egressQoSClientset, err := egressqosclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+	apbRouteClientset, err := adminpolicybasedrouteclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+	anpClientset, err := adminnetworkpolicyclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
 	return &OVNClientset{
 		KubeClient:           kclientset,
 		EgressIPClient:       egressIPClientset,
 		EgressFirewallClient: egressFirewallClientset,
+		EgressQoSClient:      egressQoSClientset,
+		APBRouteClient:       apbRouteClientset,
+		ANPClient:            anpClientset,
 		APIExtensionsClient:  crdClientset,
 	}, nil
 }
@@ -55,6 +55,14 @@ const (
 	// ovnNodeCIDR is the CIDR form representation of primary network interface's attached IP address (i.e: 192.168.126.31/24 or 0:0:0:0:0:feff:c0a8:8e0c/64)
 	ovnNodeIfAddr = "k8s.ovn.org/node-primary-ifaddr"
 
+	// ovnNodeEgressInterfaces lists the secondary host interfaces of the node which can host egress IPs,
+	// with their IP addresses in CIDR form (i.e: [{"interface": "eth1", "ipv4": "10.10.0.5/24"}])
+	ovnNodeEgressInterfaces = "k8s.ovn.org/node-egress-interfaces"
+
+	// ovnNodeEgressIPCapacity is the maximum number of egress IPs the node can host (i.e: "10"). It is published
+	// by ovnkube-node when configured with a capacity, otherwise it can be set by the administrator
+	ovnNodeEgressIPCapacity = "k8s.ovn.org/node-egress-ip-capacity"
+
 	// OvnNodeEgressLabel is a user assigned node label indicating to ovn-kubernetes that the node is to be used for egress IP assignment
 	ovnNodeEgressLabel = "k8s.ovn.org/egress-assignable"
 )
@@ -271,6 +279,57 @@ func ParseNodePrimaryIfAddr(node *kapi.Node) (string, string, error) {
 	return nodeIfAddr.IPv4, nodeIfAddr.IPv6, nil
 }
 
+// EgressInterface is a secondary host interface of a node which can host egress IPs
+type EgressInterface struct {
+	Name string `json:"interface"`
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// SetNodeEgressInterfaces sets the secondary host interfaces of the node which can host egress IPs
+func SetNodeEgressInterfaces(nodeAnnotator kube.Annotator, egressInterfaces []EgressInterface) error {
+	return nodeAnnotator.Set(ovnNodeEgressInterfaces, egressInterfaces)
+}
+
+// ParseNodeEgressInterfaces returns the secondary host interfaces of the node which can host egress IPs
+func ParseNodeEgressInterfaces(node *kapi.Node) ([]EgressInterface, error) {
+	egressInterfacesAnnotation, ok := node.Annotations[ovnNodeEgressInterfaces]
+	if !ok {
+		return nil, newAnnotationNotSetError("%s annotation not found for node %q", ovnNodeEgressInterfaces, node.Name)
+	}
+	egressInterfaces := []EgressInterface{}
+	if err := json.Unmarshal([]byte(egressInterfacesAnnotation), &egressInterfaces); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal annotation: %s for node %q, err: %v", ovnNodeEgressInterfaces, node.Name, err)
+	}
+	for _, egressInterface := range egressInterfaces {
+		if egressInterface.Name == "" {
+			return nil, fmt.Errorf("annotation: %s for node %q has an interface without name", ovnNodeEgressInterfaces, node.Name)
+		}
+		if egressInterface.IPv4 == "" && egressInterface.IPv6 == "" {
+			return nil, fmt.Errorf("interface: %s of node %q does not have any IP information set", egressInterface.Name, node.Name)
+		}
+	}
+	return egressInterfaces, nil
+}
+
+// SetNodeEgressIPCapacity sets the maximum number of egress IPs the node can host
+func SetNodeEgressIPCapacity(nodeAnnotator kube.Annotator, capacity int) error {
+	return nodeAnnotator.Set(ovnNodeEgressIPCapacity, strconv.Itoa(capacity))
+}
+
+// ParseNodeEgressIPCapacity returns the maximum number of egress IPs the node can host
+func ParseNodeEgressIPCapacity(node *kapi.Node) (int, error) {
+	capacityAnnotation, ok := node.Annotations[ovnNodeEgressIPCapacity]
+	if !ok {
+		return 0, newAnnotationNotSetError("%s annotation not found for node %q", ovnNodeEgressIPCapacity, node.Name)
+	}
+	capacity, err := strconv.Atoi(capacityAnnotation)
+	if err != nil || capacity < 0 {
+		return 0, fmt.Errorf("annotation: %s for node %q is not a non-negative integer: %q", ovnNodeEgressIPCapacity, node.Name, capacityAnnotation)
+	}
+	return capacity, nil
+}
+
 // GetNodeEgressLabel returns label annotation needed for marking nodes as egress assignable
 func GetNodeEgressLabel() string {
 	return ovnNodeEgressLabel
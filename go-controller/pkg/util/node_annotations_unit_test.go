@@ -368,3 +368,131 @@ func TestParseNodeManagementPortMACAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNodeEgressInterfaces(t *testing.T) {
+	tests := []struct {
+		desc        string
+		inpNode     v1.Node
+		errExpected bool
+		notSet      bool
+		expOutput   []EgressInterface
+	}{
+		{
+			desc:        "egress interfaces annotation not found for node",
+			inpNode:     v1.Node{},
+			errExpected: true,
+			notSet:      true,
+		},
+		{
+			desc: "success: parse egress interfaces",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-interfaces": `[{"interface":"eth1","ipv4":"10.10.0.5/24"},{"interface":"eth2","ipv4":"10.20.0.5/24","ipv6":"fd20::5/64"}]`},
+				},
+			},
+			expOutput: []EgressInterface{
+				{Name: "eth1", IPv4: "10.10.0.5/24"},
+				{Name: "eth2", IPv4: "10.20.0.5/24", IPv6: "fd20::5/64"},
+			},
+		},
+		{
+			desc: "error: interface without IP information",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-interfaces": `[{"interface":"eth1"}]`},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			desc: "error: unmarshal error",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-interfaces": `{"interface":"eth1"`},
+				},
+			},
+			errExpected: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			egressInterfaces, e := ParseNodeEgressInterfaces(&tc.inpNode)
+			if tc.errExpected {
+				t.Log(e)
+				assert.Error(t, e)
+				assert.Equal(t, tc.notSet, IsAnnotationNotSetError(e))
+			} else {
+				assert.NoError(t, e)
+			}
+			assert.Equal(t, tc.expOutput, egressInterfaces)
+		})
+	}
+}
+
+func TestParseNodeEgressIPCapacity(t *testing.T) {
+	tests := []struct {
+		desc        string
+		inpNode     v1.Node
+		errExpected bool
+		notSet      bool
+		expOutput   int
+	}{
+		{
+			desc:        "egress IP capacity annotation not found for node",
+			inpNode:     v1.Node{},
+			errExpected: true,
+			notSet:      true,
+		},
+		{
+			desc: "success: parse egress IP capacity",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-ip-capacity": "10"},
+				},
+			},
+			expOutput: 10,
+		},
+		{
+			desc: "success: parse zero egress IP capacity",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-ip-capacity": "0"},
+				},
+			},
+			expOutput: 0,
+		},
+		{
+			desc: "error: negative egress IP capacity",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-ip-capacity": "-1"},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			desc: "error: egress IP capacity is not an integer",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/node-egress-ip-capacity": "ten"},
+				},
+			},
+			errExpected: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			capacity, e := ParseNodeEgressIPCapacity(&tc.inpNode)
+			if tc.errExpected {
+				t.Log(e)
+				assert.Error(t, e)
+				assert.Equal(t, tc.notSet, IsAnnotationNotSetError(e))
+			} else {
+				assert.NoError(t, e)
+			}
+			assert.Equal(t, tc.expOutput, capacity)
+		})
+	}
+}